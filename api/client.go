@@ -7,6 +7,7 @@
 package api
 
 import (
+    "strings"
     "sync"
 )
 
@@ -29,3 +30,23 @@ type WindowsClient struct {
 }
 
 //------------------------------------------------------------------------------
+
+//------------------------------------------------------------------------------
+
+// isConnectionError is true when the runner cannot connect to the windows-computer, f.i. while it is rebooting
+// authentication errors and host-key errors are not connection errors, these don't go away by retrying
+func isConnectionError(err error) bool {
+    if err == nil {
+        return false
+    }
+
+    message := err.Error()
+    if !strings.Contains(message, "cannot dial host") && !strings.Contains(message, "cannot open session") {
+        return false
+    }
+    if strings.Contains(message, "unable to authenticate") || strings.Contains(message, "knownhosts") {
+        return false
+    }
+
+    return true
+}
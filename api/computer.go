@@ -13,6 +13,7 @@ import (
     "encoding/json"
     "log"
     "strings"
+    "time"
 
     "github.com/stefaanc/golang-exec/runner"
     "github.com/stefaanc/golang-exec/script"
@@ -26,6 +27,11 @@ type Computer struct {
 
     DNSClient              ComputerDNSClient
 
+    DomainMembership       ComputerDomainMembership
+
+    OperatingSystem        ComputerOperatingSystem
+    Hardware               ComputerHardware
+
     RebootPending          bool
     RebootPendingDetails   ComputerRebootPendingDetails
 
@@ -34,9 +40,60 @@ type Computer struct {
 }
 
 type ComputerDNSClient struct {
-    SuffixSearchList []string
-    EnableDevolution bool
-    DevolutionLevel  uint32
+    SuffixSearchList                  []string
+    EnableDevolution                  bool
+    DevolutionLevel                   uint32
+
+    PrimarySuffix                     string
+    ChangePrimarySuffixWithMembership bool
+
+    DoHServers                        []ComputerDNSClientDoHServer
+}
+
+type ComputerDNSClientDoHServer struct {
+    ServerAddress      string
+    DoHTemplate        string
+    AllowFallbackToUDP bool
+    AutoUpgrade        bool
+}
+
+type ComputerDomainMembership struct {
+    Domain         string   // FQDN
+    Workgroup      string
+    PartOfDomain   bool
+    NetBIOSName    string   // NetBIOS name of the domain
+
+    // only used for update
+    OUPath         string
+    User           string
+    Password       string
+    UnjoinUser     string   // defaults to 'User'
+    UnjoinPassword string
+    Options        []string
+}
+
+type ComputerOperatingSystem struct {
+    Caption        string
+    Edition        string
+    Version        string
+    DisplayVersion string
+    Build          uint32
+    UBR            uint32
+    InstallDate    string
+    LastBootTime   string
+    Uptime         uint64   // seconds
+    DomainRole     string
+    SystemLocale   string
+    TimeZone       string
+}
+
+type ComputerHardware struct {
+    Manufacturer          string
+    Model                 string
+    SerialNumber          string
+    ProcessorCount        uint32
+    LogicalProcessorCount uint32
+    TotalMemory           uint64   // bytes
 }
 
 type ComputerRebootPendingDetails struct {
@@ -68,6 +125,16 @@ func (c *WindowsClient) UpdateComputer(cProperties *Computer) error {
 
 //------------------------------------------------------------------------------
 
+func (c *WindowsClient) RebootComputer(timeout time.Duration) error {
+    if c.Type == "local" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/RebootComputer(timeout)] cannot reboot a 'local' computer, it is running terraform")
+    }
+
+    return rebootComputer(c, timeout)
+}
+
+//------------------------------------------------------------------------------
+
 func readComputer(c *WindowsClient) (cProperties *Computer, err error) {
     // create buffer to capture stdout & stderr
     var stdout bytes.Buffer
@@ -90,6 +157,11 @@ func readComputer(c *WindowsClient) (cProperties *Computer, err error) {
             err = fmt.Errorf("[terraform-provider-windows/api/readComputer()] runner: %s", stderr.String())
         }
 
+        // report connection errors with a distinct message, so a rebooting computer can be waited for
+        if isConnectionError(err) {
+            err = fmt.Errorf("[terraform-provider-windows/api/readComputer()] cannot connect to computer: %s", err)
+        }
+
         return nil, err
     }
     log.Printf("[INFO][terraform-provider-windows/api/readComputer()] read computer \n%s", stdout.String())
@@ -113,6 +185,9 @@ var readComputerScript = script.New("readComputer", "powershell", `
         Name                   = $env:ComputerName
         NewName                = ( Get-ItemProperty -Path 'HKLM:\SYSTEM\CurrentControlSet\Control\ComputerName\ComputerName' -Name 'ComputerName' -ErrorAction Ignore ).ComputerName
         DNSClient              = @{}
+        DomainMembership       = @{}
+        OperatingSystem        = @{}
+        Hardware               = @{}
         RebootPending          = $false
         RebootPendingDetails   = @{
             RebootRequired        = $false
@@ -134,13 +209,77 @@ var readComputerScript = script.New("readComputer", "powershell", `
 
     $settings = Get-DnsClientGlobalSetting -ErrorAction Ignore
     if ( $settings ) {
+        $tcpipParameters = Get-ItemProperty -Path 'HKLM:\SYSTEM\CurrentControlSet\Services\Tcpip\Parameters' -ErrorAction Ignore
+
         $cProperties.DNSClient = @{
-            SuffixSearchList = $settings.SuffixSearchList
-            EnableDevolution = $settings.UseDevolution
-            DevolutionLevel  = $settings.DevolutionLevel
+            SuffixSearchList                  = $settings.SuffixSearchList
+            EnableDevolution                  = $settings.UseDevolution
+            DevolutionLevel                   = $settings.DevolutionLevel
+            PrimarySuffix                     = "$( $tcpipParameters.'NV Domain' )".ToLower()
+            ChangePrimarySuffixWithMembership = ( $tcpipParameters.SyncDomainWithMembership -ne 0 )   # defaults to 1 when the value doesn't exist
+            DoHServers                        = @()
+        }
+
+        # DNS-over-HTTPS is only supported on recent versions of windows
+        if ( Get-Command -Name 'Get-DnsClientDohServerAddress' -ErrorAction Ignore ) {
+            Get-DnsClientDohServerAddress -ErrorAction Ignore | Sort-Object -Property 'ServerAddress' | foreach {
+                $cProperties.DNSClient.DoHServers += @{
+                    ServerAddress      = $_.ServerAddress
+                    DoHTemplate        = $_.DohTemplate
+                    AllowFallbackToUDP = $_.AllowFallbackToUdp
+                    AutoUpgrade        = $_.AutoUpgrade
+                }
+            }
         }
     }
 
+    $computerSystem = Get-CimInstance -ClassName 'Win32_ComputerSystem'
+    if ( $computerSystem.PartOfDomain ) {
+        $ntDomain = Get-CimInstance -ClassName 'Win32_NTDomain' -ErrorAction 'Ignore' | where { $_.DomainName -and $_.DnsForestName -and ( $computerSystem.Domain -like "*$( $_.DnsForestName )" ) } | Select-Object -First 1
+        $cProperties.DomainMembership = @{
+            Domain       = $computerSystem.Domain.ToLower()
+            Workgroup    = ""
+            PartOfDomain = $true
+            NetBIOSName  = "$( $ntDomain.DomainName )".ToUpper()
+        }
+    }
+    else {
+        $cProperties.DomainMembership = @{
+            Domain       = ""
+            Workgroup    = $computerSystem.Workgroup.ToUpper()
+            PartOfDomain = $false
+            NetBIOSName  = ""
+        }
+    }
+
+    $operatingSystem = Get-CimInstance -ClassName 'Win32_OperatingSystem'
+    $currentVersion = Get-ItemProperty -Path 'HKLM:\SOFTWARE\Microsoft\Windows NT\CurrentVersion' -ErrorAction Ignore
+    $domainRoles = @( 'StandaloneWorkstation', 'MemberWorkstation', 'StandaloneServer', 'MemberServer', 'BackupDomainController', 'PrimaryDomainController' )
+    $cProperties.OperatingSystem = @{
+        Caption        = $operatingSystem.Caption
+        Edition        = "$( $currentVersion.EditionID )"
+        Version        = $operatingSystem.Version
+        DisplayVersion = "$( $currentVersion.DisplayVersion )"
+        Build          = [uint32]$operatingSystem.BuildNumber
+        UBR            = [uint32]$currentVersion.UBR
+        InstallDate    = $operatingSystem.InstallDate.ToUniversalTime().ToString('o')
+        LastBootTime   = $operatingSystem.LastBootUpTime.ToUniversalTime().ToString('o')
+        Uptime         = [uint64]( ( Get-Date ) - $operatingSystem.LastBootUpTime ).TotalSeconds
+        DomainRole     = $domainRoles[$computerSystem.DomainRole]
+        SystemLocale   = ( Get-WinSystemLocale ).Name
+        TimeZone       = ( Get-TimeZone ).Id
+    }
+
+    $bios = Get-CimInstance -ClassName 'Win32_BIOS' -ErrorAction Ignore
+    $cProperties.Hardware = @{
+        Manufacturer          = $computerSystem.Manufacturer
+        Model                 = $computerSystem.Model
+        SerialNumber          = "$( $bios.SerialNumber )".Trim()
+        ProcessorCount        = $computerSystem.NumberOfProcessors
+        LogicalProcessorCount = $computerSystem.NumberOfLogicalProcessors
+        TotalMemory           = $computerSystem.TotalPhysicalMemory
+    }
+
     if ( Get-Item -Path 'HKLM:\SOFTWARE\Microsoft\Windows\CurrentVersion\WindowsUpdate\Auto Update\RebootRequired' -ErrorAction Ignore ) {
         $cProperties.RebootPendingDetails.RebootRequired = $true
         $cProperties.RebootPending = $true
@@ -173,7 +312,10 @@ var readComputerScript = script.New("readComputer", "powershell", `
         $cProperties.RebootPendingDetails.UpdateExeVolatile = $true
         $cProperties.RebootPending = $true
     }
-    if ( ( Get-ItemProperty -Path 'HKLM:\SYSTEM\CurrentControlSet\Control\ComputerName\ComputerName' -Name 'ComputerName' -ErrorAction Ignore ).ComputerName -ne $cProperties.Name ) {
+    if (
+        ( ( Get-ItemProperty -Path 'HKLM:\SYSTEM\CurrentControlSet\Control\ComputerName\ComputerName' -Name 'ComputerName' -ErrorAction Ignore ).ComputerName -ne $cProperties.Name ) -or
+        ( "$( ( Get-ItemProperty -Path 'HKLM:\SYSTEM\CurrentControlSet\Services\Tcpip\Parameters' -Name 'NV Domain' -ErrorAction Ignore ).'NV Domain' )" -ne "$( ( Get-ItemProperty -Path 'HKLM:\SYSTEM\CurrentControlSet\Services\Tcpip\Parameters' -Name 'Domain' -ErrorAction Ignore ).Domain )" )
+    ) {
         $cProperties.RebootPendingDetails.ComputerRenamePending = $true
         $cProperties.RebootPending = $true
     }
@@ -209,12 +351,41 @@ var readComputerScript = script.New("readComputer", "powershell", `
 //------------------------------------------------------------------------------
 
 func updateComputer(c *WindowsClient, cProperties *Computer) error {
+    // encrypt the passwords, so they are not rendered in the script
+    properties := *cProperties
+    var key *secretKey
+    if ( properties.DomainMembership.Password != "" ) || ( properties.DomainMembership.UnjoinPassword != "" ) {
+        var err error
+        key, err = createSecretKey(c)
+        if err != nil {
+            log.Printf("[ERROR][terraform-provider-windows/api/updateComputer(cProperties)] cannot create secret key for computer\n")
+            return err
+        }
+
+        properties.DomainMembership.Password, err = key.encrypt(cProperties.DomainMembership.Password)
+        if err == nil {
+            properties.DomainMembership.UnjoinPassword, err = key.encrypt(cProperties.DomainMembership.UnjoinPassword)
+        }
+        if err != nil {
+            log.Printf("[ERROR][terraform-provider-windows/api/updateComputer(cProperties)] cannot encrypt passwords for computer\n")
+            deleteSecretKey(c, key)
+            return err
+        }
+    }
+
     // convert properties to JSON
-    cPropertiesJSON, err := json.Marshal(cProperties)
+    cPropertiesJSON, err := json.Marshal(properties)
     if err != nil {
         log.Printf("[ERROR][terraform-provider-windows/api/updateComputer(cProperties)] cannot cannot convert 'cProperties' to json for computer\n")
+        if key != nil {
+            deleteSecretKey(c, key)
+        }
         return err
     }
+    secretKeyContainer := ""
+    if key != nil {
+        secretKeyContainer = key.Container
+    }
 
     // create buffer to capture stdout & stderr
     var stdout bytes.Buffer
@@ -223,13 +394,18 @@ func updateComputer(c *WindowsClient, cProperties *Computer) error {
     // run script
     c.Lock.Lock()
     err = runner.Run(c, updateComputerScript, updateComputerArguments{
-        CPropertiesJSON: string(cPropertiesJSON),
+        CPropertiesJSON: strings.ReplaceAll(string(cPropertiesJSON), "'", "''"),   // escape for a single-quoted powershell string
+        SecretKey:       secretKeyContainer,
     }, &stdout, &stderr)
     c.Lock.Unlock()
     if err != nil {
         var runnerErr runner.Error
         errors.As(err, &runnerErr)
         log.Printf("[ERROR][terraform-provider-windows/api/updateComputer()] cannot update computer\n")
+        if key != nil {
+            // the script deletes the secret key, but it may have failed before doing so
+            deleteSecretKey(c, key)
+        }
         log.Printf("[ERROR][terraform-provider-windows/api/updateComputer()] script exitcode: %d", runnerErr.ExitCode())
         log.Printf("[ERROR][terraform-provider-windows/api/updateComputer()] script stdout: \n%s", stdout.String())
         log.Printf("[ERROR][terraform-provider-windows/api/updateComputer()] script stderr: \n%s", stderr.String())
@@ -248,6 +424,7 @@ func updateComputer(c *WindowsClient, cProperties *Computer) error {
 
 type updateComputerArguments struct{
     CPropertiesJSON string
+    SecretKey       string   // name of the key container for the encrypted passwords
 }
 
 var updateComputerScript = script.New("updateComputer", "powershell", `
@@ -278,9 +455,99 @@ var updateComputerScript = script.New("updateComputer", "powershell", `
 
     $cProperties = ConvertFrom-Json -InputObject '{{.CPropertiesJSON}}'
 
+    # the passwords are encrypted with a one-time key pair in the machine key store, so they are not rendered in this script
+    $domainPassword = ""
+    $domainUnjoinPassword = ""
+    if ( '{{.SecretKey}}' -ne "" ) {
+        $cspParameters = New-Object -TypeName 'System.Security.Cryptography.CspParameters'
+        $cspParameters.KeyContainerName = '{{.SecretKey}}'
+        $cspParameters.Flags = [System.Security.Cryptography.CspProviderFlags]::UseMachineKeyStore -bor [System.Security.Cryptography.CspProviderFlags]::UseExistingKey
+        $rsa = New-Object -TypeName 'System.Security.Cryptography.RSACryptoServiceProvider' -ArgumentList $cspParameters
+        try {
+            if ( $cProperties.DomainMembership.Password -ne "" ) {
+                $domainPassword = [System.Text.Encoding]::UTF8.GetString($rsa.Decrypt([System.Convert]::FromBase64String($cProperties.DomainMembership.Password), $true))
+            }
+            if ( $cProperties.DomainMembership.UnjoinPassword -ne "" ) {
+                $domainUnjoinPassword = [System.Text.Encoding]::UTF8.GetString($rsa.Decrypt([System.Convert]::FromBase64String($cProperties.DomainMembership.UnjoinPassword), $true))
+            }
+        }
+        finally {
+            # delete the one-time key pair
+            $rsa.PersistKeyInCsp = $false
+            $rsa.Clear()
+        }
+    }
+
     $pendingName = ( Get-ItemProperty -Path 'HKLM:\SYSTEM\CurrentControlSet\Control\ComputerName\ComputerName' -Name 'ComputerName' -ErrorAction Ignore ).ComputerName
+    $renamed = $false
+
+    $domainMembership = $cProperties.DomainMembership
+    if ( ( $domainMembership.Domain -ne "" ) -or ( $domainMembership.Workgroup -ne "" ) ) {
+        $computerSystem = Get-CimInstance -ClassName 'Win32_ComputerSystem'
+
+        $credential = $null
+        if ( $domainMembership.User -ne "" ) {
+            $password = ConvertTo-SecureString -String $domainPassword -AsPlainText -Force
+            $credential = New-Object -TypeName 'System.Management.Automation.PSCredential' -ArgumentList $domainMembership.User, $password
+        }
+        $unjoinCredential = $credential
+        if ( $domainMembership.UnjoinUser -ne "" ) {
+            $password = ConvertTo-SecureString -String $domainUnjoinPassword -AsPlainText -Force
+            $unjoinCredential = New-Object -TypeName 'System.Management.Automation.PSCredential' -ArgumentList $domainMembership.UnjoinUser, $password
+        }
+
+        # the domain can be configured using its FQDN or its NetBIOS name, both are compared case-insensitive
+        $currentDomains = @()
+        if ( $computerSystem.PartOfDomain ) {
+            $ntDomain = Get-CimInstance -ClassName 'Win32_NTDomain' -ErrorAction 'Ignore' | where { $_.DomainName -and $_.DnsForestName -and ( $computerSystem.Domain -like "*$( $_.DnsForestName )" ) } | Select-Object -First 1
+            $currentDomains = @( $computerSystem.Domain, $ntDomain.DomainName ) | where { $_ }
+        }
+
+        if ( ( $domainMembership.Domain -ne "" ) -and ( $currentDomains -notcontains $domainMembership.Domain ) ) {
+            $arguments = @{
+                DomainName = $domainMembership.Domain
+            }
+            if ( $credential ) {
+                $arguments.Credential = $credential
+            }
+            if ( $computerSystem.PartOfDomain -and $unjoinCredential ) {
+                # moving to another domain, leave the current domain
+                $arguments.UnjoinDomainCredential = $unjoinCredential
+            }
+            if ( $domainMembership.OUPath -ne "" ) {
+                $arguments.OUPath = $domainMembership.OUPath
+            }
+            $options = @( $domainMembership.Options | where { $_ -ne 'JoinWithNewName' } )
+            if ( ( $domainMembership.Options -contains 'JoinWithNewName' ) -and ( $cProperties.NewName -ne "" ) -and ( $cProperties.NewName -ne $env:ComputerName ) ) {
+                # join and rename in one go, so the computer-account in the domain gets the new computer-name
+                $arguments.NewName = $cProperties.NewName
+                $renamed = $true
+            }
+            if ( $options.Count -gt 0 ) {
+                $arguments.Options = $options -join ','
+            }
+
+            Add-Computer @arguments -Force -Confirm:$false | Out-Default
+        }
+        elseif ( ( $domainMembership.Workgroup -ne "" ) -and ( $computerSystem.PartOfDomain -or ( $computerSystem.Workgroup -ne $domainMembership.Workgroup ) ) ) {
+            if ( $computerSystem.PartOfDomain ) {
+                $arguments = @{
+                    WorkgroupName = $domainMembership.Workgroup
+                }
+                if ( $unjoinCredential ) {
+                    $arguments.UnjoinDomainCredential = $unjoinCredential
+                }
+
+                Remove-Computer @arguments -Force -Confirm:$false | Out-Default
+            }
+            else {
+                Add-Computer -WorkgroupName $domainMembership.Workgroup -Force -Confirm:$false | Out-Default
+            }
+        }
+    }
+
     # remark that $pendingName is different from the current $env:ComputerName when there is a reboot pending because of a previous computer-name change
-    if ( ( $cProperties.NewName -ne "" ) -and ( $cProperties.NewName -ne $pendingName ) ) {
+    if ( ( -not $renamed ) -and ( $cProperties.NewName -ne "" ) -and ( $cProperties.NewName -ne $pendingName ) ) {
         # Rename-Computer doesn't allow you to change the pending computer-name back to the current $env:ComputerName after a previous computer-name change - using WMI does allow this
         $returnValue = ( Invoke-WmiMethod -Name 'Rename' -Path "Win32_ComputerSystem.Name='$env:ComputerName'" -ArgumentList "$( $cProperties.NewName )" ).ReturnValue; catchExit $returnValue
     }
@@ -291,6 +558,137 @@ var updateComputerScript = script.New("updateComputer", "powershell", `
         DevolutionLevel  = $cProperties.DNSClient.DevolutionLevel
     }
     Set-DnsClientGlobalSetting @arguments -Confirm:$false | Out-Default
+
+    # the primary dns-suffix and the full computer-name only change after a reboot
+    $tcpipParameters = 'HKLM:\SYSTEM\CurrentControlSet\Services\Tcpip\Parameters'
+    if ( $cProperties.DNSClient.PrimarySuffix -ne "$( ( Get-ItemProperty -Path $tcpipParameters -Name 'NV Domain' -ErrorAction Ignore ).'NV Domain' )" ) {
+        Set-ItemProperty -Path $tcpipParameters -Name 'NV Domain' -Value $cProperties.DNSClient.PrimarySuffix
+    }
+    Set-ItemProperty -Path $tcpipParameters -Name 'SyncDomainWithMembership' -Value ( [int]$cProperties.DNSClient.ChangePrimarySuffixWithMembership ) -Type 'DWord'
+
+    $dohServers = @( $cProperties.DNSClient.DoHServers | where { $_ } )
+    if ( Get-Command -Name 'Get-DnsClientDohServerAddress' -ErrorAction Ignore ) {
+        $currentDoHServers = @( Get-DnsClientDohServerAddress -ErrorAction Ignore )
+        $currentDoHServers | where { $dohServers.ServerAddress -notcontains $_.ServerAddress } | foreach {
+            Remove-DnsClientDohServerAddress -ServerAddress $_.ServerAddress -Confirm:$false | Out-Default
+        }
+        $dohServers | foreach {
+            $arguments = @{
+                ServerAddress      = $_.ServerAddress
+                DohTemplate        = $_.DoHTemplate
+                AllowFallbackToUdp = $_.AllowFallbackToUDP
+                AutoUpgrade        = $_.AutoUpgrade
+            }
+            if ( $currentDoHServers.ServerAddress -contains $_.ServerAddress ) {
+                Set-DnsClientDohServerAddress @arguments -Confirm:$false | Out-Default
+            }
+            else {
+                Add-DnsClientDohServerAddress @arguments -Confirm:$false | Out-Default
+            }
+        }
+    }
+    elseif ( $dohServers.Count -gt 0 ) {
+        throw "cannot set 'doh_server'-properties for computer, DNS-over-HTTPS is not supported on this version of windows"
+    }
+`)
+
+//------------------------------------------------------------------------------
+
+const rebootPollInterval = 10 * time.Second
+
+func rebootComputer(c *WindowsClient, timeout time.Duration) error {
+    deadline := time.Now().Add(timeout)
+
+    // read the boot-time before the reboot, to find out when the computer is back after the reboot
+    lastBootTime, err := readComputerLastBootTime(c)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/rebootComputer()] cannot read last boot-time for computer\n")
+        return err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, rebootComputerScript, nil, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/rebootComputer()] cannot reboot computer\n")
+        log.Printf("[ERROR][terraform-provider-windows/api/rebootComputer()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/rebootComputer()] script stdout: \n%s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/rebootComputer()] script stderr: \n%s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/rebootComputer()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/rebootComputer()] rebooting computer, last boot-time %s\n", lastBootTime)
+
+    // wait for the connection to drop and to come back with a new boot-time
+    disconnected := false
+    for {
+        time.Sleep(rebootPollInterval)
+
+        bootTime, err := readComputerLastBootTime(c)
+        if err != nil {
+            if !disconnected {
+                log.Printf("[INFO][terraform-provider-windows/api/rebootComputer()] lost connection to computer\n")
+                disconnected = true
+            }
+        } else if bootTime != lastBootTime {
+            log.Printf("[INFO][terraform-provider-windows/api/rebootComputer()] rebooted computer, new boot-time %s\n", bootTime)
+            return nil
+        }
+
+        if time.Now().After(deadline) {
+            if !disconnected {
+                return fmt.Errorf("[terraform-provider-windows/api/rebootComputer()] computer didn't reboot within %s", timeout)
+            }
+            return fmt.Errorf("[terraform-provider-windows/api/rebootComputer()] computer didn't come back within %s after reboot", timeout)
+        }
+    }
+}
+
+var rebootComputerScript = script.New("rebootComputer", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    # delay the reboot, so this script can return before the connection drops
+    shutdown.exe /r /t 10 /d p:4:1 /c "terraform-provider-windows: reboot" | Out-Default
+    if ( $LASTEXITCODE -ne 0 ) {
+        throw "cannot schedule reboot, shutdown.exe failed with exitcode $LASTEXITCODE"
+    }
+`)
+
+func readComputerLastBootTime(c *WindowsClient) (lastBootTime string, err error) {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, readComputerLastBootTimeScript, nil, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        log.Printf("[INFO][terraform-provider-windows/api/readComputerLastBootTime()] cannot read last boot-time for computer: %s\n", err)
+        return "", err
+    }
+
+    return strings.TrimSpace(stdout.String()), nil
+}
+
+var readComputerLastBootTimeScript = script.New("readComputerLastBootTime", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    Write-Output $( ( Get-CimInstance -ClassName 'Win32_OperatingSystem' ).LastBootUpTime.ToUniversalTime().ToString('o') )
 `)
 
 //------------------------------------------------------------------------------
@@ -0,0 +1,255 @@
+//
+// Copyright (c) 2019 Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-windows
+//
+package api
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "encoding/json"
+    "log"
+    "strings"
+
+    "github.com/stefaanc/golang-exec/runner"
+    "github.com/stefaanc/golang-exec/script"
+)
+
+//------------------------------------------------------------------------------
+
+type FirewallProfile struct {
+    Name                  string   // "Domain", "Private" or "Public"
+
+    Enabled               bool
+    DefaultInboundAction  string   // "Allow", "Block" or "NotConfigured"
+    DefaultOutboundAction string   // "Allow", "Block" or "NotConfigured"
+    NotifyOnListen        bool
+
+    LogFileName           string
+    LogMaxSizeKilobytes   uint32   // 0 means unchanged
+    LogAllowed            bool
+    LogBlocked            bool
+
+    NotConfigured         []string   // boolean settings that are "NotConfigured", f.i. [ "NotifyOnListen", "LogAllowed" ]
+}
+
+//------------------------------------------------------------------------------
+
+func (c *WindowsClient) ReadFirewallProfile(fpQuery *FirewallProfile) (fpProperties *FirewallProfile, err error) {
+    if ( fpQuery.Name != "Domain" ) && ( fpQuery.Name != "Private" ) && ( fpQuery.Name != "Public" ) {
+        return nil, fmt.Errorf("[ERROR][terraform-provider-windows/api/ReadFirewallProfile(fpQuery)] invalid 'fpQuery.Name', must be \"Domain\", \"Private\" or \"Public\"")
+    }
+
+    return readFirewallProfile(c, fpQuery)
+}
+
+func (c *WindowsClient) UpdateFirewallProfile(fpQuery *FirewallProfile, fpProperties *FirewallProfile) error {
+    if ( fpQuery.Name != "Domain" ) && ( fpQuery.Name != "Private" ) && ( fpQuery.Name != "Public" ) {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/UpdateFirewallProfile(fpQuery)] invalid 'fpQuery.Name', must be \"Domain\", \"Private\" or \"Public\"")
+    }
+
+    return updateFirewallProfile(c, fpQuery, fpProperties)
+}
+
+//------------------------------------------------------------------------------
+
+func readFirewallProfile(c *WindowsClient, fpQuery *FirewallProfile) (fpProperties *FirewallProfile, err error) {
+    // find id
+    id := fpQuery.Name
+
+    // convert query to JSON
+    fpQueryJSON, err := json.Marshal(fpQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/readFirewallProfile()] cannot cannot convert 'fpQuery' to json for firewall_profile %#v\n", id)
+        return nil, err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, readFirewallProfileScript, readFirewallProfileArguments{
+        FPQueryJSON: string(fpQueryJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/readFirewallProfile()] cannot read firewall_profile %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/readFirewallProfile()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/readFirewallProfile()] script stdout: \n%s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/readFirewallProfile()] script stderr: \n%s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/readFirewallProfile()] runner: %s", stderr.String())
+        }
+
+        return nil, err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/readFirewallProfile()] read firewall_profile %#v \n%s", id, stdout.String())
+
+    // convert stdout-JSON to fpProperties
+    fpProperties = new(FirewallProfile)
+    err = json.Unmarshal(stdout.Bytes(), fpProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/readFirewallProfile()] cannot convert json to 'fpProperties' for firewall_profile %#v\n", id)
+        return nil, err
+    }
+
+    return fpProperties, nil
+}
+
+type readFirewallProfileArguments struct{
+    FPQueryJSON string
+}
+
+var readFirewallProfileScript = script.New("readFirewallProfile", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $fpQuery = ConvertFrom-Json -InputObject '{{.FPQueryJSON}}'
+    $name = $fpQuery.Name
+
+    $firewallProfile = Get-NetFirewallProfile -Name $name -ErrorAction 'Ignore'
+    if ( -not $firewallProfile ) {
+        throw "cannot find firewall_profile '$name'"
+    }
+
+    # prepare result, "NotConfigured" booleans are reported as false, and listed in NotConfigured so they can be restored
+    $fpProperties = @{
+        Name                  = $firewallProfile.Name
+        Enabled               = ( $firewallProfile.Enabled.ToString() -eq 'True' )
+        DefaultInboundAction  = $firewallProfile.DefaultInboundAction.ToString()
+        DefaultOutboundAction = $firewallProfile.DefaultOutboundAction.ToString()
+        NotifyOnListen        = ( $firewallProfile.NotifyOnListen.ToString() -eq 'True' )
+        LogFileName           = "$( $firewallProfile.LogFileName )"
+        LogMaxSizeKilobytes   = [uint32]$firewallProfile.LogMaxSizeKilobytes
+        LogAllowed            = ( $firewallProfile.LogAllowed.ToString() -eq 'True' )
+        LogBlocked            = ( $firewallProfile.LogBlocked.ToString() -eq 'True' )
+        NotConfigured         = @( @( 'Enabled', 'NotifyOnListen', 'LogAllowed', 'LogBlocked' ) | where { $firewallProfile.$_.ToString() -eq 'NotConfigured' } )
+    }
+
+    Write-Output $( ConvertTo-Json -InputObject $fpProperties -Depth 100 )
+`)
+
+//------------------------------------------------------------------------------
+
+func updateFirewallProfile(c *WindowsClient, fpQuery *FirewallProfile, fpProperties *FirewallProfile) error {
+    // find id
+    id := fpQuery.Name
+
+    // convert query to JSON
+    fpQueryJSON, err := json.Marshal(fpQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/updateFirewallProfile(fpQuery, fpProperties)] cannot cannot convert 'fpQuery' to json for firewall_profile %#v\n", id)
+        return err
+    }
+
+    // convert properties to JSON
+    fpPropertiesJSON, err := json.Marshal(fpProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/updateFirewallProfile(fpQuery, fpProperties)] cannot cannot convert 'fpProperties' to json for firewall_profile %#v\n", id)
+        return err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, updateFirewallProfileScript, updateFirewallProfileArguments{
+        FPQueryJSON:      string(fpQueryJSON),
+        FPPropertiesJSON: string(fpPropertiesJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/updateFirewallProfile()] cannot update firewall_profile %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/updateFirewallProfile()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/updateFirewallProfile()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/updateFirewallProfile()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/updateFirewallProfile()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/updateFirewallProfile()] updated firewall_profile %#v\n", id)
+
+    return nil
+}
+
+type updateFirewallProfileArguments struct{
+    FPQueryJSON      string
+    FPPropertiesJSON string
+}
+
+var updateFirewallProfileScript = script.New("updateFirewallProfile", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $fpQuery = ConvertFrom-Json -InputObject '{{.FPQueryJSON}}'
+    $name = $fpQuery.Name
+
+    $firewallProfile = Get-NetFirewallProfile -Name $name -ErrorAction 'Ignore'
+    if ( -not $firewallProfile ) {
+        throw "cannot find firewall_profile '$name'"
+    }
+
+    $fpProperties = ConvertFrom-Json -InputObject '{{.FPPropertiesJSON}}'
+
+    # only change what differs, so "NotConfigured" settings stay untouched when their effective value doesn't change
+    # settings listed in NotConfigured are set back to "NotConfigured", f.i. when restoring the original values
+    $notConfigured = @( $fpProperties.NotConfigured | where { $_ } )
+    function ConvertTo-GpoBoolean( $setting, $enabled ) {
+        if ( $notConfigured -contains $setting ) { 'NotConfigured' } elseif ( $enabled ) { 'True' } else { 'False' }
+    }
+    function Test-Changed( $setting, $enabled, $current ) {
+        if ( $notConfigured -contains $setting ) {
+            return ( $current.ToString() -ne 'NotConfigured' )
+        }
+        return ( $enabled -ne ( $current.ToString() -eq 'True' ) )
+    }
+
+    $arguments = @{}
+    if ( Test-Changed 'Enabled' $fpProperties.Enabled $firewallProfile.Enabled ) {
+        $arguments.Enabled = ConvertTo-GpoBoolean 'Enabled' $fpProperties.Enabled
+    }
+    if ( ( $fpProperties.DefaultInboundAction -ne "" ) -and ( $fpProperties.DefaultInboundAction -ne $firewallProfile.DefaultInboundAction.ToString() ) ) {
+        $arguments.DefaultInboundAction = $fpProperties.DefaultInboundAction
+    }
+    if ( ( $fpProperties.DefaultOutboundAction -ne "" ) -and ( $fpProperties.DefaultOutboundAction -ne $firewallProfile.DefaultOutboundAction.ToString() ) ) {
+        $arguments.DefaultOutboundAction = $fpProperties.DefaultOutboundAction
+    }
+    if ( Test-Changed 'NotifyOnListen' $fpProperties.NotifyOnListen $firewallProfile.NotifyOnListen ) {
+        $arguments.NotifyOnListen = ConvertTo-GpoBoolean 'NotifyOnListen' $fpProperties.NotifyOnListen
+    }
+    if ( ( $fpProperties.LogFileName -ne "" ) -and ( $fpProperties.LogFileName -ne $firewallProfile.LogFileName ) ) {
+        $arguments.LogFileName = $fpProperties.LogFileName
+    }
+    if ( ( $fpProperties.LogMaxSizeKilobytes -ne 0 ) -and ( $fpProperties.LogMaxSizeKilobytes -ne $firewallProfile.LogMaxSizeKilobytes ) ) {
+        $arguments.LogMaxSizeKilobytes = $fpProperties.LogMaxSizeKilobytes
+    }
+    if ( Test-Changed 'LogAllowed' $fpProperties.LogAllowed $firewallProfile.LogAllowed ) {
+        $arguments.LogAllowed = ConvertTo-GpoBoolean 'LogAllowed' $fpProperties.LogAllowed
+    }
+    if ( Test-Changed 'LogBlocked' $fpProperties.LogBlocked $firewallProfile.LogBlocked ) {
+        $arguments.LogBlocked = ConvertTo-GpoBoolean 'LogBlocked' $fpProperties.LogBlocked
+    }
+
+    if ( $arguments.Count -gt 0 ) {
+        Set-NetFirewallProfile -Name $name @arguments -Confirm:$false | Out-Default
+    }
+`)
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,459 @@
+//
+// Copyright (c) 2019 Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-windows
+//
+package api
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "encoding/json"
+    "log"
+    "strings"
+
+    "github.com/stefaanc/golang-exec/runner"
+    "github.com/stefaanc/golang-exec/script"
+)
+
+//------------------------------------------------------------------------------
+
+type FirewallRule struct {
+    Name             string     // unique identifier of the rule
+    DisplayName      string
+    Description      string
+    Group            string     // cannot be changed after creation
+
+    Enabled          bool
+    Direction        string     // "Inbound" or "Outbound"
+    Action           string     // "Allow" or "Block"
+    Profiles         []string   // "Domain", "Private" and/or "Public", empty means any profile
+
+    Protocol         string     // "Any", "TCP", "UDP", "ICMPv4", "ICMPv6" or a protocol number
+    LocalPorts       []string   // empty means any port
+    RemotePorts      []string   // empty means any port
+    LocalAddresses   []string   // empty means any address
+    RemoteAddresses  []string   // empty means any address
+
+    Program          string     // "" means any program
+    Service          string     // "" means any service
+    InterfaceAliases []string   // empty means any interface
+
+    // status
+    DisplayGroup     string
+}
+
+//------------------------------------------------------------------------------
+
+func (c *WindowsClient) CreateFirewallRule(frProperties *FirewallRule) error {
+    if frProperties.Name == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/CreateFirewallRule(frProperties)] missing 'frProperties.Name'")
+    }
+    if frProperties.DisplayName == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/CreateFirewallRule(frProperties)] missing 'frProperties.DisplayName'")
+    }
+    if ( frProperties.Direction != "Inbound" ) && ( frProperties.Direction != "Outbound" ) {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/CreateFirewallRule(frProperties)] invalid 'frProperties.Direction', must be \"Inbound\" or \"Outbound\"")
+    }
+    if ( frProperties.Action != "Allow" ) && ( frProperties.Action != "Block" ) {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/CreateFirewallRule(frProperties)] invalid 'frProperties.Action', must be \"Allow\" or \"Block\"")
+    }
+
+    return createFirewallRule(c, frProperties)
+}
+
+func (c *WindowsClient) ReadFirewallRule(frQuery *FirewallRule) (frProperties *FirewallRule, err error) {
+    if frQuery.Name == "" {
+        return nil, fmt.Errorf("[ERROR][terraform-provider-windows/api/ReadFirewallRule(frQuery)] missing 'frQuery.Name'")
+    }
+
+    return readFirewallRule(c, frQuery)
+}
+
+func (c *WindowsClient) UpdateFirewallRule(frQuery *FirewallRule, frProperties *FirewallRule) error {
+    if frQuery.Name == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/UpdateFirewallRule(frQuery)] missing 'frQuery.Name'")
+    }
+    if frProperties.DisplayName == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/UpdateFirewallRule(frProperties)] missing 'frProperties.DisplayName'")
+    }
+    if ( frProperties.Direction != "Inbound" ) && ( frProperties.Direction != "Outbound" ) {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/UpdateFirewallRule(frProperties)] invalid 'frProperties.Direction', must be \"Inbound\" or \"Outbound\"")
+    }
+    if ( frProperties.Action != "Allow" ) && ( frProperties.Action != "Block" ) {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/UpdateFirewallRule(frProperties)] invalid 'frProperties.Action', must be \"Allow\" or \"Block\"")
+    }
+
+    return updateFirewallRule(c, frQuery, frProperties)
+}
+
+func (c *WindowsClient) DeleteFirewallRule(frQuery *FirewallRule) error {
+    if frQuery.Name == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/DeleteFirewallRule(frQuery)] missing 'frQuery.Name'")
+    }
+
+    return deleteFirewallRule(c, frQuery)
+}
+
+//------------------------------------------------------------------------------
+
+func createFirewallRule(c *WindowsClient, frProperties *FirewallRule) error {
+    // find id
+    id := frProperties.Name
+
+    // convert properties to JSON
+    frPropertiesJSON, err := json.Marshal(frProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/createFirewallRule(frProperties)] cannot cannot convert 'frProperties' to json for firewall_rule %#v\n", id)
+        return err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, createFirewallRuleScript, createFirewallRuleArguments{
+        FRPropertiesJSON: string(frPropertiesJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/createFirewallRule()] cannot create firewall_rule %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/createFirewallRule()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/createFirewallRule()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/createFirewallRule()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/createFirewallRule()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/createFirewallRule()] created firewall_rule %#v\n", id)
+
+    return nil
+}
+
+type createFirewallRuleArguments struct{
+    FRPropertiesJSON string
+}
+
+var createFirewallRuleScript = script.New("createFirewallRule", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $frProperties = ConvertFrom-Json -InputObject '{{.FRPropertiesJSON}}'
+    $name = $frProperties.Name
+
+    if ( Get-NetFirewallRule -Name $name -ErrorAction 'Ignore' ) {
+        throw "cannot create firewall_rule '$name', firewall_rule already exists"
+    }
+
+    $arguments = @{
+        DisplayName    = $frProperties.DisplayName
+        Enabled        = $( if ( $frProperties.Enabled ) { 'True' } else { 'False' } )
+        Direction      = $frProperties.Direction
+        Action         = $frProperties.Action
+        Profile        = $( if ( $frProperties.Profiles.Count -gt 0 ) { $frProperties.Profiles -join ', ' } else { 'Any' } )
+        Protocol       = $( if ( $frProperties.Protocol ) { $frProperties.Protocol } else { 'Any' } )
+        LocalAddress   = $( if ( $frProperties.LocalAddresses.Count -gt 0 ) { @( $frProperties.LocalAddresses ) } else { 'Any' } )
+        RemoteAddress  = $( if ( $frProperties.RemoteAddresses.Count -gt 0 ) { @( $frProperties.RemoteAddresses ) } else { 'Any' } )
+        Program        = $( if ( $frProperties.Program ) { $frProperties.Program } else { 'Any' } )
+        Service        = $( if ( $frProperties.Service ) { $frProperties.Service } else { 'Any' } )
+        InterfaceAlias = $( if ( $frProperties.InterfaceAliases.Count -gt 0 ) { @( $frProperties.InterfaceAliases ) } else { 'Any' } )
+    }
+    if ( $frProperties.Description -ne "" ) {
+        $arguments.Description = $frProperties.Description
+    }
+    if ( $frProperties.Group -ne "" ) {
+        $arguments.Group = $frProperties.Group
+    }
+    # ports can only be filtered for TCP and UDP
+    if ( ( $frProperties.LocalPorts.Count -gt 0 ) -or ( $frProperties.RemotePorts.Count -gt 0 ) ) {
+        $arguments.LocalPort  = $( if ( $frProperties.LocalPorts.Count -gt 0 ) { @( $frProperties.LocalPorts ) } else { 'Any' } )
+        $arguments.RemotePort = $( if ( $frProperties.RemotePorts.Count -gt 0 ) { @( $frProperties.RemotePorts ) } else { 'Any' } )
+    }
+
+    New-NetFirewallRule -Name $name @arguments -Confirm:$false | Out-Null
+`)
+
+//------------------------------------------------------------------------------
+
+func readFirewallRule(c *WindowsClient, frQuery *FirewallRule) (frProperties *FirewallRule, err error) {
+    // find id
+    id := frQuery.Name
+
+    // convert query to JSON
+    frQueryJSON, err := json.Marshal(frQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/readFirewallRule()] cannot cannot convert 'frQuery' to json for firewall_rule %#v\n", id)
+        return nil, err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, readFirewallRuleScript, readFirewallRuleArguments{
+        FRQueryJSON: string(frQueryJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/readFirewallRule()] cannot read firewall_rule %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/readFirewallRule()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/readFirewallRule()] script stdout: \n%s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/readFirewallRule()] script stderr: \n%s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/readFirewallRule()] runner: %s", stderr.String())
+        }
+
+        return nil, err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/readFirewallRule()] read firewall_rule %#v \n%s", id, stdout.String())
+
+    // convert stdout-JSON to frProperties
+    frProperties = new(FirewallRule)
+    err = json.Unmarshal(stdout.Bytes(), frProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/readFirewallRule()] cannot convert json to 'frProperties' for firewall_rule %#v\n", id)
+        return nil, err
+    }
+
+    return frProperties, nil
+}
+
+type readFirewallRuleArguments struct{
+    FRQueryJSON string
+}
+
+var readFirewallRuleScript = script.New("readFirewallRule", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $frQuery = ConvertFrom-Json -InputObject '{{.FRQueryJSON}}'
+    $name = $frQuery.Name
+
+    $firewallRule = Get-NetFirewallRule -Name $name -ErrorAction 'Ignore'
+    if ( -not $firewallRule ) {
+        throw "cannot find firewall_rule '$name'"
+    }
+
+    # the settings of a rule are spread over multiple filter objects, "Any" is reported as an empty value
+    function Get-Values($values) {
+        return @( $values | where { $_ -and ( $_ -ne 'Any' ) } | foreach { "$_" } | Sort-Object )
+    }
+
+    # windows reports IPv4 subnets with a dotted mask, convert these to prefix lengths
+    function ConvertTo-PrefixLength($address) {
+        if ( $address -match '^([^/]+)/(\d+\.\d+\.\d+\.\d+)$' ) {
+            $bits = ( [System.Net.IPAddress]::Parse($Matches[2]).GetAddressBytes() | foreach { [Convert]::ToString($_, 2) } ) -join ''
+            return "$( $Matches[1] )/$( ( $bits -replace '0', '' ).Length )"
+        }
+        return $address
+    }
+
+    $portFilter        = Get-NetFirewallPortFilter -AssociatedNetFirewallRule $firewallRule
+    $addressFilter     = Get-NetFirewallAddressFilter -AssociatedNetFirewallRule $firewallRule
+    $applicationFilter = Get-NetFirewallApplicationFilter -AssociatedNetFirewallRule $firewallRule
+    $serviceFilter     = Get-NetFirewallServiceFilter -AssociatedNetFirewallRule $firewallRule
+    $interfaceFilter   = Get-NetFirewallInterfaceFilter -AssociatedNetFirewallRule $firewallRule
+
+    # prepare result
+    $frProperties = @{
+        Name             = $firewallRule.Name
+        DisplayName      = $firewallRule.DisplayName
+        Description      = "$( $firewallRule.Description )"
+        Group            = "$( $firewallRule.Group )"
+
+        Enabled          = ( $firewallRule.Enabled.ToString() -eq 'True' )
+        Direction        = $firewallRule.Direction.ToString()
+        Action           = $firewallRule.Action.ToString()
+        Profiles         = @( Get-Values ( $firewallRule.Profile.ToString() -split ', ' ) )
+
+        Protocol         = "$( $portFilter.Protocol )"
+        LocalPorts       = @( Get-Values $portFilter.LocalPort )
+        RemotePorts      = @( Get-Values $portFilter.RemotePort )
+        LocalAddresses   = @( Get-Values ( $addressFilter.LocalAddress | foreach { ConvertTo-PrefixLength $_ } ) )
+        RemoteAddresses  = @( Get-Values ( $addressFilter.RemoteAddress | foreach { ConvertTo-PrefixLength $_ } ) )
+
+        Program          = "$( @( Get-Values $applicationFilter.Program )[0] )"
+        Service          = "$( @( Get-Values $serviceFilter.Service )[0] )"
+        InterfaceAliases = @( Get-Values $interfaceFilter.InterfaceAlias )
+
+        DisplayGroup     = "$( $firewallRule.DisplayGroup )"
+    }
+
+    Write-Output $( ConvertTo-Json -InputObject $frProperties -Depth 100 )
+`)
+
+//------------------------------------------------------------------------------
+
+func updateFirewallRule(c *WindowsClient, frQuery *FirewallRule, frProperties *FirewallRule) error {
+    // find id
+    id := frQuery.Name
+
+    // convert query to JSON
+    frQueryJSON, err := json.Marshal(frQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/updateFirewallRule(frQuery, frProperties)] cannot cannot convert 'frQuery' to json for firewall_rule %#v\n", id)
+        return err
+    }
+
+    // convert properties to JSON
+    frPropertiesJSON, err := json.Marshal(frProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/updateFirewallRule(frQuery, frProperties)] cannot cannot convert 'frProperties' to json for firewall_rule %#v\n", id)
+        return err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, updateFirewallRuleScript, updateFirewallRuleArguments{
+        FRQueryJSON:      string(frQueryJSON),
+        FRPropertiesJSON: string(frPropertiesJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/updateFirewallRule()] cannot update firewall_rule %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/updateFirewallRule()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/updateFirewallRule()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/updateFirewallRule()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/updateFirewallRule()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/updateFirewallRule()] updated firewall_rule %#v\n", id)
+
+    return nil
+}
+
+type updateFirewallRuleArguments struct{
+    FRQueryJSON      string
+    FRPropertiesJSON string
+}
+
+var updateFirewallRuleScript = script.New("updateFirewallRule", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $frQuery = ConvertFrom-Json -InputObject '{{.FRQueryJSON}}'
+    $name = $frQuery.Name
+
+    $firewallRule = Get-NetFirewallRule -Name $name -ErrorAction 'Ignore'
+    if ( -not $firewallRule ) {
+        throw "cannot find firewall_rule '$name'"
+    }
+
+    $frProperties = ConvertFrom-Json -InputObject '{{.FRPropertiesJSON}}'
+
+    $arguments = @{
+        DisplayName    = $frProperties.DisplayName
+        Enabled        = $( if ( $frProperties.Enabled ) { 'True' } else { 'False' } )
+        Direction      = $frProperties.Direction
+        Action         = $frProperties.Action
+        Profile        = $( if ( $frProperties.Profiles.Count -gt 0 ) { $frProperties.Profiles -join ', ' } else { 'Any' } )
+        Protocol       = $( if ( $frProperties.Protocol ) { $frProperties.Protocol } else { 'Any' } )
+        LocalAddress   = $( if ( $frProperties.LocalAddresses.Count -gt 0 ) { @( $frProperties.LocalAddresses ) } else { 'Any' } )
+        RemoteAddress  = $( if ( $frProperties.RemoteAddresses.Count -gt 0 ) { @( $frProperties.RemoteAddresses ) } else { 'Any' } )
+        Program        = $( if ( $frProperties.Program ) { $frProperties.Program } else { 'Any' } )
+        Service        = $( if ( $frProperties.Service ) { $frProperties.Service } else { 'Any' } )
+        InterfaceAlias = $( if ( $frProperties.InterfaceAliases.Count -gt 0 ) { @( $frProperties.InterfaceAliases ) } else { 'Any' } )
+    }
+    $arguments.Description = $frProperties.Description
+
+    # ports can only be filtered for TCP and UDP, reset them together with the protocol
+    $portFilter = Get-NetFirewallPortFilter -AssociatedNetFirewallRule $firewallRule
+    $hasPorts = ( @( $portFilter.LocalPort | where { $_ -ne 'Any' } ).Count -gt 0 ) -or ( @( $portFilter.RemotePort | where { $_ -ne 'Any' } ).Count -gt 0 )
+    if ( $hasPorts -or ( $frProperties.LocalPorts.Count -gt 0 ) -or ( $frProperties.RemotePorts.Count -gt 0 ) ) {
+        $arguments.LocalPort  = $( if ( $frProperties.LocalPorts.Count -gt 0 ) { @( $frProperties.LocalPorts ) } else { 'Any' } )
+        $arguments.RemotePort = $( if ( $frProperties.RemotePorts.Count -gt 0 ) { @( $frProperties.RemotePorts ) } else { 'Any' } )
+    }
+
+    Set-NetFirewallRule -Name $name @arguments -Confirm:$false | Out-Default
+`)
+
+//------------------------------------------------------------------------------
+
+func deleteFirewallRule(c *WindowsClient, frQuery *FirewallRule) error {
+    // find id
+    id := frQuery.Name
+
+    // convert query to JSON
+    frQueryJSON, err := json.Marshal(frQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteFirewallRule(frQuery)] cannot cannot convert 'frQuery' to json for firewall_rule %#v\n", id)
+        return err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, deleteFirewallRuleScript, deleteFirewallRuleArguments{
+        FRQueryJSON: string(frQueryJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteFirewallRule()] cannot delete firewall_rule %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteFirewallRule()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteFirewallRule()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteFirewallRule()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/deleteFirewallRule()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/deleteFirewallRule()] deleted firewall_rule %#v\n", id)
+
+    return nil
+}
+
+type deleteFirewallRuleArguments struct{
+    FRQueryJSON string
+}
+
+var deleteFirewallRuleScript = script.New("deleteFirewallRule", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $frQuery = ConvertFrom-Json -InputObject '{{.FRQueryJSON}}'
+    $name = $frQuery.Name
+
+    $firewallRule = Get-NetFirewallRule -Name $name -ErrorAction 'Ignore'
+    if ( -not $firewallRule ) {
+        return
+    }
+
+    Remove-NetFirewallRule -Name $name -Confirm:$false | Out-Default
+`)
+
+//------------------------------------------------------------------------------
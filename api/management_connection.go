@@ -0,0 +1,150 @@
+//
+// Copyright (c) 2019 Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-windows
+//
+package api
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "encoding/json"
+    "log"
+    "strings"
+
+    "github.com/stefaanc/golang-exec/runner"
+    "github.com/stefaanc/golang-exec/script"
+)
+
+//------------------------------------------------------------------------------
+
+type ManagementConnection struct {
+    IPAddress              string     // local address of the ssh-connection of the provider, empty when not using ssh
+    Port                   uint16     // local port of the ssh-connection of the provider
+
+    NetworkAdapterNames    []string   // network adapters carrying the ssh-connection, including the network adapters bound to the vswitch and the members of the team
+    NetworkConnectionNames []string
+    VSwitchName            string
+
+    AllowedProfiles        []string   // firewall profiles that allow the ssh-connection - "Domain", "Private", "Public"
+}
+
+//------------------------------------------------------------------------------
+
+func (c *WindowsClient) ReadManagementConnection() (mcProperties *ManagementConnection, err error) {
+    if c.Type == "local" {
+        return new(ManagementConnection), nil
+    }
+
+    return readManagementConnection(c)
+}
+
+//------------------------------------------------------------------------------
+
+func readManagementConnection(c *WindowsClient) (mcProperties *ManagementConnection, err error) {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, readManagementConnectionScript, nil, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/readManagementConnection()] cannot read management_connection\n")
+        log.Printf("[ERROR][terraform-provider-windows/api/readManagementConnection()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/readManagementConnection()] script stdout: \n%s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/readManagementConnection()] script stderr: \n%s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/readManagementConnection()] runner: %s", stderr.String())
+        }
+
+        return nil, err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/readManagementConnection()] read management_connection \n%s", stdout.String())
+
+    // convert stdout-JSON to mcProperties
+    mcProperties = new(ManagementConnection)
+    err = json.Unmarshal(stdout.Bytes(), mcProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/readManagementConnection()] cannot convert json to 'mcProperties' for management_connection\n")
+        return nil, err
+    }
+
+    return mcProperties, nil
+}
+
+var readManagementConnectionScript = script.New("readManagementConnection", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $mcProperties = @{
+        IPAddress              = ""
+        Port                   = 0
+        NetworkAdapterNames    = @()
+        NetworkConnectionNames = @()
+        VSwitchName            = ""
+        AllowedProfiles        = @()
+    }
+
+    if ( $env:SSH_CONNECTION ) {
+        $sshAddress = ( $env:SSH_CONNECTION -split ' ' )[2]
+        $sshPort = [uint16]( $env:SSH_CONNECTION -split ' ' )[3]
+        $mcProperties.IPAddress = $sshAddress
+        $mcProperties.Port = $sshPort
+
+        $sshIPAddress = Get-NetIPAddress -IPAddress $sshAddress -ErrorAction 'Ignore' | Select-Object -First 1
+        if ( $sshIPAddress ) {
+            $sshNetworkAdapter = Get-NetAdapter -InterfaceIndex $sshIPAddress.InterfaceIndex -IncludeHidden -ErrorAction 'Ignore'
+            $networkAdapters = @( $sshNetworkAdapter | where { $_ } )
+
+            # a vnetwork adapter of the management OS depends on the network adapters bound to its vswitch
+            $sshVNetworkAdapter = Get-VMNetworkAdapter -ManagementOS -ErrorAction 'Ignore' | where { $_.DeviceID -eq $sshNetworkAdapter.DeviceID }
+            if ( $sshVNetworkAdapter ) {
+                $mcProperties.VSwitchName = $sshVNetworkAdapter.SwitchName
+                $vswitch = Get-VMSwitch -Name $sshVNetworkAdapter.SwitchName -ErrorAction 'Ignore'
+                if ( $vswitch -and ( $vswitch.SwitchType.ToString() -eq 'External' ) ) {
+                    $descriptions = @( $vswitch.NetAdapterInterfaceDescriptions | where { $_ } )
+                    if ( ( $descriptions.Count -eq 0 ) -and $vswitch.NetAdapterInterfaceDescription ) {
+                        $descriptions = @( $vswitch.NetAdapterInterfaceDescription )
+                    }
+                    $networkAdapters += @( $descriptions | foreach { Get-NetAdapter -InterfaceDescription $_ -ErrorAction 'Ignore' } )
+                }
+            }
+
+            # a team interface depends on its team members
+            foreach ( $networkAdapter in @( $networkAdapters ) ) {
+                $teamNic = Get-NetLbfoTeamNic -Name $networkAdapter.Name -ErrorAction 'Ignore'
+                if ( $teamNic ) {
+                    $networkAdapters += @( Get-NetLbfoTeamMember -Team $teamNic.Team -ErrorAction 'Ignore' | foreach { Get-NetAdapter -Name $_.Name -ErrorAction 'Ignore' } )
+                }
+            }
+
+            $mcProperties.NetworkAdapterNames = @( $networkAdapters | foreach { $_.Name } | Select-Object -Unique )
+            $mcProperties.NetworkConnectionNames = @( Get-NetConnectionProfile -InterfaceIndex $sshIPAddress.InterfaceIndex -ErrorAction 'Ignore' | foreach { $_.Name } )
+        }
+
+        # find the firewall profiles that allow the ssh-connection
+        $ruleIDs = @( Get-NetFirewallPortFilter -All -PolicyStore 'ActiveStore' -ErrorAction 'Ignore' | where { ( @( 'Any', 'TCP' ) -contains $_.Protocol ) -and ( ( $_.LocalPort -contains 'Any' ) -or ( $_.LocalPort -contains "$sshPort" ) ) } | foreach { $_.InstanceID } )
+        $rules = @( Get-NetFirewallRule -Enabled True -Direction Inbound -Action Allow -PolicyStore 'ActiveStore' -ErrorAction 'Ignore' | where { $ruleIDs -contains $_.InstanceID } )
+        foreach ( $firewallProfile in @( Get-NetFirewallProfile -PolicyStore 'ActiveStore' ) ) {
+            $profileName = $firewallProfile.Name
+            if ( ( $firewallProfile.Enabled.ToString() -eq 'False' ) -or ( $firewallProfile.DefaultInboundAction.ToString() -eq 'Allow' ) ) {
+                $mcProperties.AllowedProfiles += @( $profileName )
+            }
+            elseif ( $rules | where { ( $_.Profile.ToString() -eq 'Any' ) -or ( ( $_.Profile.ToString() -split ', ' ) -contains $profileName ) } ) {
+                $mcProperties.AllowedProfiles += @( $profileName )
+            }
+        }
+    }
+
+    Write-Output $( ConvertTo-Json -InputObject $mcProperties -Depth 100 )
+`)
+
+//------------------------------------------------------------------------------
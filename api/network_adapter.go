@@ -27,22 +27,52 @@ type NetworkAdapter struct {
     OldName             string
     NewName             string
 
+    AllowDisconnect     bool   // allow changes that cut the ssh-connection of the provider
+
     MACAddress          string
     PermanentMACAddress string
 
     DNSClient           []NetworkAdapterDNSClient
 
+    AdvancedProperties  []NetworkAdapterAdvancedProperty
+
     // status
     AdminStatus         string
     OperationalStatus   string
     ConnectionStatus    string
     ConnectionSpeed     string
     IsPhysical          bool
+    IsTeamMember        bool
+    IsTeamInterface     bool
+    TeamName            string
 }
 
 type NetworkAdapterDNSClient struct {
     RegisterConnectionAddress bool
     RegisterConnectionSuffix  string
+    ServerAddresses           []string   // static DNS servers, IPv4 before IPv6
+    ResetToDHCP               bool       // use the DNS servers provided by DHCP
+}
+
+type NetworkAdapterAdvancedProperty struct {
+    RegistryKeyword      string
+    RegistryValue        string
+    DisplayName          string
+    DisplayValue         string
+
+    // valid values, only read by ReadNetworkAdapterAdvancedProperties
+    DefaultRegistryValue string
+    ValidRegistryValues  []string
+    ValidDisplayValues   []string
+    NumericMinValue      int64
+    NumericMaxValue      int64
+    NumericStepValue     int64
+}
+
+type NetworkAdapterFilter struct {
+    Name              string   // wildcard pattern, f.i. "Ethernet*"
+    IsPhysical        *bool    // nil matches any
+    OperationalStatus string
 }
 
 //------------------------------------------------------------------------------
@@ -57,6 +87,23 @@ func (c *WindowsClient) ReadNetworkAdapter(naQuery *NetworkAdapter) (naPropertie
     return readNetworkAdapter(c, naQuery)
 }
 
+func (c *WindowsClient) ReadNetworkAdapters(naFilter *NetworkAdapterFilter) (naPropertiesList []NetworkAdapter, err error) {
+    return readNetworkAdapters(c, naFilter)
+}
+
+func (c *WindowsClient) ReadNetworkAdapterMACAddresses() (machineGUID string, macAddresses []string, err error) {
+    return readNetworkAdapterMACAddresses(c)
+}
+
+func (c *WindowsClient) ReadNetworkAdapterAdvancedProperties(naQuery *NetworkAdapter) (apPropertiesList []NetworkAdapterAdvancedProperty, err error) {
+    if ( naQuery.GUID    == "" ) &&
+       ( naQuery.Name    == "" ) {
+        return nil, fmt.Errorf("[ERROR][terraform-provider-windows/api/ReadNetworkAdapterAdvancedProperties(naQuery)] empty 'naQuery'")
+    }
+
+    return readNetworkAdapterAdvancedProperties(c, naQuery)
+}
+
 func (c *WindowsClient) UpdateNetworkAdapter(naQuery *NetworkAdapter, naProperties *NetworkAdapter) error {
     if naQuery.GUID == "" {
         return fmt.Errorf("[ERROR][terraform-provider-windows/api/UpdateNetworkAdapter(naQuery)] missing 'naQuery.GUID'")
@@ -65,6 +112,25 @@ func (c *WindowsClient) UpdateNetworkAdapter(naQuery *NetworkAdapter, naProperti
     return updateNetworkAdapter(c, naQuery, naProperties)
 }
 
+func (c *WindowsClient) CreateNetworkAdapterRollback(naQuery *NetworkAdapter, naProperties *NetworkAdapter, window uint32) error {
+    if naQuery.GUID == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/CreateNetworkAdapterRollback(naQuery)] missing 'naQuery.GUID'")
+    }
+    if window == 0 {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/CreateNetworkAdapterRollback(window)] missing 'window'")
+    }
+
+    return createNetworkAdapterRollback(c, naQuery, naProperties, window)
+}
+
+func (c *WindowsClient) DeleteNetworkAdapterRollback(naQuery *NetworkAdapter) error {
+    if naQuery.GUID == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/DeleteNetworkAdapterRollback(naQuery)] missing 'naQuery.GUID'")
+    }
+
+    return deleteNetworkAdapterRollback(c, naQuery)
+}
+
 //------------------------------------------------------------------------------
 
 func readNetworkAdapter(c *WindowsClient, naQuery *NetworkAdapter) (naProperties *NetworkAdapter, err error) {
@@ -155,11 +221,28 @@ var readNetworkAdapterScript = script.New("readNetworkAdapter", "powershell", `
         MACAddress          = $networkAdapter.MacAddress
         PermanentMACAddress = $networkAdapter.PermanentAddress -replace '..(?!$)', '$&-'
         DNSClient           = @()
+        AdvancedProperties  = @()
         AdminStatus         = $networkAdapter.AdminStatus.ToString()
         OperationalStatus   = $networkAdapter.ifOperStatus.ToString()
         ConnectionStatus    = $networkAdapter.MediaConnectionState.ToString()
         ConnectionSpeed     = $networkAdapter.LinkSpeed.ToString()
         IsPhysical          = $networkAdapter.ConnectorPresent
+        IsTeamMember        = $false
+        IsTeamInterface     = $false
+        TeamName            = ""
+    }
+
+    if ( Get-Command -Name 'Get-NetLbfoTeam' -ErrorAction 'Ignore' ) {
+        $teamMember = Get-NetLbfoTeamMember -Name $networkAdapter.Name -ErrorAction 'Ignore'
+        if ( $teamMember ) {
+            $naProperties.IsTeamMember = $true
+            $naProperties.TeamName     = $teamMember.Team
+        }
+        $teamNIC = Get-NetLbfoTeamNic -Name $networkAdapter.Name -ErrorAction 'Ignore'
+        if ( $teamNIC ) {
+            $naProperties.IsTeamInterface = $true
+            $naProperties.TeamName        = $teamNIC.Team
+        }
     }
 
     $dnsClient = Get-DNSClient -InterfaceAlias $networkAdapter.Name -ErrorAction 'Ignore'
@@ -167,11 +250,32 @@ var readNetworkAdapterScript = script.New("readNetworkAdapter", "powershell", `
         $naProperties.DNSClient += @{
             RegisterConnectionAddress = $dnsClient.RegisterThisConnectionsAddress
             RegisterConnectionSuffix  = ""
+            ServerAddresses           = @()
+            ResetToDHCP               = $true
         }
 
         if ( $dnsClient.UseSuffixWhenRegistering ) {
             $naProperties.DNSClient[0].RegisterConnectionSuffix = $dnsClient.ConnectionSpecificSuffix
         }
+
+        # only static DNS servers have a 'NameServer' in the registry, DNS servers provided by DHCP have a 'DhcpNameServer'
+        foreach ( $family in @( @{ AddressFamily = 'IPv4'; Service = 'Tcpip' }, @{ AddressFamily = 'IPv6'; Service = 'Tcpip6' } ) ) {
+            $nameServer = ( Get-ItemProperty -Path "HKLM:\SYSTEM\CurrentControlSet\Services\$( $family.Service )\Parameters\Interfaces\$( $networkAdapter.InstanceID )" -Name 'NameServer' -ErrorAction 'Ignore' ).NameServer
+            if ( $nameServer ) {
+                $serverAddresses = ( Get-DnsClientServerAddress -InterfaceIndex $networkAdapter.InterfaceIndex -AddressFamily $family.AddressFamily -ErrorAction 'Ignore' ).ServerAddresses
+                $naProperties.DNSClient[0].ServerAddresses += @( $serverAddresses )
+                $naProperties.DNSClient[0].ResetToDHCP = $false
+            }
+        }
+    }
+
+    Get-NetAdapterAdvancedProperty -Name $networkAdapter.Name -ErrorAction 'Ignore' | foreach {
+        $naProperties.AdvancedProperties += @{
+            RegistryKeyword = $_.RegistryKeyword
+            RegistryValue   = "$( $_.RegistryValue )"
+            DisplayName     = $_.DisplayName
+            DisplayValue    = "$( $_.DisplayValue )"
+        }
     }
 
     Write-Output $( ConvertTo-Json -InputObject $naProperties -Depth 100 )
@@ -179,6 +283,321 @@ var readNetworkAdapterScript = script.New("readNetworkAdapter", "powershell", `
 
 //------------------------------------------------------------------------------
 
+func readNetworkAdapters(c *WindowsClient, naFilter *NetworkAdapterFilter) (naPropertiesList []NetworkAdapter, err error) {
+    // find id
+    id := naFilter.Name
+    if id == "" {
+        id = "*"
+    }
+
+    // convert filter to JSON
+    naFilterJSON, err := json.Marshal(naFilter)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkAdapters()] cannot convert 'naFilter' to json for network_adapters %#v\n", id)
+        return nil, err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, readNetworkAdaptersScript, readNetworkAdaptersArguments{
+        NAFilterJSON: string(naFilterJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkAdapters()] cannot read network_adapters %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkAdapters()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkAdapters()] script stdout: \n%s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkAdapters()] script stderr: \n%s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/readNetworkAdapters()] runner: %s", stderr.String())
+        }
+
+        return nil, err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/readNetworkAdapters()] found network_adapters %#v \n%s", id, stdout.String())
+
+    // convert stdout-JSON to naPropertiesList
+    naPropertiesList = make([]NetworkAdapter, 0)
+    err = json.Unmarshal(stdout.Bytes(), &naPropertiesList)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkAdapters()] cannot convert json to 'naPropertiesList' for network_adapters %#v\n", id)
+        return nil, err
+    }
+
+    return naPropertiesList, nil
+}
+
+type readNetworkAdaptersArguments struct{
+    NAFilterJSON string
+}
+
+var readNetworkAdaptersScript = script.New("readNetworkAdapters", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $naFilter = ConvertFrom-Json -InputObject '{{.NAFilterJSON}}'
+    $name = $naFilter.Name
+
+    $hasLbfo = [bool]( Get-Command -Name 'Get-NetLbfoTeam' -ErrorAction 'Ignore' )
+
+    $naPropertiesList = @()
+    Get-NetAdapter -ErrorAction 'Ignore' | Sort-Object -Property 'Name' | foreach {
+        $networkAdapter = $_
+
+        if ( ( $name -ne "" ) -and ( $networkAdapter.Name -notlike $name ) ) {
+            return   # continue with next network_adapter
+        }
+        if ( ( $naFilter.IsPhysical -ne $null ) -and ( $networkAdapter.ConnectorPresent -ne $naFilter.IsPhysical ) ) {
+            return   # continue with next network_adapter
+        }
+        if ( ( $naFilter.OperationalStatus -ne "" ) -and ( $networkAdapter.ifOperStatus.ToString() -ne $naFilter.OperationalStatus ) ) {
+            return   # continue with next network_adapter
+        }
+
+        $naProperties = @{
+            GUID                = $networkAdapter.InstanceID.Trim("{}")
+            Name                = $networkAdapter.Name
+            MACAddress          = $networkAdapter.MacAddress
+            PermanentMACAddress = $networkAdapter.PermanentAddress -replace '..(?!$)', '$&-'
+            DNSClient           = @()
+            AdvancedProperties  = @()
+            AdminStatus         = $networkAdapter.AdminStatus.ToString()
+            OperationalStatus   = $networkAdapter.ifOperStatus.ToString()
+            ConnectionStatus    = $networkAdapter.MediaConnectionState.ToString()
+            ConnectionSpeed     = $networkAdapter.LinkSpeed.ToString()
+            IsPhysical          = $networkAdapter.ConnectorPresent
+            IsTeamMember        = $false
+            IsTeamInterface     = $false
+            TeamName            = ""
+        }
+
+        if ( $hasLbfo ) {
+            $teamMember = Get-NetLbfoTeamMember -Name $networkAdapter.Name -ErrorAction 'Ignore'
+            if ( $teamMember ) {
+                $naProperties.IsTeamMember = $true
+                $naProperties.TeamName     = $teamMember.Team
+            }
+            $teamNIC = Get-NetLbfoTeamNic -Name $networkAdapter.Name -ErrorAction 'Ignore'
+            if ( $teamNIC ) {
+                $naProperties.IsTeamInterface = $true
+                $naProperties.TeamName        = $teamNIC.Team
+            }
+        }
+
+        $dnsClient = Get-DNSClient -InterfaceAlias $networkAdapter.Name -ErrorAction 'Ignore'
+        if ( $dnsClient ) {
+            $naProperties.DNSClient += @{
+                RegisterConnectionAddress = $dnsClient.RegisterThisConnectionsAddress
+                RegisterConnectionSuffix  = ""
+                ServerAddresses           = @()
+                ResetToDHCP               = $true
+            }
+
+            if ( $dnsClient.UseSuffixWhenRegistering ) {
+                $naProperties.DNSClient[0].RegisterConnectionSuffix = $dnsClient.ConnectionSpecificSuffix
+            }
+
+            # only static DNS servers have a 'NameServer' in the registry, DNS servers provided by DHCP have a 'DhcpNameServer'
+            foreach ( $family in @( @{ AddressFamily = 'IPv4'; Service = 'Tcpip' }, @{ AddressFamily = 'IPv6'; Service = 'Tcpip6' } ) ) {
+                $nameServer = ( Get-ItemProperty -Path "HKLM:\SYSTEM\CurrentControlSet\Services\$( $family.Service )\Parameters\Interfaces\$( $networkAdapter.InstanceID )" -Name 'NameServer' -ErrorAction 'Ignore' ).NameServer
+                if ( $nameServer ) {
+                    $serverAddresses = ( Get-DnsClientServerAddress -InterfaceIndex $networkAdapter.InterfaceIndex -AddressFamily $family.AddressFamily -ErrorAction 'Ignore' ).ServerAddresses
+                    $naProperties.DNSClient[0].ServerAddresses += @( $serverAddresses )
+                    $naProperties.DNSClient[0].ResetToDHCP = $false
+                }
+            }
+        }
+
+        Get-NetAdapterAdvancedProperty -Name $networkAdapter.Name -ErrorAction 'Ignore' | foreach {
+            $naProperties.AdvancedProperties += @{
+                RegistryKeyword = $_.RegistryKeyword
+                RegistryValue   = "$( $_.RegistryValue )"
+                DisplayName     = $_.DisplayName
+                DisplayValue    = "$( $_.DisplayValue )"
+            }
+        }
+
+        $naPropertiesList += $naProperties
+    }
+
+    Write-Output $( ConvertTo-Json -InputObject @( $naPropertiesList ) -Depth 100 )
+`)
+
+//------------------------------------------------------------------------------
+
+func readNetworkAdapterAdvancedProperties(c *WindowsClient, naQuery *NetworkAdapter) (apPropertiesList []NetworkAdapterAdvancedProperty, err error) {
+    // find id
+    var id interface{}
+    if naQuery.GUID               != "" { id = naQuery.GUID               } else
+    if naQuery.Name               != "" { id = naQuery.Name               }
+
+    // convert query to JSON
+    naQueryJSON, err := json.Marshal(naQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkAdapterAdvancedProperties()] cannot cannot convert 'naQuery' to json for network_adapter %#v\n", id)
+        return nil, err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, readNetworkAdapterAdvancedPropertiesScript, readNetworkAdapterAdvancedPropertiesArguments{
+        NAQueryJSON: string(naQueryJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkAdapterAdvancedProperties()] cannot read advanced properties for network_adapter %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkAdapterAdvancedProperties()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkAdapterAdvancedProperties()] script stdout: \n%s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkAdapterAdvancedProperties()] script stderr: \n%s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/readNetworkAdapterAdvancedProperties()] runner: %s", stderr.String())
+        }
+
+        return nil, err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/readNetworkAdapterAdvancedProperties()] read advanced properties for network_adapter %#v \n%s", id, stdout.String())
+
+    // convert stdout-JSON to apPropertiesList
+    apPropertiesList = make([]NetworkAdapterAdvancedProperty, 0)
+    err = json.Unmarshal(stdout.Bytes(), &apPropertiesList)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkAdapterAdvancedProperties()] cannot convert json to 'apPropertiesList' for network_adapter %#v\n", id)
+        return nil, err
+    }
+
+    return apPropertiesList, nil
+}
+
+type readNetworkAdapterAdvancedPropertiesArguments struct{
+    NAQueryJSON string
+}
+
+var readNetworkAdapterAdvancedPropertiesScript = script.New("readNetworkAdapterAdvancedProperties", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $naQuery = ConvertFrom-Json -InputObject '{{.NAQueryJSON}}'
+    $guid    = $naQuery.GUID
+    $name    = $naQuery.Name
+
+    if ( $guid -ne "" ) {
+        $id = $guid
+        $networkAdapter = Get-NetAdapter -IncludeHidden -ErrorAction 'Ignore' | where { $_.InstanceID -eq "{$guid}" }
+    }
+    elseif ( $name -ne "" ) {
+        $id = $name
+        $networkAdapter = Get-NetAdapter -Name $name -ErrorAction 'Ignore'
+    }
+    if ( -not $networkAdapter ) {
+        throw "cannot find network_adapter '$id'"
+    }
+
+    # prepare result
+    $apPropertiesList = @()
+    Get-NetAdapterAdvancedProperty -Name $networkAdapter.Name -ErrorAction 'Ignore' | Sort-Object -Property 'RegistryKeyword' | foreach {
+        $apPropertiesList += @{
+            RegistryKeyword      = $_.RegistryKeyword
+            RegistryValue        = "$( $_.RegistryValue )"
+            DisplayName          = $_.DisplayName
+            DisplayValue         = "$( $_.DisplayValue )"
+            DefaultRegistryValue = "$( $_.DefaultRegistryValue )"
+            ValidRegistryValues  = @( $_.ValidRegistryValues | foreach { "$_" } )
+            ValidDisplayValues   = @( $_.ValidDisplayValues | foreach { "$_" } )
+            NumericMinValue      = [int64]$_.NumericParameterMinValue
+            NumericMaxValue      = [int64]$_.NumericParameterMaxValue
+            NumericStepValue     = [int64]$_.NumericParameterStepValue
+        }
+    }
+
+    Write-Output $( ConvertTo-Json -InputObject @( $apPropertiesList ) -Depth 100 )
+`)
+
+//------------------------------------------------------------------------------
+
+func readNetworkAdapterMACAddresses(c *WindowsClient) (machineGUID string, macAddresses []string, err error) {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, readNetworkAdapterMACAddressesScript, nil, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkAdapterMACAddresses()] cannot read mac addresses for network_adapters\n")
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkAdapterMACAddresses()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkAdapterMACAddresses()] script stdout: \n%s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkAdapterMACAddresses()] script stderr: \n%s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/readNetworkAdapterMACAddresses()] runner: %s", stderr.String())
+        }
+
+        return "", nil, err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/readNetworkAdapterMACAddresses()] read mac addresses for network_adapters \n%s", stdout.String())
+
+    // convert stdout-JSON to macAddresses
+    var result struct {
+        MachineGUID  string
+        MACAddresses []string
+    }
+    err = json.Unmarshal(stdout.Bytes(), &result)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkAdapterMACAddresses()] cannot convert json to 'macAddresses' for network_adapters\n")
+        return "", nil, err
+    }
+    if result.MACAddresses == nil {
+        result.MACAddresses = make([]string, 0)
+    }
+
+    return result.MachineGUID, result.MACAddresses, nil
+}
+
+var readNetworkAdapterMACAddressesScript = script.New("readNetworkAdapterMACAddresses", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    # the mac addresses of the network adapters on the host
+    $macAddresses = @( Get-NetAdapter -IncludeHidden -ErrorAction 'Ignore' | where { $_.MacAddress } | foreach { $_.MacAddress } )
+
+    # the mac addresses of the vnetwork adapters of the virtual machines and the management OS, when Hyper-V is installed
+    if ( Get-Command -Name 'Get-VMNetworkAdapter' -ErrorAction 'Ignore' ) {
+        $macAddresses += @( Get-VMNetworkAdapter -All -ErrorAction 'Ignore' | where { $_.MacAddress -and ( $_.MacAddress -ne '000000000000' ) } | foreach { $_.MacAddress -replace '..(?!$)', '$&-' } )
+    }
+
+    # the machine guid identifies the host, independent of the name used to connect to it
+    $machineGUID = ( Get-ItemProperty -Path 'HKLM:\SOFTWARE\Microsoft\Cryptography' -Name 'MachineGuid' ).MachineGuid
+
+    $result = @{
+        MachineGUID  = "$machineGUID".ToUpper()
+        MACAddresses = @( $macAddresses | Select-Object -Unique )
+    }
+
+    Write-Output $( ConvertTo-Json -InputObject $result -Depth 100 )
+`)
+
+//------------------------------------------------------------------------------
+
 func updateNetworkAdapter(c *WindowsClient, naQuery *NetworkAdapter, naProperties *NetworkAdapter) error {
     // find id
     id := naQuery.GUID
@@ -247,6 +666,30 @@ var updateNetworkAdapterScript = script.New("updateNetworkAdapter", "powershell"
 
     $naProperties = ConvertFrom-Json -InputObject '{{.NAPropertiesJSON}}'
 
+    if ( ( $naProperties.AdminStatus -eq "Up" ) -and ( $networkAdapter.AdminStatus.ToString() -ne "Up" ) ) {
+        Enable-NetAdapter -InputObject $networkAdapter -Confirm:$false | Out-Default
+        $networkAdapter = Get-NetAdapter -IncludeHidden -ErrorAction 'Ignore' | where { $_.InstanceID -eq "{$guid}" }
+    }
+
+    if ( ( $naProperties.AdminStatus -eq "Down" ) -and ( $networkAdapter.AdminStatus.ToString() -ne "Down" ) -and ( -not $naProperties.AllowDisconnect ) -and $env:SSH_CONNECTION ) {
+        # refuse to disable the network adapter that carries the ssh-connection of the provider, or the vswitch of the vnetwork adapter that carries it
+        $sshAddress = ( $env:SSH_CONNECTION -split ' ' )[2]
+        $sshIPAddress = Get-NetIPAddress -IPAddress $sshAddress -ErrorAction 'Ignore' | Select-Object -First 1
+        if ( $sshIPAddress ) {
+            if ( $sshIPAddress.InterfaceIndex -eq $networkAdapter.InterfaceIndex ) {
+                throw "cannot disable network_adapter '$guid', network_adapter carries the ssh-connection of the provider"
+            }
+
+            if ( Get-Command -Name 'Get-VMSwitch' -ErrorAction 'Ignore' ) {
+                $sshNetworkAdapter = Get-NetAdapter -InterfaceIndex $sshIPAddress.InterfaceIndex -IncludeHidden -ErrorAction 'Ignore'
+                $vSwitch = Get-VMSwitch -ErrorAction 'Ignore' | where { $_.NetAdapterInterfaceDescription -eq $networkAdapter.InterfaceDescription }
+                if ( $sshNetworkAdapter -and $vSwitch -and ( $sshNetworkAdapter.Name -eq "vEthernet ($( $vSwitch.Name ))" ) ) {
+                    throw "cannot disable network_adapter '$guid', network_adapter carries the vswitch '$( $vSwitch.Name )' for the ssh-connection of the provider"
+                }
+            }
+        }
+    }
+
     if ( $naProperties.DNSClient.Count -ne 0 ) {
         $dnsClient = Get-DNSClient -InterfaceIndex $networkAdapter.InterfaceIndex -ErrorAction 'Ignore'
         if ( !$dnsClient ) {
@@ -279,8 +722,98 @@ var updateNetworkAdapterScript = script.New("updateNetworkAdapter", "powershell"
         }
 
         Set-DNSClient -InterfaceAlias $networkAdapter.Name @arguments -Confirm:$false | Out-Default
+
+        if ( $naProperties.DNSClient[0].ResetToDHCP ) {
+            Set-DnsClientServerAddress -InterfaceIndex $networkAdapter.InterfaceIndex -ResetServerAddresses -Confirm:$false | Out-Default
+        }
+        elseif ( $naProperties.DNSClient[0].ServerAddresses.Count -ne 0 ) {
+            $serverAddresses = @( $naProperties.DNSClient[0].ServerAddresses )
+            Set-DnsClientServerAddress -InterfaceIndex $networkAdapter.InterfaceIndex -ServerAddresses $serverAddresses -Confirm:$false | Out-Default
+
+            # Set-DnsClientServerAddress only changes the address families that are in the list, reset the other address family
+            if ( -not ( $serverAddresses | where { $_ -notmatch ':' } ) ) {
+                netsh interface ipv4 set dnsservers name="$( $networkAdapter.InterfaceIndex )" source=dhcp | Out-Null
+            }
+            if ( -not ( $serverAddresses | where { $_ -match ':' } ) ) {
+                netsh interface ipv6 set dnsservers name="$( $networkAdapter.InterfaceIndex )" source=dhcp | Out-Null
+            }
+        }
+    }
+
+    if ( $naProperties.AdvancedProperties.Count -ne 0 ) {
+        $restart = $false
+        foreach ( $advancedProperty in $naProperties.AdvancedProperties ) {
+            $keyword = $advancedProperty.RegistryKeyword
+            $current = Get-NetAdapterAdvancedProperty -InterfaceDescription $networkAdapter.InterfaceDescription -RegistryKeyword $keyword -ErrorAction 'Ignore'
+            if ( -not $current ) {
+                throw "cannot set advanced_property '$keyword' for network_adapter '$guid', network_adapter doesn't have this advanced_property"
+            }
+            if ( $current.ValidRegistryValues -and ( @( $current.ValidRegistryValues ) -notcontains $advancedProperty.RegistryValue ) ) {
+                throw "cannot set advanced_property '$keyword' for network_adapter '$guid', valid values are '$( $current.ValidRegistryValues -join "', '" )'"
+            }
+
+            if ( "$( $current.RegistryValue )" -ne $advancedProperty.RegistryValue ) {
+                # the network adapter is restarted once, after all advanced properties are set
+                Set-NetAdapterAdvancedProperty -InterfaceDescription $networkAdapter.InterfaceDescription -RegistryKeyword $keyword -RegistryValue $advancedProperty.RegistryValue -NoRestart -Confirm:$false | Out-Default
+                $restart = $true
+            }
+        }
+
+        if ( $restart -and ( $networkAdapter.AdminStatus.ToString() -eq "Up" ) ) {
+            Write-Warning "restarting network_adapter '$guid' to apply advanced_properties"
+            Restart-NetAdapter -InterfaceDescription $networkAdapter.InterfaceDescription -Confirm:$false | Out-Default
+        }
+    }
+
+    if ( ( $naProperties.AdminStatus -eq "Down" ) -and ( $networkAdapter.AdminStatus.ToString() -ne "Down" ) ) {
+        Disable-NetAdapter -InputObject $networkAdapter -Confirm:$false | Out-Default
     }
 
 `)
 
 //------------------------------------------------------------------------------
+
+func createNetworkAdapterRollback(c *WindowsClient, naQuery *NetworkAdapter, naProperties *NetworkAdapter, window uint32) error {
+    // find id
+    id := naQuery.GUID
+
+    // convert query to JSON
+    naQueryJSON, err := json.Marshal(naQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/createNetworkAdapterRollback(naQuery, naProperties)] cannot cannot convert 'naQuery' to json for network_adapter %#v\n", id)
+        return err
+    }
+
+    // convert properties to JSON
+    naPropertiesJSON, err := json.Marshal(naProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/createNetworkAdapterRollback(naQuery, naProperties)] cannot cannot convert 'naProperties' to json for network_adapter %#v\n", id)
+        return err
+    }
+
+    // the rollback runs the update script with the original properties
+    code, err := renderRollbackCode(updateNetworkAdapterScript, updateNetworkAdapterArguments{
+        NAQueryJSON:      string(naQueryJSON),
+        NAPropertiesJSON: string(naPropertiesJSON),
+    })
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/createNetworkAdapterRollback(naQuery, naProperties)] cannot render rollback for network_adapter %#v\n", id)
+        return err
+    }
+
+    rbProperties := new(Rollback)
+    rbProperties.Name   = "network_adapter-" + naQuery.GUID
+    rbProperties.Window = window
+    rbProperties.Code   = code
+
+    return createRollback(c, rbProperties)
+}
+
+func deleteNetworkAdapterRollback(c *WindowsClient, naQuery *NetworkAdapter) error {
+    rbQuery := new(Rollback)
+    rbQuery.Name = "network_adapter-" + naQuery.GUID
+
+    return deleteRollback(c, rbQuery)
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,355 @@
+//
+// Copyright (c) 2019 Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-windows
+//
+package api
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "encoding/json"
+    "log"
+    "strings"
+
+    "github.com/stefaanc/golang-exec/runner"
+    "github.com/stefaanc/golang-exec/script"
+)
+
+//------------------------------------------------------------------------------
+
+type NetworkAdapterBinding struct {
+    NetworkAdapterGUID string
+    NetworkAdapterName string
+    ComponentID        string   // f.i. "ms_tcpip", "ms_tcpip6", "ms_server", "ms_lldp"
+
+    Enabled            bool
+    AllowDisconnect    bool   // allow changes that cut the ssh-connection of the provider
+
+    // status
+    DisplayName        string
+}
+
+//------------------------------------------------------------------------------
+
+func (c *WindowsClient) ReadNetworkAdapterBinding(nabQuery *NetworkAdapterBinding) (nabProperties *NetworkAdapterBinding, err error) {
+    if ( nabQuery.NetworkAdapterGUID == "" ) &&
+       ( nabQuery.NetworkAdapterName == "" ) {
+        return nil, fmt.Errorf("[ERROR][terraform-provider-windows/api/ReadNetworkAdapterBinding(nabQuery)] missing 'nabQuery.NetworkAdapterGUID' or 'nabQuery.NetworkAdapterName'")
+    }
+    if nabQuery.ComponentID == "" {
+        return nil, fmt.Errorf("[ERROR][terraform-provider-windows/api/ReadNetworkAdapterBinding(nabQuery)] missing 'nabQuery.ComponentID'")
+    }
+
+    return readNetworkAdapterBinding(c, nabQuery)
+}
+
+func (c *WindowsClient) ReadNetworkAdapterBindings(nabQuery *NetworkAdapterBinding) (nabPropertiesList []NetworkAdapterBinding, err error) {
+    if ( nabQuery.NetworkAdapterGUID == "" ) &&
+       ( nabQuery.NetworkAdapterName == "" ) {
+        return nil, fmt.Errorf("[ERROR][terraform-provider-windows/api/ReadNetworkAdapterBindings(nabQuery)] missing 'nabQuery.NetworkAdapterGUID' or 'nabQuery.NetworkAdapterName'")
+    }
+
+    return readNetworkAdapterBindings(c, nabQuery)
+}
+
+func (c *WindowsClient) UpdateNetworkAdapterBinding(nabQuery *NetworkAdapterBinding, nabProperties *NetworkAdapterBinding) error {
+    if nabQuery.NetworkAdapterGUID == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/UpdateNetworkAdapterBinding(nabQuery)] missing 'nabQuery.NetworkAdapterGUID'")
+    }
+    if nabQuery.ComponentID == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/UpdateNetworkAdapterBinding(nabQuery)] missing 'nabQuery.ComponentID'")
+    }
+
+    return updateNetworkAdapterBinding(c, nabQuery, nabProperties)
+}
+
+//------------------------------------------------------------------------------
+
+func readNetworkAdapterBinding(c *WindowsClient, nabQuery *NetworkAdapterBinding) (nabProperties *NetworkAdapterBinding, err error) {
+    // find id
+    var id string
+    if nabQuery.NetworkAdapterGUID != "" { id = nabQuery.NetworkAdapterGUID } else
+    if nabQuery.NetworkAdapterName != "" { id = nabQuery.NetworkAdapterName }
+    id = fmt.Sprintf("%s/%s", id, nabQuery.ComponentID)
+
+    // convert query to JSON
+    nabQueryJSON, err := json.Marshal(nabQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkAdapterBinding()] cannot cannot convert 'nabQuery' to json for network_adapter_binding %#v\n", id)
+        return nil, err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, readNetworkAdapterBindingScript, readNetworkAdapterBindingArguments{
+        NABQueryJSON: string(nabQueryJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkAdapterBinding()] cannot read network_adapter_binding %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkAdapterBinding()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkAdapterBinding()] script stdout: \n%s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkAdapterBinding()] script stderr: \n%s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/readNetworkAdapterBinding()] runner: %s", stderr.String())
+        }
+
+        return nil, err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/readNetworkAdapterBinding()] read network_adapter_binding %#v \n%s", id, stdout.String())
+
+    // convert stdout-JSON to nabProperties
+    nabProperties = new(NetworkAdapterBinding)
+    err = json.Unmarshal(stdout.Bytes(), nabProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkAdapterBinding()] cannot convert json to 'nabProperties' for network_adapter_binding %#v\n", id)
+        return nil, err
+    }
+
+    return nabProperties, nil
+}
+
+type readNetworkAdapterBindingArguments struct{
+    NABQueryJSON string
+}
+
+var readNetworkAdapterBindingScript = script.New("readNetworkAdapterBinding", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $nabQuery = ConvertFrom-Json -InputObject '{{.NABQueryJSON}}'
+    $guid = $nabQuery.NetworkAdapterGUID
+    $name = $nabQuery.NetworkAdapterName
+
+    if ( $guid -ne "" ) {
+        $id = $guid
+        $networkAdapter = Get-NetAdapter -IncludeHidden -ErrorAction 'Ignore' | where { $_.InstanceID -eq "{$guid}" }
+    }
+    elseif ( $name -ne "" ) {
+        $id = $name
+        $networkAdapter = Get-NetAdapter -Name $name -ErrorAction 'Ignore'
+    }
+    if ( -not $networkAdapter ) {
+        throw "cannot find network_adapter '$id'"
+    }
+
+    $componentID = $nabQuery.ComponentID
+    $networkAdapterBinding = Get-NetAdapterBinding -Name $networkAdapter.Name -ComponentID $componentID -IncludeHidden -AllBindings -ErrorAction 'Ignore' | Select-Object -First 1
+    if ( -not $networkAdapterBinding ) {
+        throw "cannot find network_adapter_binding '$id/$componentID'"
+    }
+
+    # prepare result
+    $nabProperties = @{
+        NetworkAdapterGUID = $networkAdapter.InstanceID.Trim("{}")
+        NetworkAdapterName = $networkAdapter.Name
+        ComponentID        = $networkAdapterBinding.ComponentID
+        Enabled            = $networkAdapterBinding.Enabled
+        DisplayName        = $networkAdapterBinding.DisplayName
+    }
+
+    Write-Output $( ConvertTo-Json -InputObject $nabProperties -Depth 100 )
+`)
+
+//------------------------------------------------------------------------------
+
+func readNetworkAdapterBindings(c *WindowsClient, nabQuery *NetworkAdapterBinding) (nabPropertiesList []NetworkAdapterBinding, err error) {
+    // find id
+    var id string
+    if nabQuery.NetworkAdapterGUID != "" { id = nabQuery.NetworkAdapterGUID } else
+    if nabQuery.NetworkAdapterName != "" { id = nabQuery.NetworkAdapterName }
+
+    // convert query to JSON
+    nabQueryJSON, err := json.Marshal(nabQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkAdapterBindings()] cannot cannot convert 'nabQuery' to json for network_adapter_bindings %#v\n", id)
+        return nil, err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, readNetworkAdapterBindingsScript, readNetworkAdapterBindingsArguments{
+        NABQueryJSON: string(nabQueryJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkAdapterBindings()] cannot read network_adapter_bindings %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkAdapterBindings()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkAdapterBindings()] script stdout: \n%s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkAdapterBindings()] script stderr: \n%s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/readNetworkAdapterBindings()] runner: %s", stderr.String())
+        }
+
+        return nil, err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/readNetworkAdapterBindings()] read network_adapter_bindings %#v \n%s", id, stdout.String())
+
+    // convert stdout-JSON to nabPropertiesList
+    nabPropertiesList = make([]NetworkAdapterBinding, 0)
+    err = json.Unmarshal(stdout.Bytes(), &nabPropertiesList)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkAdapterBindings()] cannot convert json to 'nabPropertiesList' for network_adapter_bindings %#v\n", id)
+        return nil, err
+    }
+
+    return nabPropertiesList, nil
+}
+
+type readNetworkAdapterBindingsArguments struct{
+    NABQueryJSON string
+}
+
+var readNetworkAdapterBindingsScript = script.New("readNetworkAdapterBindings", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $nabQuery = ConvertFrom-Json -InputObject '{{.NABQueryJSON}}'
+    $guid = $nabQuery.NetworkAdapterGUID
+    $name = $nabQuery.NetworkAdapterName
+
+    if ( $guid -ne "" ) {
+        $id = $guid
+        $networkAdapter = Get-NetAdapter -IncludeHidden -ErrorAction 'Ignore' | where { $_.InstanceID -eq "{$guid}" }
+    }
+    elseif ( $name -ne "" ) {
+        $id = $name
+        $networkAdapter = Get-NetAdapter -Name $name -ErrorAction 'Ignore'
+    }
+    if ( -not $networkAdapter ) {
+        throw "cannot find network_adapter '$id'"
+    }
+
+    # prepare result
+    $nabPropertiesList = @()
+    Get-NetAdapterBinding -Name $networkAdapter.Name -IncludeHidden -AllBindings -ErrorAction 'Ignore' | Sort-Object -Property 'ComponentID' | foreach {
+        $nabPropertiesList += @{
+            NetworkAdapterGUID = $networkAdapter.InstanceID.Trim("{}")
+            NetworkAdapterName = $networkAdapter.Name
+            ComponentID        = $_.ComponentID
+            Enabled            = $_.Enabled
+            DisplayName        = $_.DisplayName
+        }
+    }
+
+    Write-Output $( ConvertTo-Json -InputObject @( $nabPropertiesList ) -Depth 100 )
+`)
+
+//------------------------------------------------------------------------------
+
+func updateNetworkAdapterBinding(c *WindowsClient, nabQuery *NetworkAdapterBinding, nabProperties *NetworkAdapterBinding) error {
+    // find id
+    id := fmt.Sprintf("%s/%s", nabQuery.NetworkAdapterGUID, nabQuery.ComponentID)
+
+    // convert query to JSON
+    nabQueryJSON, err := json.Marshal(nabQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/updateNetworkAdapterBinding(nabQuery, nabProperties)] cannot cannot convert 'nabQuery' to json for network_adapter_binding %#v\n", id)
+        return err
+    }
+
+    // convert properties to JSON
+    nabPropertiesJSON, err := json.Marshal(nabProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/updateNetworkAdapterBinding(nabQuery, nabProperties)] cannot cannot convert 'nabProperties' to json for network_adapter_binding %#v\n", id)
+        return err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, updateNetworkAdapterBindingScript, updateNetworkAdapterBindingArguments{
+        NABQueryJSON:      string(nabQueryJSON),
+        NABPropertiesJSON: string(nabPropertiesJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/updateNetworkAdapterBinding()] cannot update network_adapter_binding %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/updateNetworkAdapterBinding()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/updateNetworkAdapterBinding()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/updateNetworkAdapterBinding()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/updateNetworkAdapterBinding()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/updateNetworkAdapterBinding()] updated network_adapter_binding %#v\n", id)
+
+    return nil
+}
+
+type updateNetworkAdapterBindingArguments struct{
+    NABQueryJSON      string
+    NABPropertiesJSON string
+}
+
+var updateNetworkAdapterBindingScript = script.New("updateNetworkAdapterBinding", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $nabQuery = ConvertFrom-Json -InputObject '{{.NABQueryJSON}}'
+    $guid = $nabQuery.NetworkAdapterGUID
+
+    $networkAdapter = Get-NetAdapter -IncludeHidden -ErrorAction 'Ignore' | where { $_.InstanceID -eq "{$guid}" }
+    if ( -not $networkAdapter ) {
+        throw "cannot find network_adapter '$guid'"
+    }
+
+    $componentID = $nabQuery.ComponentID
+    $networkAdapterBinding = Get-NetAdapterBinding -Name $networkAdapter.Name -ComponentID $componentID -IncludeHidden -AllBindings -ErrorAction 'Ignore' | Select-Object -First 1
+    if ( -not $networkAdapterBinding ) {
+        throw "cannot find network_adapter_binding '$guid/$componentID'"
+    }
+
+    $nabProperties = ConvertFrom-Json -InputObject '{{.NABPropertiesJSON}}'
+
+    if ( $nabProperties.Enabled -eq $networkAdapterBinding.Enabled ) {
+        return
+    }
+
+    if ( ( -not $nabProperties.Enabled ) -and ( @( 'ms_tcpip', 'ms_tcpip6' ) -contains $componentID ) -and ( -not $nabProperties.AllowDisconnect ) -and $env:SSH_CONNECTION ) {
+        # refuse to unbind the IP protocol that carries the ssh-connection of the provider
+        $sshAddress = ( $env:SSH_CONNECTION -split ' ' )[2]
+        $sshIPAddress = Get-NetIPAddress -IPAddress $sshAddress -ErrorAction 'Ignore' | Select-Object -First 1
+        $sshComponentID = if ( $sshIPAddress.AddressFamily -eq 'IPv4' ) { 'ms_tcpip' } else { 'ms_tcpip6' }
+        if ( $sshIPAddress -and ( $sshIPAddress.InterfaceIndex -eq $networkAdapter.InterfaceIndex ) -and ( $sshComponentID -eq $componentID ) ) {
+            throw "cannot disable network_adapter_binding '$guid/$componentID', network_adapter_binding carries the ssh-connection of the provider"
+        }
+    }
+
+    if ( $nabProperties.Enabled ) {
+        Enable-NetAdapterBinding -Name $networkAdapter.Name -ComponentID $componentID -IncludeHidden -AllBindings -Confirm:$false | Out-Default
+    }
+    else {
+        Disable-NetAdapterBinding -Name $networkAdapter.Name -ComponentID $componentID -IncludeHidden -AllBindings -Confirm:$false | Out-Default
+    }
+`)
+
+//------------------------------------------------------------------------------
@@ -40,6 +40,12 @@ type NetworkConnection struct {
     NetworkAdapterNames []string
 }
 
+type NetworkConnectionFilter struct {
+    Name              string   // wildcard pattern, f.i. "Network*"
+    ConnectionProfile string
+    HasGateway        *bool    // nil matches any
+}
+
 //------------------------------------------------------------------------------
 
 func (c *WindowsClient) ReadNetworkConnection(ncQuery *NetworkConnection) (ncProperties *NetworkConnection, err error) {
@@ -54,6 +60,10 @@ func (c *WindowsClient) ReadNetworkConnection(ncQuery *NetworkConnection) (ncPro
     return readNetworkConnection(c, ncQuery)
 }
 
+func (c *WindowsClient) ReadNetworkConnections(ncFilter *NetworkConnectionFilter) (ncPropertiesList []NetworkConnection, err error) {
+    return readNetworkConnections(c, ncFilter)
+}
+
 func (c *WindowsClient) UpdateNetworkConnection(ncQuery *NetworkConnection, ncProperties *NetworkConnection) error {
     if ncQuery.GUID == "" {
         return fmt.Errorf("[ERROR][terraform-provider-windows/api/UpdateNetworkConnection(ncQuery)] missing 'ncQuery.GUID'")
@@ -62,6 +72,25 @@ func (c *WindowsClient) UpdateNetworkConnection(ncQuery *NetworkConnection, ncPr
     return updateNetworkConnection(c, ncQuery, ncProperties)
 }
 
+func (c *WindowsClient) CreateNetworkConnectionRollback(ncQuery *NetworkConnection, ncProperties *NetworkConnection, window uint32) error {
+    if ncQuery.GUID == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/CreateNetworkConnectionRollback(ncQuery)] missing 'ncQuery.GUID'")
+    }
+    if window == 0 {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/CreateNetworkConnectionRollback(window)] missing 'window'")
+    }
+
+    return createNetworkConnectionRollback(c, ncQuery, ncProperties, window)
+}
+
+func (c *WindowsClient) DeleteNetworkConnectionRollback(ncQuery *NetworkConnection) error {
+    if ncQuery.GUID == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/DeleteNetworkConnectionRollback(ncQuery)] missing 'ncQuery.GUID'")
+    }
+
+    return deleteNetworkConnectionRollback(c, ncQuery)
+}
+
 //------------------------------------------------------------------------------
 
 func readNetworkConnection(c *WindowsClient, ncQuery *NetworkConnection) (ncProperties *NetworkConnection, err error) {
@@ -456,6 +485,179 @@ var readNetworkConnectionScript = script.New("readNetworkConnection", "powershel
     Write-Output $( ConvertTo-Json -InputObject $ncProperties -Depth 100 )
 `)
 
+//------------------------------------------------------------------------------
+
+func readNetworkConnections(c *WindowsClient, ncFilter *NetworkConnectionFilter) (ncPropertiesList []NetworkConnection, err error) {
+    // find id
+    id := ncFilter.Name
+    if id == "" {
+        id = "*"
+    }
+
+    // convert filter to JSON
+    ncFilterJSON, err := json.Marshal(ncFilter)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkConnections()] cannot convert 'ncFilter' to json for network_connections %#v\n", id)
+        return nil, err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, readNetworkConnectionsScript, readNetworkConnectionsArguments{
+        NCFilterJSON: string(ncFilterJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkConnections()] cannot read network_connections %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkConnections()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkConnections()] script stdout: \n%s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkConnections()] script stderr: \n%s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/readNetworkConnections()] runner: %s", stderr.String())
+        }
+
+        return nil, err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/readNetworkConnections()] found network_connections %#v \n%s", id, stdout.String())
+
+    // convert stdout-JSON to ncPropertiesList
+    ncPropertiesList = make([]NetworkConnection, 0)
+    err = json.Unmarshal(stdout.Bytes(), &ncPropertiesList)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkConnections()] cannot convert json to 'ncPropertiesList' for network_connections %#v\n", id)
+        return nil, err
+    }
+
+    return ncPropertiesList, nil
+}
+
+type readNetworkConnectionsArguments struct{
+    NCFilterJSON string
+}
+
+var readNetworkConnectionsScript = script.New("readNetworkConnections", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    function findGatewayAddress {
+        param( $gatewayRoutes, $networkConnectionProfile )
+
+        # given a list of gateway-routes for an address-family and a connection-profile
+        # build a list of interfaces for the connection-profile
+        # verify that there is only one connection-profile that matches all these interfaces, otherwise it is impossible to determine the gateway
+        # build a list of gateway-routes for these interfaces
+        # for each gateway in the list of gateway-routes
+        # build a reduced list of gateway-routes for this gateway
+        # if the reduced list contains a route for every interface then this is a candidate gateway-address for the connection-profile
+        # if there is only one candidate then this is the gateway for the connection-profile
+
+        $gatewayAddress = $null
+
+        $interfaceIndexes = $networkConnectionProfile.InterfaceIndex | Sort-Object | Get-Unique
+        $profileNames = ( Get-NetConnectionProfile -InterfaceIndex $interfaceIndexes[0] -ErrorAction 'Ignore' ).Name | Sort-Object | Get-Unique
+        $interfaceIndexes | foreach {
+            $pns = ( Get-NetConnectionProfile -InterfaceIndex $_ -ErrorAction 'Ignore' ).Name | Sort-Object | Get-Unique
+            $profileNames = $profileNames | where { $pns -contains $_ }
+        }
+
+        if ( $profileNames.Count -eq 1 ) {
+            $gatewayRoutes = $gatewayRoutes | where { $interfaceIndexes -contains $_.InterfaceIndex }
+            $gatewayRoutes.NextHop | Sort-Object | Get-Unique | foreach {
+                $nextHop = $_
+                $reduced = $gatewayRoutes | where { $_.NextHop -eq $nextHop }
+                if ( $reduced -and                                              # !!! remark that $reduced.Count doesn't work if only one item - don't know why this is !!!!!!!!!!!!!!!!!!!!!!!!
+                     ( ( $reduced -is    [array] ) -and ( $reduced.Count -eq $interfaceIndexes.Count ) ) -or
+                     ( ( $reduced -isnot [array] ) -and ( $interfaceIndexes.Count -eq 1 ) )
+                   ) {
+
+                    if ( $gatewayAddress -eq $null ) {
+                        $gatewayAddress = $nextHop
+                    }
+                    else {
+                        $gatewayAddress = ""
+                    }
+                }
+            }
+        }
+
+        $gatewayAddress
+    }
+
+    $ncFilter = ConvertFrom-Json -InputObject '{{.NCFilterJSON}}'
+    $name              = $ncFilter.Name
+    $connectionProfile = $ncFilter.ConnectionProfile
+    $hasGateway        = $ncFilter.HasGateway
+
+    # read the registry-profiles and gateway-routes once for all network_connections
+    $guids = @{}
+    Get-ChildItem -Path 'HKLM:\SOFTWARE\Microsoft\Windows NT\CurrentVersion\NetworkList\Profiles' -ErrorAction Ignore | foreach {
+        $n = ( Get-ItemProperty -Path $_.PSPath -Name 'ProfileName' ).ProfileName
+        if ( $n ) {
+            $guids[$n] = $_.PSChildName.Trim("{}")
+        }
+    }
+    $ipv4GatewayRoutes = Get-NetRoute -DestinationPrefix '0.0.0.0/0' -ErrorAction 'Ignore'
+    $ipv6GatewayRoutes = Get-NetRoute -DestinationPrefix '::/0' -ErrorAction 'Ignore'
+
+    $networkConnectionProfiles = Get-NetConnectionProfile -ErrorAction 'Ignore' | where {
+        ( ( $name -eq "" ) -or ( $_.Name -like $name ) ) -and
+        ( ( $connectionProfile -eq "" ) -or ( $_.NetworkCategory.ToString() -eq $connectionProfile ) )
+    }
+
+    $ncPropertiesList = @()
+    $networkConnectionProfiles | Group-Object -Property 'Name' | Sort-Object -Property 'Name' | foreach {
+        $networkConnectionProfile = $_.Group
+
+        $ipv4GatewayAddress = ""
+        if ( $ipv4GatewayRoutes ) {
+            $ipv4GatewayAddress = findGatewayAddress $ipv4GatewayRoutes $networkConnectionProfile[0]
+            if ( -not $ipv4GatewayAddress ) {
+                $ipv4GatewayAddress = ""
+            }
+        }
+
+        $ipv6GatewayAddress = ""
+        if ( $ipv6GatewayRoutes ) {
+            $ipv6GatewayAddress = findGatewayAddress $ipv6GatewayRoutes $networkConnectionProfile[0]
+            if ( -not $ipv6GatewayAddress ) {
+                $ipv6GatewayAddress = ""
+            }
+        }
+
+        if ( ( $hasGateway -ne $null ) -and ( ( ( $ipv4GatewayAddress -ne "" ) -or ( $ipv6GatewayAddress -ne "" ) ) -ne $hasGateway ) ) {
+            return   # continue with next network_connection
+        }
+
+        $ncProperties = @{
+            GUID                = if ( $guids.ContainsKey($networkConnectionProfile[0].Name) ) { $guids[$networkConnectionProfile[0].Name] } else { "" }
+            IPv4GatewayAddress  = $ipv4GatewayAddress
+            IPv6GatewayAddress  = $ipv6GatewayAddress
+            Name                = $networkConnectionProfile[0].Name
+            ConnectionProfile   = $networkConnectionProfile[0].NetworkCategory.ToString()
+            IPv4Connectivity    = $networkConnectionProfile[0].IPv4Connectivity.ToString()
+            IPv6Connectivity    = $networkConnectionProfile[0].IPv6Connectivity.ToString()
+            NetworkAdapterNames = @()
+        }
+
+        $networkConnectionProfile | foreach {
+            $ncProperties.NetworkAdapterNames += $_.InterfaceAlias
+        }
+
+        $ncPropertiesList += $ncProperties
+    }
+
+    Write-Output $( ConvertTo-Json -InputObject @( $ncPropertiesList ) -Depth 100 )
+`)
+
+
 //------------------------------------------------------------------------------
 
 func updateNetworkConnection(c *WindowsClient, ncQuery *NetworkConnection, ncProperties *NetworkConnection) error {
@@ -544,3 +746,48 @@ var updateNetworkConnectionScript = script.New("updateNetworkConnection", "power
 `)
 
 //------------------------------------------------------------------------------
+
+func createNetworkConnectionRollback(c *WindowsClient, ncQuery *NetworkConnection, ncProperties *NetworkConnection, window uint32) error {
+    // find id
+    id := ncQuery.GUID
+
+    // convert query to JSON
+    ncQueryJSON, err := json.Marshal(ncQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/createNetworkConnectionRollback(ncQuery, ncProperties)] cannot cannot convert 'ncQuery' to json for network_connection %#v\n", id)
+        return err
+    }
+
+    // convert properties to JSON
+    ncPropertiesJSON, err := json.Marshal(ncProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/createNetworkConnectionRollback(ncQuery, ncProperties)] cannot cannot convert 'ncProperties' to json for network_connection %#v\n", id)
+        return err
+    }
+
+    // the rollback runs the update script with the original properties
+    code, err := renderRollbackCode(updateNetworkConnectionScript, updateNetworkConnectionArguments{
+        NCQueryJSON:      string(ncQueryJSON),
+        NCPropertiesJSON: string(ncPropertiesJSON),
+    })
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/createNetworkConnectionRollback(ncQuery, ncProperties)] cannot render rollback for network_connection %#v\n", id)
+        return err
+    }
+
+    rbProperties := new(Rollback)
+    rbProperties.Name   = "network_connection-" + ncQuery.GUID
+    rbProperties.Window = window
+    rbProperties.Code   = code
+
+    return createRollback(c, rbProperties)
+}
+
+func deleteNetworkConnectionRollback(c *WindowsClient, ncQuery *NetworkConnection) error {
+    rbQuery := new(Rollback)
+    rbQuery.Name = "network_connection-" + ncQuery.GUID
+
+    return deleteRollback(c, rbQuery)
+}
+
+//------------------------------------------------------------------------------
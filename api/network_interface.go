@@ -29,10 +29,29 @@ type NetworkInterface struct {
     MACAddress             string
     NetworkAdapterName     string
     VNetworkAdapterName    string
+    IPAddress              string   // query only
+    Subnet                 string   // query only, CIDR notation
 
     NetworkConnectionNames []string
     VSwitchName            string
     ComputerName           string
+
+    IPv4Interface          []NetworkIPInterface   // empty or one element
+    IPv6Interface          []NetworkIPInterface   // empty or one element
+
+    IPv4Addresses          []NetworkInterfaceIPAddress
+    IPv6Addresses          []NetworkInterfaceIPAddress
+}
+
+type NetworkInterfaceIPAddress struct {
+    IPAddress              string
+    PrefixLength           uint8
+}
+
+type NetworkInterfaceFilter struct {
+    Alias       string   // wildcard pattern, f.i. "vEthernet*"
+    IsPhysical  *bool    // nil matches any
+    VSwitchName string
 }
 
 //------------------------------------------------------------------------------
@@ -44,13 +63,19 @@ func (c *WindowsClient) ReadNetworkInterface(niQuery *NetworkInterface) (niPrope
        niQuery.Description == "" &&
        niQuery.MACAddress == "" &&
        niQuery.NetworkAdapterName == "" &&
-       niQuery.VNetworkAdapterName == "" {
+       niQuery.VNetworkAdapterName == "" &&
+       niQuery.IPAddress == "" &&
+       niQuery.Subnet == "" {
         return nil, fmt.Errorf("[ERROR][terraform-provider-windows/api/ReadNetworkInterface(niQuery)] empty 'niQuery'")
     }
 
     return readNetworkInterface(c, niQuery)
 }
 
+func (c *WindowsClient) ReadNetworkInterfaces(niFilter *NetworkInterfaceFilter) (niPropertiesList []NetworkInterface, err error) {
+    return readNetworkInterfaces(c, niFilter)
+}
+
 //------------------------------------------------------------------------------
 
 func readNetworkInterface(c *WindowsClient, niQuery *NetworkInterface) (niProperties *NetworkInterface, err error) {
@@ -62,7 +87,9 @@ func readNetworkInterface(c *WindowsClient, niQuery *NetworkInterface) (niProper
     if niQuery.Description         != "" { id = niQuery.Description         } else
     if niQuery.MACAddress          != "" { id = niQuery.MACAddress          } else
     if niQuery.NetworkAdapterName  != "" { id = niQuery.NetworkAdapterName  } else
-    if niQuery.VNetworkAdapterName != "" { id = niQuery.VNetworkAdapterName }
+    if niQuery.VNetworkAdapterName != "" { id = niQuery.VNetworkAdapterName } else
+    if niQuery.IPAddress           != "" { id = niQuery.IPAddress           } else
+    if niQuery.Subnet              != "" { id = niQuery.Subnet              }
 
     // convert query to JSON
     niQueryJSON, err := json.Marshal(niQuery)
@@ -117,6 +144,28 @@ var readNetworkInterfaceScript = script.New("readNetworkInterface", "powershell"
     $ErrorActionPreference = 'Stop'
     $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
 
+    function isInSubnet {
+        param( $ipAddress, $subnet )
+
+        $prefix, $prefixLength = $subnet -split '/'
+        $addressBytes = ( [System.Net.IPAddress]::Parse( ( $ipAddress -replace '%.*$', '' ) ) ).GetAddressBytes()
+        $prefixBytes  = ( [System.Net.IPAddress]::Parse( $prefix ) ).GetAddressBytes()
+        if ( $addressBytes.Length -ne $prefixBytes.Length ) {
+            return $false   # different address family
+        }
+
+        $bits = [int]$prefixLength
+        for ( $i = 0; $bits -gt 0; $i++ ) {
+            $mask = if ( $bits -ge 8 ) { 0xFF } else { ( 0xFF -shl ( 8 - $bits ) ) -band 0xFF }
+            if ( ( $addressBytes[$i] -band $mask ) -ne ( $prefixBytes[$i] -band $mask ) ) {
+                return $false
+            }
+            $bits -= 8
+        }
+
+        return $true
+    }
+
     $niQuery = ConvertFrom-Json -InputObject '{{.NIQueryJSON}}'
     $guid                = $niQuery.GUID
     $index               = $niQuery.Index
@@ -125,6 +174,8 @@ var readNetworkInterfaceScript = script.New("readNetworkInterface", "powershell"
     $macAddress          = $niQuery.MACAddress
     $networkAdapterName  = $niQuery.NetworkAdapterName
     $vnetworkAdapterName = $niQuery.VNetworkAdapterName
+    $ipAddress           = $niQuery.IPAddress
+    $subnet              = $niQuery.Subnet
 
     if ( $guid -ne "" ) {
         $id = $guid
@@ -160,6 +211,23 @@ var readNetworkInterfaceScript = script.New("readNetworkInterface", "powershell"
             $networkAdapter = Get-NetAdapter -IncludeHidden | where { $_.DeviceID -eq $vnetworkAdapter.DeviceID }
         }
     }
+    elseif ( $ipAddress -ne "" ) {
+        $id = $ipAddress
+        $networkIPAddress = Get-NetIPAddress -IPAddress $ipAddress -ErrorAction 'Ignore'
+        if ( $networkIPAddress ) {
+            $networkAdapter = Get-NetAdapter -InterfaceIndex $networkIPAddress[0].InterfaceIndex -IncludeHidden -ErrorAction 'Ignore'
+        }
+    }
+    elseif ( $subnet -ne "" ) {
+        $id = $subnet
+        $interfaceIndexes = @( Get-NetIPAddress -ErrorAction 'Ignore' | where { isInSubnet $_.IPAddress $subnet } | foreach { $_.InterfaceIndex } | Sort-Object | Get-Unique )
+        if ( $interfaceIndexes.Length -eq 1 ) {
+            $networkAdapter = Get-NetAdapter -InterfaceIndex $interfaceIndexes[0] -IncludeHidden -ErrorAction 'Ignore'
+        }
+        elseif ( $interfaceIndexes.Length -gt 1 ) {
+            throw "ambiguous network_interface query '$id', multiple network_interfaces have an address in the subnet"
+        }
+    }
     if ( -not $networkAdapter ) {
         throw "cannot find network_interface '$id'"
     }
@@ -190,7 +258,206 @@ var readNetworkInterfaceScript = script.New("readNetworkInterface", "powershell"
         $niProperties.NetworkConnectionNames += $_.Name
     }
 
+    $niProperties.IPv4Interface = @()
+    $niProperties.IPv6Interface = @()
+    Get-NetIPInterface -InterfaceIndex $networkAdapter.InterfaceIndex -ErrorAction 'Ignore' | foreach {
+        $niiProperties = @{
+            AddressFamily   = $_.AddressFamily.ToString()
+            DHCP            = ( $_.Dhcp.ToString() -eq 'Enabled' )
+            AutomaticMetric = ( $_.AutomaticMetric.ToString() -eq 'Enabled' )
+            InterfaceMetric = $_.InterfaceMetric
+            NlMTU           = $_.NlMtu
+            Forwarding      = ( $_.Forwarding.ToString() -eq 'Enabled' )
+            RouterDiscovery = $_.RouterDiscovery.ToString()
+            WeakHostSend    = ( $_.WeakHostSend.ToString() -eq 'Enabled' )
+            WeakHostReceive = ( $_.WeakHostReceive.ToString() -eq 'Enabled' )
+            ConnectionState = $_.ConnectionState.ToString()
+        }
+        if ( $niiProperties.AddressFamily -eq 'IPv4' ) {
+            $niProperties.IPv4Interface = @( $niiProperties )
+        }
+        else {
+            $niProperties.IPv6Interface = @( $niiProperties )
+        }
+    }
+
+    $niProperties.IPv4Addresses = @()
+    $niProperties.IPv6Addresses = @()
+    Get-NetIPAddress -InterfaceIndex $networkAdapter.InterfaceIndex -ErrorAction 'Ignore' | Sort-Object -Property 'IPAddress' | foreach {
+        $niaProperties = @{
+            IPAddress    = $_.IPAddress -replace '%.*$', ''   # remove zone index from link-local IPv6 addresses
+            PrefixLength = $_.PrefixLength
+        }
+        if ( $_.AddressFamily.ToString() -eq 'IPv4' ) {
+            $niProperties.IPv4Addresses += $niaProperties
+        }
+        else {
+            $niProperties.IPv6Addresses += $niaProperties
+        }
+    }
+
     Write-Output $( ConvertTo-Json -InputObject $niProperties -Depth 100 )
 `)
 
+//------------------------------------------------------------------------------
+
+func readNetworkInterfaces(c *WindowsClient, niFilter *NetworkInterfaceFilter) (niPropertiesList []NetworkInterface, err error) {
+    // find id
+    id := niFilter.Alias
+    if id == "" {
+        id = "*"
+    }
+
+    // convert filter to JSON
+    niFilterJSON, err := json.Marshal(niFilter)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkInterfaces()] cannot convert 'niFilter' to json for network_interfaces %#v\n", id)
+        return nil, err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, readNetworkInterfacesScript, readNetworkInterfacesArguments{
+        NIFilterJSON: string(niFilterJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkInterfaces()] cannot read network_interfaces %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkInterfaces()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkInterfaces()] script stdout: \n%s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkInterfaces()] script stderr: \n%s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/readNetworkInterfaces()] runner: %s", stderr.String())
+        }
+
+        return nil, err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/readNetworkInterfaces()] found network_interfaces %#v \n%s", id, stdout.String())
+
+    // convert stdout-JSON to niPropertiesList
+    niPropertiesList = make([]NetworkInterface, 0)
+    err = json.Unmarshal(stdout.Bytes(), &niPropertiesList)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkInterfaces()] cannot convert json to 'niPropertiesList' for network_interfaces %#v\n", id)
+        return nil, err
+    }
+
+    return niPropertiesList, nil
+}
+
+type readNetworkInterfacesArguments struct{
+    NIFilterJSON string
+}
+
+var readNetworkInterfacesScript = script.New("readNetworkInterfaces", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $niFilter = ConvertFrom-Json -InputObject '{{.NIFilterJSON}}'
+    $alias       = $niFilter.Alias
+    $isPhysical  = $niFilter.IsPhysical
+    $vswitchName = $niFilter.VSwitchName
+
+    $networkAdapters = @( Get-NetAdapter -ErrorAction 'Ignore' | Sort-Object -Property 'InterfaceAlias' | where {
+        ( ( $alias -eq "" ) -or ( $_.InterfaceAlias -like $alias ) ) -and
+        ( ( $isPhysical -eq $null ) -or ( $_.ConnectorPresent -eq $isPhysical ) )
+    } )
+
+    # read the vnetwork-adapters, connection-profiles, ip-interfaces and ip-addresses once for all network_interfaces
+    $vnetworkAdapters = @()
+    if ( $networkAdapters | where { $_.DriverDescription -eq "Hyper-V Virtual Ethernet Adapter" } ) {
+        $vnetworkAdapters = @( Get-VMNetworkAdapter -ManagementOS )
+    }
+    $networkConnectionProfiles = @( Get-NetConnectionProfile -ErrorAction 'Ignore' )
+    $networkIPInterfaces       = @( Get-NetIPInterface -ErrorAction 'Ignore' )
+    $networkIPAddresses        = @( Get-NetIPAddress -ErrorAction 'Ignore' | Sort-Object -Property 'IPAddress' )
+
+    $niPropertiesList = @()
+    $networkAdapters | foreach {
+        $networkAdapter = $_
+
+        # find vnetwork-adapter
+        $vnetworkAdapter = $null
+        if ( $networkAdapter.DriverDescription -eq "Hyper-V Virtual Ethernet Adapter" ) {
+            $vnetworkAdapter = $vnetworkAdapters | where { $_.DeviceID -eq $networkAdapter.DeviceID }
+        }
+
+        if ( ( $vswitchName -ne "" ) -and ( -not $vnetworkAdapter -or ( $vnetworkAdapter.SwitchName -ne $vswitchName ) ) ) {
+            return   # continue with next network_interface
+        }
+
+        $niProperties = @{
+            GUID                   = $networkAdapter.InterfaceGUID.Trim("{}")
+            Index                  = $networkAdapter.InterfaceIndex
+            Alias                  = $networkAdapter.InterfaceAlias
+            Description            = $networkAdapter.InterfaceDescription
+            MACAddress             = $networkAdapter.MacAddress
+            NetworkAdapterName     = $networkAdapter.Name
+
+            NetworkConnectionNames = @()
+            ComputerName           = $networkAdapter.SystemName
+        }
+
+        if ( $vnetworkAdapter ) {
+            $niProperties.VNetworkAdapterName = $vnetworkAdapter.Name
+            $niProperties.VSwitchName         = $vnetworkAdapter.SwitchName
+        }
+
+        $networkConnectionProfiles | where { $_.InterfaceIndex -eq $networkAdapter.InterfaceIndex } | foreach {
+            $niProperties.NetworkConnectionNames += $_.Name
+        }
+
+        $niProperties.IPv4Interface = @()
+        $niProperties.IPv6Interface = @()
+        $networkIPInterfaces | where { $_.InterfaceIndex -eq $networkAdapter.InterfaceIndex } | foreach {
+            $niiProperties = @{
+                AddressFamily   = $_.AddressFamily.ToString()
+                DHCP            = ( $_.Dhcp.ToString() -eq 'Enabled' )
+                AutomaticMetric = ( $_.AutomaticMetric.ToString() -eq 'Enabled' )
+                InterfaceMetric = $_.InterfaceMetric
+                NlMTU           = $_.NlMtu
+                Forwarding      = ( $_.Forwarding.ToString() -eq 'Enabled' )
+                RouterDiscovery = $_.RouterDiscovery.ToString()
+                WeakHostSend    = ( $_.WeakHostSend.ToString() -eq 'Enabled' )
+                WeakHostReceive = ( $_.WeakHostReceive.ToString() -eq 'Enabled' )
+                ConnectionState = $_.ConnectionState.ToString()
+            }
+            if ( $niiProperties.AddressFamily -eq 'IPv4' ) {
+                $niProperties.IPv4Interface = @( $niiProperties )
+            }
+            else {
+                $niProperties.IPv6Interface = @( $niiProperties )
+            }
+        }
+
+        $niProperties.IPv4Addresses = @()
+        $niProperties.IPv6Addresses = @()
+        $networkIPAddresses | where { $_.InterfaceIndex -eq $networkAdapter.InterfaceIndex } | foreach {
+            $niaProperties = @{
+                IPAddress    = $_.IPAddress -replace '%.*$', ''   # remove zone index from link-local IPv6 addresses
+                PrefixLength = $_.PrefixLength
+            }
+            if ( $_.AddressFamily.ToString() -eq 'IPv4' ) {
+                $niProperties.IPv4Addresses += $niaProperties
+            }
+            else {
+                $niProperties.IPv6Addresses += $niaProperties
+            }
+        }
+
+        $niPropertiesList += $niProperties
+    }
+
+    Write-Output $( ConvertTo-Json -InputObject @( $niPropertiesList ) -Depth 100 )
+`)
+
+
 //------------------------------------------------------------------------------
@@ -0,0 +1,518 @@
+//
+// Copyright (c) 2019 Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-windows
+//
+package api
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "encoding/json"
+    "log"
+    "math"
+    "strings"
+
+    "github.com/stefaanc/golang-exec/runner"
+    "github.com/stefaanc/golang-exec/script"
+)
+
+//------------------------------------------------------------------------------
+
+const InfiniteLifetime uint32 = math.MaxUint32   // same as the windows IP helper API
+
+type NetworkIPAddress struct {
+    NetworkAdapterGUID string
+    NetworkAdapterName string
+
+    IPAddress          string
+    PrefixLength       uint8
+    AddressFamily      string   // "IPv4" or "IPv6"
+    SkipAsSource       bool
+    ValidLifetime      uint32   // seconds
+    PreferredLifetime  uint32   // seconds
+
+    DHCP               string   // DHCP on the IP interface for the address family: "Enabled" or "Disabled"
+
+    // status
+    PrefixOrigin       string
+    SuffixOrigin       string
+    AddressState       string
+}
+
+//------------------------------------------------------------------------------
+
+func (c *WindowsClient) CreateNetworkIPAddress(ipProperties *NetworkIPAddress) (ipOriginal *NetworkIPAddress, err error) {
+    if ( ipProperties.NetworkAdapterGUID == "" ) &&
+       ( ipProperties.NetworkAdapterName == "" ) {
+        return nil, fmt.Errorf("[ERROR][terraform-provider-windows/api/CreateNetworkIPAddress(ipProperties)] missing 'ipProperties.NetworkAdapterGUID' or 'ipProperties.NetworkAdapterName'")
+    }
+    if ipProperties.IPAddress == "" {
+        return nil, fmt.Errorf("[ERROR][terraform-provider-windows/api/CreateNetworkIPAddress(ipProperties)] missing 'ipProperties.IPAddress'")
+    }
+    if ipProperties.AddressFamily == "" {
+        return nil, fmt.Errorf("[ERROR][terraform-provider-windows/api/CreateNetworkIPAddress(ipProperties)] missing 'ipProperties.AddressFamily'")
+    }
+    if ipProperties.PreferredLifetime > ipProperties.ValidLifetime {
+        return nil, fmt.Errorf("[ERROR][terraform-provider-windows/api/CreateNetworkIPAddress(ipProperties)] 'ipProperties.PreferredLifetime' cannot be longer than 'ipProperties.ValidLifetime'")
+    }
+
+    return createNetworkIPAddress(c, ipProperties)
+}
+
+func (c *WindowsClient) ReadNetworkIPAddress(ipQuery *NetworkIPAddress) (ipProperties *NetworkIPAddress, err error) {
+    if ( ipQuery.NetworkAdapterGUID == "" ) &&
+       ( ipQuery.NetworkAdapterName == "" ) {
+        return nil, fmt.Errorf("[ERROR][terraform-provider-windows/api/ReadNetworkIPAddress(ipQuery)] missing 'ipQuery.NetworkAdapterGUID' or 'ipQuery.NetworkAdapterName'")
+    }
+    if ipQuery.IPAddress == "" {
+        return nil, fmt.Errorf("[ERROR][terraform-provider-windows/api/ReadNetworkIPAddress(ipQuery)] missing 'ipQuery.IPAddress'")
+    }
+
+    return readNetworkIPAddress(c, ipQuery)
+}
+
+func (c *WindowsClient) UpdateNetworkIPAddress(ipQuery *NetworkIPAddress, ipProperties *NetworkIPAddress) error {
+    if ipQuery.NetworkAdapterGUID == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/UpdateNetworkIPAddress(ipQuery)] missing 'ipQuery.NetworkAdapterGUID'")
+    }
+    if ipQuery.IPAddress == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/UpdateNetworkIPAddress(ipQuery)] missing 'ipQuery.IPAddress'")
+    }
+    if ipProperties.PreferredLifetime > ipProperties.ValidLifetime {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/UpdateNetworkIPAddress(ipProperties)] 'ipProperties.PreferredLifetime' cannot be longer than 'ipProperties.ValidLifetime'")
+    }
+
+    return updateNetworkIPAddress(c, ipQuery, ipProperties)
+}
+
+func (c *WindowsClient) DeleteNetworkIPAddress(ipQuery *NetworkIPAddress, ipProperties *NetworkIPAddress) error {
+    if ipQuery.NetworkAdapterGUID == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/DeleteNetworkIPAddress(ipQuery)] missing 'ipQuery.NetworkAdapterGUID'")
+    }
+    if ipQuery.IPAddress == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/DeleteNetworkIPAddress(ipQuery)] missing 'ipQuery.IPAddress'")
+    }
+
+    return deleteNetworkIPAddress(c, ipQuery, ipProperties)
+}
+
+//------------------------------------------------------------------------------
+
+func createNetworkIPAddress(c *WindowsClient, ipProperties *NetworkIPAddress) (ipOriginal *NetworkIPAddress, err error) {
+    // find id
+    id := ipProperties.IPAddress
+
+    // convert properties to JSON
+    ipPropertiesJSON, err := json.Marshal(ipProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/createNetworkIPAddress(ipProperties)] cannot cannot convert 'ipProperties' to json for network_ip_address %#v\n", id)
+        return nil, err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, createNetworkIPAddressScript, createNetworkIPAddressArguments{
+        IPPropertiesJSON: string(ipPropertiesJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/createNetworkIPAddress()] cannot create network_ip_address %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/createNetworkIPAddress()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/createNetworkIPAddress()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/createNetworkIPAddress()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/createNetworkIPAddress()] runner: %s", stderr.String())
+        }
+
+        return nil, err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/createNetworkIPAddress()] created network_ip_address %#v \n%s", id, stdout.String())
+
+    // convert stdout-JSON to ipOriginal
+    ipOriginal = new(NetworkIPAddress)
+    err = json.Unmarshal(stdout.Bytes(), ipOriginal)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/createNetworkIPAddress()] cannot convert json to 'ipOriginal' for network_ip_address %#v\n", id)
+        return nil, err
+    }
+
+    return ipOriginal, nil
+}
+
+type createNetworkIPAddressArguments struct{
+    IPPropertiesJSON string
+}
+
+var createNetworkIPAddressScript = script.New("createNetworkIPAddress", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $ipProperties = ConvertFrom-Json -InputObject '{{.IPPropertiesJSON}}'
+    $guid      = $ipProperties.NetworkAdapterGUID
+    $name      = $ipProperties.NetworkAdapterName
+    $ipAddress = $ipProperties.IPAddress
+
+    if ( $guid -ne "" ) {
+        $id = $guid
+        $networkAdapter = Get-NetAdapter -IncludeHidden -ErrorAction 'Ignore' | where { $_.InstanceID -eq "{$guid}" }
+    }
+    elseif ( $name -ne "" ) {
+        $id = $name
+        $networkAdapter = Get-NetAdapter -Name $name -ErrorAction 'Ignore'
+    }
+    if ( -not $networkAdapter ) {
+        throw "cannot find network_adapter '$id'"
+    }
+
+    $ipInterface = Get-NetIPInterface -InterfaceIndex $networkAdapter.InterfaceIndex -AddressFamily $ipProperties.AddressFamily -ErrorAction 'Ignore'
+    if ( -not $ipInterface ) {
+        throw "cannot create network_ip_address '$ipAddress', network_adapter '$id' has no $( $ipProperties.AddressFamily ) interface"
+    }
+
+    if ( Get-NetIPAddress -InterfaceIndex $networkAdapter.InterfaceIndex -IPAddress $ipAddress -ErrorAction 'Ignore' ) {
+        throw "cannot create network_ip_address '$ipAddress', network_ip_address already exists"
+    }
+
+    # prepare result
+    $ipOriginal = @{
+        DHCP = $ipInterface.Dhcp.ToString()
+    }
+
+    if ( ( $ipProperties.DHCP -ne "" ) -and ( $ipProperties.DHCP -ne $ipInterface.Dhcp.ToString() ) ) {
+        Set-NetIPInterface -InputObject $ipInterface -Dhcp $ipProperties.DHCP -Confirm:$false | Out-Default
+    }
+
+    function ConvertTo-Lifetime( [uint32]$seconds ) {
+        if ( $seconds -eq [uint32]::MaxValue ) { return [TimeSpan]::MaxValue }
+        return [TimeSpan]::FromSeconds($seconds)
+    }
+
+    $arguments = @{
+        InterfaceIndex    = $networkAdapter.InterfaceIndex
+        IPAddress         = $ipAddress
+        PrefixLength      = $ipProperties.PrefixLength
+        AddressFamily     = $ipProperties.AddressFamily
+        SkipAsSource      = $ipProperties.SkipAsSource
+        ValidLifetime     = ConvertTo-Lifetime $ipProperties.ValidLifetime
+        PreferredLifetime = ConvertTo-Lifetime $ipProperties.PreferredLifetime
+    }
+    try {
+        New-NetIPAddress @arguments -Confirm:$false | Out-Null
+    }
+    catch {
+        $createError = $_
+
+        # restore dhcp, the original value is not saved in the terraform state when the create fails
+        $ipInterface = Get-NetIPInterface -InterfaceIndex $networkAdapter.InterfaceIndex -AddressFamily $ipProperties.AddressFamily -ErrorAction 'Ignore'
+        if ( $ipInterface -and ( $ipOriginal.DHCP -ne $ipInterface.Dhcp.ToString() ) ) {
+            Set-NetIPInterface -InputObject $ipInterface -Dhcp $ipOriginal.DHCP -Confirm:$false -ErrorAction 'Continue' | Out-Default
+        }
+
+        throw $createError
+    }
+
+    Write-Output $( ConvertTo-Json -InputObject $ipOriginal -Depth 100 )
+`)
+
+//------------------------------------------------------------------------------
+
+func readNetworkIPAddress(c *WindowsClient, ipQuery *NetworkIPAddress) (ipProperties *NetworkIPAddress, err error) {
+    // find id
+    id := ipQuery.IPAddress
+
+    // convert query to JSON
+    ipQueryJSON, err := json.Marshal(ipQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkIPAddress()] cannot cannot convert 'ipQuery' to json for network_ip_address %#v\n", id)
+        return nil, err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, readNetworkIPAddressScript, readNetworkIPAddressArguments{
+        IPQueryJSON: string(ipQueryJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkIPAddress()] cannot read network_ip_address %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkIPAddress()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkIPAddress()] script stdout: \n%s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkIPAddress()] script stderr: \n%s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/readNetworkIPAddress()] runner: %s", stderr.String())
+        }
+
+        return nil, err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/readNetworkIPAddress()] read network_ip_address %#v \n%s", id, stdout.String())
+
+    // convert stdout-JSON to ipProperties
+    ipProperties = new(NetworkIPAddress)
+    err = json.Unmarshal(stdout.Bytes(), ipProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkIPAddress()] cannot convert json to 'ipProperties' for network_ip_address %#v\n", id)
+        return nil, err
+    }
+
+    return ipProperties, nil
+}
+
+type readNetworkIPAddressArguments struct{
+    IPQueryJSON string
+}
+
+var readNetworkIPAddressScript = script.New("readNetworkIPAddress", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $ipQuery = ConvertFrom-Json -InputObject '{{.IPQueryJSON}}'
+    $guid      = $ipQuery.NetworkAdapterGUID
+    $name      = $ipQuery.NetworkAdapterName
+    $ipAddress = $ipQuery.IPAddress
+
+    if ( $guid -ne "" ) {
+        $id = $guid
+        $networkAdapter = Get-NetAdapter -IncludeHidden -ErrorAction 'Ignore' | where { $_.InstanceID -eq "{$guid}" }
+    }
+    elseif ( $name -ne "" ) {
+        $id = $name
+        $networkAdapter = Get-NetAdapter -Name $name -ErrorAction 'Ignore'
+    }
+    if ( -not $networkAdapter ) {
+        throw "cannot find network_adapter '$id'"
+    }
+
+    $networkIPAddress = Get-NetIPAddress -InterfaceIndex $networkAdapter.InterfaceIndex -IPAddress $ipAddress -ErrorAction 'Ignore'
+    if ( -not $networkIPAddress ) {
+        throw "cannot find network_ip_address '$ipAddress'"
+    }
+
+    $ipInterface = Get-NetIPInterface -InterfaceIndex $networkAdapter.InterfaceIndex -AddressFamily $networkIPAddress.AddressFamily
+
+    function ConvertFrom-Lifetime( [TimeSpan]$lifetime ) {
+        if ( $lifetime -eq [TimeSpan]::MaxValue ) { return [uint32]::MaxValue }
+        return [uint32][Math]::Min( [Math]::Floor($lifetime.TotalSeconds), [uint32]::MaxValue - 1 )
+    }
+
+    # prepare result
+    $ipProperties = @{
+        NetworkAdapterGUID = $networkAdapter.InstanceID.Trim("{}")
+        NetworkAdapterName = $networkAdapter.Name
+        IPAddress          = $networkIPAddress.IPAddress
+        PrefixLength       = $networkIPAddress.PrefixLength
+        AddressFamily      = $networkIPAddress.AddressFamily.ToString()
+        SkipAsSource       = $networkIPAddress.SkipAsSource
+        ValidLifetime      = ConvertFrom-Lifetime $networkIPAddress.ValidLifetime
+        PreferredLifetime  = ConvertFrom-Lifetime $networkIPAddress.PreferredLifetime
+        DHCP               = $ipInterface.Dhcp.ToString()
+        PrefixOrigin       = $networkIPAddress.PrefixOrigin.ToString()
+        SuffixOrigin       = $networkIPAddress.SuffixOrigin.ToString()
+        AddressState       = $networkIPAddress.AddressState.ToString()
+    }
+
+    Write-Output $( ConvertTo-Json -InputObject $ipProperties -Depth 100 )
+`)
+
+//------------------------------------------------------------------------------
+
+func updateNetworkIPAddress(c *WindowsClient, ipQuery *NetworkIPAddress, ipProperties *NetworkIPAddress) error {
+    // find id
+    id := ipQuery.IPAddress
+
+    // convert query to JSON
+    ipQueryJSON, err := json.Marshal(ipQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/updateNetworkIPAddress(ipQuery, ipProperties)] cannot cannot convert 'ipQuery' to json for network_ip_address %#v\n", id)
+        return err
+    }
+
+    // convert properties to JSON
+    ipPropertiesJSON, err := json.Marshal(ipProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/updateNetworkIPAddress(ipQuery, ipProperties)] cannot cannot convert 'ipProperties' to json for network_ip_address %#v\n", id)
+        return err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, updateNetworkIPAddressScript, updateNetworkIPAddressArguments{
+        IPQueryJSON:      string(ipQueryJSON),
+        IPPropertiesJSON: string(ipPropertiesJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/updateNetworkIPAddress()] cannot update network_ip_address %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/updateNetworkIPAddress()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/updateNetworkIPAddress()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/updateNetworkIPAddress()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/updateNetworkIPAddress()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/updateNetworkIPAddress()] updated network_ip_address %#v\n", id)
+
+    return nil
+}
+
+type updateNetworkIPAddressArguments struct{
+    IPQueryJSON      string
+    IPPropertiesJSON string
+}
+
+var updateNetworkIPAddressScript = script.New("updateNetworkIPAddress", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $ipQuery = ConvertFrom-Json -InputObject '{{.IPQueryJSON}}'
+    $guid      = $ipQuery.NetworkAdapterGUID
+    $ipAddress = $ipQuery.IPAddress
+
+    $networkAdapter = Get-NetAdapter -IncludeHidden -ErrorAction 'Ignore' | where { $_.InstanceID -eq "{$guid}" }
+    if ( -not $networkAdapter ) {
+        throw "cannot find network_adapter '$guid'"
+    }
+
+    $networkIPAddress = Get-NetIPAddress -InterfaceIndex $networkAdapter.InterfaceIndex -IPAddress $ipAddress -ErrorAction 'Ignore'
+    if ( -not $networkIPAddress ) {
+        throw "cannot find network_ip_address '$ipAddress'"
+    }
+
+    $ipProperties = ConvertFrom-Json -InputObject '{{.IPPropertiesJSON}}'
+
+    $ipInterface = Get-NetIPInterface -InterfaceIndex $networkAdapter.InterfaceIndex -AddressFamily $networkIPAddress.AddressFamily
+    if ( ( $ipProperties.DHCP -ne "" ) -and ( $ipProperties.DHCP -ne $ipInterface.Dhcp.ToString() ) ) {
+        Set-NetIPInterface -InputObject $ipInterface -Dhcp $ipProperties.DHCP -Confirm:$false | Out-Default
+    }
+
+    function ConvertTo-Lifetime( [uint32]$seconds ) {
+        if ( $seconds -eq [uint32]::MaxValue ) { return [TimeSpan]::MaxValue }
+        return [TimeSpan]::FromSeconds($seconds)
+    }
+
+    $arguments = @{
+        InterfaceIndex    = $networkAdapter.InterfaceIndex
+        IPAddress         = $ipAddress
+        PrefixLength      = $ipProperties.PrefixLength
+        SkipAsSource      = $ipProperties.SkipAsSource
+        ValidLifetime     = ConvertTo-Lifetime $ipProperties.ValidLifetime
+        PreferredLifetime = ConvertTo-Lifetime $ipProperties.PreferredLifetime
+    }
+    Set-NetIPAddress @arguments -Confirm:$false | Out-Default
+`)
+
+//------------------------------------------------------------------------------
+
+func deleteNetworkIPAddress(c *WindowsClient, ipQuery *NetworkIPAddress, ipProperties *NetworkIPAddress) error {
+    // find id
+    id := ipQuery.IPAddress
+
+    // convert query to JSON
+    ipQueryJSON, err := json.Marshal(ipQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteNetworkIPAddress(ipQuery, ipProperties)] cannot cannot convert 'ipQuery' to json for network_ip_address %#v\n", id)
+        return err
+    }
+
+    // convert properties to JSON
+    ipPropertiesJSON, err := json.Marshal(ipProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteNetworkIPAddress(ipQuery, ipProperties)] cannot cannot convert 'ipProperties' to json for network_ip_address %#v\n", id)
+        return err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, deleteNetworkIPAddressScript, deleteNetworkIPAddressArguments{
+        IPQueryJSON:      string(ipQueryJSON),
+        IPPropertiesJSON: string(ipPropertiesJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteNetworkIPAddress()] cannot delete network_ip_address %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteNetworkIPAddress()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteNetworkIPAddress()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteNetworkIPAddress()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/deleteNetworkIPAddress()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/deleteNetworkIPAddress()] deleted network_ip_address %#v\n", id)
+
+    return nil
+}
+
+type deleteNetworkIPAddressArguments struct{
+    IPQueryJSON      string
+    IPPropertiesJSON string
+}
+
+var deleteNetworkIPAddressScript = script.New("deleteNetworkIPAddress", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $ipQuery = ConvertFrom-Json -InputObject '{{.IPQueryJSON}}'
+    $guid      = $ipQuery.NetworkAdapterGUID
+    $ipAddress = $ipQuery.IPAddress
+
+    $networkAdapter = Get-NetAdapter -IncludeHidden -ErrorAction 'Ignore' | where { $_.InstanceID -eq "{$guid}" }
+    if ( -not $networkAdapter ) {
+        throw "cannot find network_adapter '$guid'"
+    }
+
+    $ipProperties = ConvertFrom-Json -InputObject '{{.IPPropertiesJSON}}'
+
+    $networkIPAddress = Get-NetIPAddress -InterfaceIndex $networkAdapter.InterfaceIndex -IPAddress $ipAddress -ErrorAction 'Ignore'
+    if ( $networkIPAddress ) {
+        Remove-NetIPAddress -InterfaceIndex $networkAdapter.InterfaceIndex -IPAddress $ipAddress -Confirm:$false | Out-Default
+    }
+
+    # only re-enable DHCP when no other static addresses are left on the interface, f.i. managed by other resources
+    if ( $ipProperties.DHCP -eq "Enabled" ) {
+        $addressFamily = $ipProperties.AddressFamily
+        $staticIPAddresses = Get-NetIPAddress -InterfaceIndex $networkAdapter.InterfaceIndex -AddressFamily $addressFamily -PrefixOrigin 'Manual' -ErrorAction 'Ignore'
+        if ( -not $staticIPAddresses ) {
+            Set-NetIPInterface -InterfaceIndex $networkAdapter.InterfaceIndex -AddressFamily $addressFamily -Dhcp 'Enabled' -Confirm:$false | Out-Default
+        }
+    }
+`)
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,297 @@
+//
+// Copyright (c) 2019 Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-windows
+//
+package api
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "encoding/json"
+    "log"
+    "strings"
+
+    "github.com/stefaanc/golang-exec/runner"
+    "github.com/stefaanc/golang-exec/script"
+)
+
+//------------------------------------------------------------------------------
+
+type NetworkIPInterface struct {
+    NetworkAdapterGUID string
+    NetworkAdapterName string
+    AddressFamily      string   // "IPv4" or "IPv6"
+
+    DHCP               bool
+    AutomaticMetric    bool
+    InterfaceMetric    uint32
+    NlMTU              uint32
+    Forwarding         bool
+    RouterDiscovery    string   // "Enabled", "Disabled" or "ControlledByDHCP"
+    WeakHostSend       bool
+    WeakHostReceive    bool
+
+    // status
+    InterfaceIndex     uint32
+    InterfaceAlias     string
+    ConnectionState    string
+}
+
+//------------------------------------------------------------------------------
+
+func (c *WindowsClient) ReadNetworkIPInterface(niiQuery *NetworkIPInterface) (niiProperties *NetworkIPInterface, err error) {
+    if ( niiQuery.NetworkAdapterGUID == "" ) &&
+       ( niiQuery.NetworkAdapterName == "" ) {
+        return nil, fmt.Errorf("[ERROR][terraform-provider-windows/api/ReadNetworkIPInterface(niiQuery)] missing 'niiQuery.NetworkAdapterGUID' or 'niiQuery.NetworkAdapterName'")
+    }
+    if ( niiQuery.AddressFamily != "IPv4" ) && ( niiQuery.AddressFamily != "IPv6" ) {
+        return nil, fmt.Errorf("[ERROR][terraform-provider-windows/api/ReadNetworkIPInterface(niiQuery)] invalid 'niiQuery.AddressFamily', must be \"IPv4\" or \"IPv6\"")
+    }
+
+    return readNetworkIPInterface(c, niiQuery)
+}
+
+func (c *WindowsClient) UpdateNetworkIPInterface(niiQuery *NetworkIPInterface, niiProperties *NetworkIPInterface) error {
+    if niiQuery.NetworkAdapterGUID == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/UpdateNetworkIPInterface(niiQuery)] missing 'niiQuery.NetworkAdapterGUID'")
+    }
+    if ( niiQuery.AddressFamily != "IPv4" ) && ( niiQuery.AddressFamily != "IPv6" ) {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/UpdateNetworkIPInterface(niiQuery)] invalid 'niiQuery.AddressFamily', must be \"IPv4\" or \"IPv6\"")
+    }
+    if ( niiProperties.RouterDiscovery != "" ) &&
+       ( niiProperties.RouterDiscovery != "Enabled" ) &&
+       ( niiProperties.RouterDiscovery != "Disabled" ) &&
+       ( niiProperties.RouterDiscovery != "ControlledByDHCP" ) {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/UpdateNetworkIPInterface(niiProperties)] invalid 'niiProperties.RouterDiscovery', must be \"Enabled\", \"Disabled\" or \"ControlledByDHCP\"")
+    }
+
+    return updateNetworkIPInterface(c, niiQuery, niiProperties)
+}
+
+//------------------------------------------------------------------------------
+
+func readNetworkIPInterface(c *WindowsClient, niiQuery *NetworkIPInterface) (niiProperties *NetworkIPInterface, err error) {
+    // find id
+    var id string
+    if niiQuery.NetworkAdapterGUID != "" { id = niiQuery.NetworkAdapterGUID } else
+    if niiQuery.NetworkAdapterName != "" { id = niiQuery.NetworkAdapterName }
+    id = fmt.Sprintf("%s/%s", id, niiQuery.AddressFamily)
+
+    // convert query to JSON
+    niiQueryJSON, err := json.Marshal(niiQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkIPInterface()] cannot cannot convert 'niiQuery' to json for network_ip_interface %#v\n", id)
+        return nil, err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, readNetworkIPInterfaceScript, readNetworkIPInterfaceArguments{
+        NIIQueryJSON: string(niiQueryJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkIPInterface()] cannot read network_ip_interface %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkIPInterface()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkIPInterface()] script stdout: \n%s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkIPInterface()] script stderr: \n%s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/readNetworkIPInterface()] runner: %s", stderr.String())
+        }
+
+        return nil, err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/readNetworkIPInterface()] read network_ip_interface %#v \n%s", id, stdout.String())
+
+    // convert stdout-JSON to niiProperties
+    niiProperties = new(NetworkIPInterface)
+    err = json.Unmarshal(stdout.Bytes(), niiProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkIPInterface()] cannot convert json to 'niiProperties' for network_ip_interface %#v\n", id)
+        return nil, err
+    }
+
+    return niiProperties, nil
+}
+
+type readNetworkIPInterfaceArguments struct{
+    NIIQueryJSON string
+}
+
+var readNetworkIPInterfaceScript = script.New("readNetworkIPInterface", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $niiQuery = ConvertFrom-Json -InputObject '{{.NIIQueryJSON}}'
+    $guid = $niiQuery.NetworkAdapterGUID
+    $name = $niiQuery.NetworkAdapterName
+
+    if ( $guid -ne "" ) {
+        $id = $guid
+        $networkAdapter = Get-NetAdapter -IncludeHidden -ErrorAction 'Ignore' | where { $_.InstanceID -eq "{$guid}" }
+    }
+    elseif ( $name -ne "" ) {
+        $id = $name
+        $networkAdapter = Get-NetAdapter -Name $name -ErrorAction 'Ignore'
+    }
+    if ( -not $networkAdapter ) {
+        throw "cannot find network_adapter '$id'"
+    }
+
+    $addressFamily = $niiQuery.AddressFamily
+    $networkIPInterface = Get-NetIPInterface -InterfaceIndex $networkAdapter.InterfaceIndex -AddressFamily $addressFamily -ErrorAction 'Ignore'
+    if ( -not $networkIPInterface ) {
+        throw "cannot find network_ip_interface '$id/$addressFamily'"
+    }
+
+    # prepare result
+    $niiProperties = @{
+        NetworkAdapterGUID = $networkAdapter.InstanceID.Trim("{}")
+        NetworkAdapterName = $networkAdapter.Name
+        AddressFamily      = $networkIPInterface.AddressFamily.ToString()
+
+        DHCP               = ( $networkIPInterface.Dhcp.ToString() -eq 'Enabled' )
+        AutomaticMetric    = ( $networkIPInterface.AutomaticMetric.ToString() -eq 'Enabled' )
+        InterfaceMetric    = $networkIPInterface.InterfaceMetric
+        NlMTU              = $networkIPInterface.NlMtu
+        Forwarding         = ( $networkIPInterface.Forwarding.ToString() -eq 'Enabled' )
+        RouterDiscovery    = $networkIPInterface.RouterDiscovery.ToString()
+        WeakHostSend       = ( $networkIPInterface.WeakHostSend.ToString() -eq 'Enabled' )
+        WeakHostReceive    = ( $networkIPInterface.WeakHostReceive.ToString() -eq 'Enabled' )
+
+        InterfaceIndex     = $networkIPInterface.InterfaceIndex
+        InterfaceAlias     = $networkIPInterface.InterfaceAlias
+        ConnectionState    = $networkIPInterface.ConnectionState.ToString()
+    }
+
+    Write-Output $( ConvertTo-Json -InputObject $niiProperties -Depth 100 )
+`)
+
+//------------------------------------------------------------------------------
+
+func updateNetworkIPInterface(c *WindowsClient, niiQuery *NetworkIPInterface, niiProperties *NetworkIPInterface) error {
+    // find id
+    id := fmt.Sprintf("%s/%s", niiQuery.NetworkAdapterGUID, niiQuery.AddressFamily)
+
+    // convert query to JSON
+    niiQueryJSON, err := json.Marshal(niiQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/updateNetworkIPInterface(niiQuery, niiProperties)] cannot cannot convert 'niiQuery' to json for network_ip_interface %#v\n", id)
+        return err
+    }
+
+    // convert properties to JSON
+    niiPropertiesJSON, err := json.Marshal(niiProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/updateNetworkIPInterface(niiQuery, niiProperties)] cannot cannot convert 'niiProperties' to json for network_ip_interface %#v\n", id)
+        return err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, updateNetworkIPInterfaceScript, updateNetworkIPInterfaceArguments{
+        NIIQueryJSON:      string(niiQueryJSON),
+        NIIPropertiesJSON: string(niiPropertiesJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/updateNetworkIPInterface()] cannot update network_ip_interface %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/updateNetworkIPInterface()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/updateNetworkIPInterface()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/updateNetworkIPInterface()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/updateNetworkIPInterface()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/updateNetworkIPInterface()] updated network_ip_interface %#v\n", id)
+
+    return nil
+}
+
+type updateNetworkIPInterfaceArguments struct{
+    NIIQueryJSON      string
+    NIIPropertiesJSON string
+}
+
+var updateNetworkIPInterfaceScript = script.New("updateNetworkIPInterface", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $niiQuery = ConvertFrom-Json -InputObject '{{.NIIQueryJSON}}'
+    $guid = $niiQuery.NetworkAdapterGUID
+
+    $networkAdapter = Get-NetAdapter -IncludeHidden -ErrorAction 'Ignore' | where { $_.InstanceID -eq "{$guid}" }
+    if ( -not $networkAdapter ) {
+        throw "cannot find network_adapter '$guid'"
+    }
+
+    $addressFamily = $niiQuery.AddressFamily
+    $networkIPInterface = Get-NetIPInterface -InterfaceIndex $networkAdapter.InterfaceIndex -AddressFamily $addressFamily -ErrorAction 'Ignore'
+    if ( -not $networkIPInterface ) {
+        throw "cannot find network_ip_interface '$guid/$addressFamily'"
+    }
+
+    $niiProperties = ConvertFrom-Json -InputObject '{{.NIIPropertiesJSON}}'
+
+    function ConvertTo-State( $enabled ) {
+        if ( $enabled ) { 'Enabled' } else { 'Disabled' }
+    }
+
+    $arguments = @{}
+    if ( ( ConvertTo-State $niiProperties.DHCP ) -ne $networkIPInterface.Dhcp.ToString() ) {
+        $arguments.Dhcp = ConvertTo-State $niiProperties.DHCP
+    }
+    if ( $niiProperties.AutomaticMetric ) {
+        if ( $networkIPInterface.AutomaticMetric.ToString() -ne 'Enabled' ) {
+            $arguments.AutomaticMetric = 'Enabled'
+        }
+    }
+    elseif ( ( $networkIPInterface.AutomaticMetric.ToString() -ne 'Disabled' ) -or ( ( $niiProperties.InterfaceMetric -ne 0 ) -and ( $niiProperties.InterfaceMetric -ne $networkIPInterface.InterfaceMetric ) ) ) {
+        $arguments.AutomaticMetric = 'Disabled'
+        if ( $niiProperties.InterfaceMetric -ne 0 ) {
+            $arguments.InterfaceMetric = $niiProperties.InterfaceMetric
+        }
+    }
+    if ( ( $niiProperties.NlMTU -ne 0 ) -and ( $niiProperties.NlMTU -ne $networkIPInterface.NlMtu ) ) {
+        $arguments.NlMtuBytes = $niiProperties.NlMTU
+    }
+    if ( ( ConvertTo-State $niiProperties.Forwarding ) -ne $networkIPInterface.Forwarding.ToString() ) {
+        $arguments.Forwarding = ConvertTo-State $niiProperties.Forwarding
+    }
+    if ( ( $niiProperties.RouterDiscovery -ne "" ) -and ( $niiProperties.RouterDiscovery -ne $networkIPInterface.RouterDiscovery.ToString() ) ) {
+        $arguments.RouterDiscovery = $niiProperties.RouterDiscovery
+    }
+    if ( ( ConvertTo-State $niiProperties.WeakHostSend ) -ne $networkIPInterface.WeakHostSend.ToString() ) {
+        $arguments.WeakHostSend = ConvertTo-State $niiProperties.WeakHostSend
+    }
+    if ( ( ConvertTo-State $niiProperties.WeakHostReceive ) -ne $networkIPInterface.WeakHostReceive.ToString() ) {
+        $arguments.WeakHostReceive = ConvertTo-State $niiProperties.WeakHostReceive
+    }
+
+    if ( $arguments.Count -gt 0 ) {
+        Set-NetIPInterface -InterfaceIndex $networkAdapter.InterfaceIndex -AddressFamily $addressFamily @arguments -Confirm:$false | Out-Default
+    }
+`)
+
+//------------------------------------------------------------------------------
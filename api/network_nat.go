@@ -0,0 +1,429 @@
+//
+// Copyright (c) 2019 Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-windows
+//
+package api
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "encoding/json"
+    "log"
+    "strings"
+
+    "github.com/stefaanc/golang-exec/runner"
+    "github.com/stefaanc/golang-exec/script"
+)
+
+//------------------------------------------------------------------------------
+
+type NetworkNAT struct {
+    Name                             string
+    InternalIPInterfaceAddressPrefix string   // cannot be changed after creation
+
+    StaticMappings                   []NetworkNATStaticMapping
+
+    // status
+    Active                           bool
+}
+
+type NetworkNATStaticMapping struct {
+    Protocol          string   // "TCP" or "UDP"
+    ExternalIPAddress string   // "0.0.0.0" means any external address
+    ExternalPort      uint16
+    InternalIPAddress string
+    InternalPort      uint16
+}
+
+//------------------------------------------------------------------------------
+
+func (c *WindowsClient) CreateNetworkNAT(nnProperties *NetworkNAT) error {
+    if nnProperties.Name == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/CreateNetworkNAT(nnProperties)] missing 'nnProperties.Name'")
+    }
+    if nnProperties.InternalIPInterfaceAddressPrefix == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/CreateNetworkNAT(nnProperties)] missing 'nnProperties.InternalIPInterfaceAddressPrefix'")
+    }
+    for _, sm := range nnProperties.StaticMappings {
+        if ( sm.Protocol != "TCP" ) && ( sm.Protocol != "UDP" ) {
+            return fmt.Errorf("[ERROR][terraform-provider-windows/api/CreateNetworkNAT(nnProperties)] invalid 'nnProperties.StaticMappings[].Protocol', must be \"TCP\" or \"UDP\"")
+        }
+        if sm.InternalIPAddress == "" {
+            return fmt.Errorf("[ERROR][terraform-provider-windows/api/CreateNetworkNAT(nnProperties)] missing 'nnProperties.StaticMappings[].InternalIPAddress'")
+        }
+    }
+
+    return createNetworkNAT(c, nnProperties)
+}
+
+func (c *WindowsClient) ReadNetworkNAT(nnQuery *NetworkNAT) (nnProperties *NetworkNAT, err error) {
+    if nnQuery.Name == "" {
+        return nil, fmt.Errorf("[ERROR][terraform-provider-windows/api/ReadNetworkNAT(nnQuery)] missing 'nnQuery.Name'")
+    }
+
+    return readNetworkNAT(c, nnQuery)
+}
+
+func (c *WindowsClient) UpdateNetworkNAT(nnQuery *NetworkNAT, nnProperties *NetworkNAT) error {
+    if nnQuery.Name == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/UpdateNetworkNAT(nnQuery)] missing 'nnQuery.Name'")
+    }
+    for _, sm := range nnProperties.StaticMappings {
+        if ( sm.Protocol != "TCP" ) && ( sm.Protocol != "UDP" ) {
+            return fmt.Errorf("[ERROR][terraform-provider-windows/api/UpdateNetworkNAT(nnProperties)] invalid 'nnProperties.StaticMappings[].Protocol', must be \"TCP\" or \"UDP\"")
+        }
+        if sm.InternalIPAddress == "" {
+            return fmt.Errorf("[ERROR][terraform-provider-windows/api/UpdateNetworkNAT(nnProperties)] missing 'nnProperties.StaticMappings[].InternalIPAddress'")
+        }
+    }
+
+    return updateNetworkNAT(c, nnQuery, nnProperties)
+}
+
+func (c *WindowsClient) DeleteNetworkNAT(nnQuery *NetworkNAT) error {
+    if nnQuery.Name == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/DeleteNetworkNAT(nnQuery)] missing 'nnQuery.Name'")
+    }
+
+    return deleteNetworkNAT(c, nnQuery)
+}
+
+//------------------------------------------------------------------------------
+
+func createNetworkNAT(c *WindowsClient, nnProperties *NetworkNAT) error {
+    // find id
+    id := nnProperties.Name
+
+    // convert properties to JSON
+    nnPropertiesJSON, err := json.Marshal(nnProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/createNetworkNAT(nnProperties)] cannot cannot convert 'nnProperties' to json for network_nat %#v\n", id)
+        return err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, createNetworkNATScript, createNetworkNATArguments{
+        NNPropertiesJSON: string(nnPropertiesJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/createNetworkNAT()] cannot create network_nat %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/createNetworkNAT()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/createNetworkNAT()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/createNetworkNAT()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/createNetworkNAT()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/createNetworkNAT()] created network_nat %#v\n", id)
+
+    return nil
+}
+
+type createNetworkNATArguments struct{
+    NNPropertiesJSON string
+}
+
+var createNetworkNATScript = script.New("createNetworkNAT", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $nnProperties = ConvertFrom-Json -InputObject '{{.NNPropertiesJSON}}'
+    $name = $nnProperties.Name
+
+    if ( -not ( Get-Command -Name 'New-NetNat' -ErrorAction 'Ignore' ) ) {
+        throw "cannot create network_nat '$name', NetNat is not supported on this windows-computer"
+    }
+    if ( Get-NetNat -Name $name -ErrorAction 'Ignore' ) {
+        throw "cannot create network_nat '$name', network_nat already exists"
+    }
+
+    New-NetNat -Name $name -InternalIPInterfaceAddressPrefix $nnProperties.InternalIPInterfaceAddressPrefix -Confirm:$false | Out-Null
+
+    foreach ( $staticMapping in $nnProperties.StaticMappings ) {
+        $externalIPAddress = $staticMapping.ExternalIPAddress
+        if ( -not $externalIPAddress ) {
+            $externalIPAddress = '0.0.0.0'
+        }
+        Add-NetNatStaticMapping -NatName $name -Protocol $staticMapping.Protocol -ExternalIPAddress $externalIPAddress -ExternalPort $staticMapping.ExternalPort -InternalIPAddress $staticMapping.InternalIPAddress -InternalPort $staticMapping.InternalPort -Confirm:$false | Out-Null
+    }
+`)
+
+//------------------------------------------------------------------------------
+
+func readNetworkNAT(c *WindowsClient, nnQuery *NetworkNAT) (nnProperties *NetworkNAT, err error) {
+    // find id
+    id := nnQuery.Name
+
+    // convert query to JSON
+    nnQueryJSON, err := json.Marshal(nnQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkNAT()] cannot cannot convert 'nnQuery' to json for network_nat %#v\n", id)
+        return nil, err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, readNetworkNATScript, readNetworkNATArguments{
+        NNQueryJSON: string(nnQueryJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkNAT()] cannot read network_nat %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkNAT()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkNAT()] script stdout: \n%s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkNAT()] script stderr: \n%s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/readNetworkNAT()] runner: %s", stderr.String())
+        }
+
+        return nil, err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/readNetworkNAT()] read network_nat %#v \n%s", id, stdout.String())
+
+    // convert stdout-JSON to nnProperties
+    nnProperties = new(NetworkNAT)
+    err = json.Unmarshal(stdout.Bytes(), nnProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkNAT()] cannot convert json to 'nnProperties' for network_nat %#v\n", id)
+        return nil, err
+    }
+
+    return nnProperties, nil
+}
+
+type readNetworkNATArguments struct{
+    NNQueryJSON string
+}
+
+var readNetworkNATScript = script.New("readNetworkNAT", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $nnQuery = ConvertFrom-Json -InputObject '{{.NNQueryJSON}}'
+    $name = $nnQuery.Name
+
+    if ( Get-Command -Name 'Get-NetNat' -ErrorAction 'Ignore' ) {
+        $networkNAT = Get-NetNat -Name $name -ErrorAction 'Ignore'
+    }
+    if ( -not $networkNAT ) {
+        throw "cannot find network_nat '$name'"
+    }
+
+    # prepare result
+    $nnProperties = @{
+        Name                             = $networkNAT.Name
+        InternalIPInterfaceAddressPrefix = $networkNAT.InternalIPInterfaceAddressPrefix
+        StaticMappings                   = @()
+        Active                           = [bool]$networkNAT.Active
+    }
+
+    $staticMappings = Get-NetNatStaticMapping -NatName $name -ErrorAction 'Ignore' | Sort-Object -Property 'Protocol', 'ExternalIPAddress', 'ExternalPort'
+    foreach ( $staticMapping in $staticMappings ) {
+        $nnProperties.StaticMappings += @{
+            Protocol          = $staticMapping.Protocol.ToString().ToUpper()
+            ExternalIPAddress = $staticMapping.ExternalIPAddress
+            ExternalPort      = [uint16]$staticMapping.ExternalPort
+            InternalIPAddress = $staticMapping.InternalIPAddress
+            InternalPort      = [uint16]$staticMapping.InternalPort
+        }
+    }
+
+    Write-Output $( ConvertTo-Json -InputObject $nnProperties -Depth 100 )
+`)
+
+//------------------------------------------------------------------------------
+
+func updateNetworkNAT(c *WindowsClient, nnQuery *NetworkNAT, nnProperties *NetworkNAT) error {
+    // find id
+    id := nnQuery.Name
+
+    // convert query to JSON
+    nnQueryJSON, err := json.Marshal(nnQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/updateNetworkNAT(nnQuery, nnProperties)] cannot cannot convert 'nnQuery' to json for network_nat %#v\n", id)
+        return err
+    }
+
+    // convert properties to JSON
+    nnPropertiesJSON, err := json.Marshal(nnProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/updateNetworkNAT(nnQuery, nnProperties)] cannot cannot convert 'nnProperties' to json for network_nat %#v\n", id)
+        return err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, updateNetworkNATScript, updateNetworkNATArguments{
+        NNQueryJSON:      string(nnQueryJSON),
+        NNPropertiesJSON: string(nnPropertiesJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/updateNetworkNAT()] cannot update network_nat %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/updateNetworkNAT()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/updateNetworkNAT()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/updateNetworkNAT()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/updateNetworkNAT()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/updateNetworkNAT()] updated network_nat %#v\n", id)
+
+    return nil
+}
+
+type updateNetworkNATArguments struct{
+    NNQueryJSON      string
+    NNPropertiesJSON string
+}
+
+var updateNetworkNATScript = script.New("updateNetworkNAT", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $nnQuery = ConvertFrom-Json -InputObject '{{.NNQueryJSON}}'
+    $name = $nnQuery.Name
+
+    if ( Get-Command -Name 'Get-NetNat' -ErrorAction 'Ignore' ) {
+        $networkNAT = Get-NetNat -Name $name -ErrorAction 'Ignore'
+    }
+    if ( -not $networkNAT ) {
+        throw "cannot find network_nat '$name'"
+    }
+
+    $nnProperties = ConvertFrom-Json -InputObject '{{.NNPropertiesJSON}}'
+
+    $desiredMappings = @( $nnProperties.StaticMappings | where { $_ } | foreach {
+        $externalIPAddress = $_.ExternalIPAddress
+        if ( -not $externalIPAddress ) {
+            $externalIPAddress = '0.0.0.0'
+        }
+        [pscustomobject]@{
+            Key               = "$( $_.Protocol.ToUpper() ):$( $externalIPAddress ):$( $_.ExternalPort )->$( $_.InternalIPAddress ):$( $_.InternalPort )"
+            Protocol          = $_.Protocol
+            ExternalIPAddress = $externalIPAddress
+            ExternalPort      = $_.ExternalPort
+            InternalIPAddress = $_.InternalIPAddress
+            InternalPort      = $_.InternalPort
+        }
+    } )
+    $desiredKeys = @( $desiredMappings | foreach { $_.Key } )
+
+    # remove mappings that are not desired first, so desired mappings can reuse their external ports
+    $currentKeys = @()
+    foreach ( $staticMapping in @( Get-NetNatStaticMapping -NatName $name -ErrorAction 'Ignore' ) ) {
+        $key = "$( $staticMapping.Protocol.ToString().ToUpper() ):$( $staticMapping.ExternalIPAddress ):$( $staticMapping.ExternalPort )->$( $staticMapping.InternalIPAddress ):$( $staticMapping.InternalPort )"
+        if ( $desiredKeys -notcontains $key ) {
+            Remove-NetNatStaticMapping -NatName $name -StaticMappingID $staticMapping.StaticMappingID -Confirm:$false | Out-Default
+        }
+        else {
+            $currentKeys += $key
+        }
+    }
+
+    foreach ( $desiredMapping in $desiredMappings ) {
+        if ( $currentKeys -notcontains $desiredMapping.Key ) {
+            Add-NetNatStaticMapping -NatName $name -Protocol $desiredMapping.Protocol -ExternalIPAddress $desiredMapping.ExternalIPAddress -ExternalPort $desiredMapping.ExternalPort -InternalIPAddress $desiredMapping.InternalIPAddress -InternalPort $desiredMapping.InternalPort -Confirm:$false | Out-Default
+        }
+    }
+`)
+
+//------------------------------------------------------------------------------
+
+func deleteNetworkNAT(c *WindowsClient, nnQuery *NetworkNAT) error {
+    // find id
+    id := nnQuery.Name
+
+    // convert query to JSON
+    nnQueryJSON, err := json.Marshal(nnQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteNetworkNAT(nnQuery)] cannot cannot convert 'nnQuery' to json for network_nat %#v\n", id)
+        return err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, deleteNetworkNATScript, deleteNetworkNATArguments{
+        NNQueryJSON: string(nnQueryJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteNetworkNAT()] cannot delete network_nat %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteNetworkNAT()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteNetworkNAT()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteNetworkNAT()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/deleteNetworkNAT()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/deleteNetworkNAT()] deleted network_nat %#v\n", id)
+
+    return nil
+}
+
+type deleteNetworkNATArguments struct{
+    NNQueryJSON string
+}
+
+var deleteNetworkNATScript = script.New("deleteNetworkNAT", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $nnQuery = ConvertFrom-Json -InputObject '{{.NNQueryJSON}}'
+    $name = $nnQuery.Name
+
+    if ( Get-Command -Name 'Get-NetNat' -ErrorAction 'Ignore' ) {
+        $networkNAT = Get-NetNat -Name $name -ErrorAction 'Ignore'
+    }
+    if ( -not $networkNAT ) {
+        return
+    }
+
+    # static mappings are removed together with the nat
+    Remove-NetNat -Name $name -Confirm:$false | Out-Default
+`)
+
+//------------------------------------------------------------------------------
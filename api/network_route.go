@@ -0,0 +1,570 @@
+//
+// Copyright (c) 2019 Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-windows
+//
+package api
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "encoding/json"
+    "log"
+    "strings"
+
+    "github.com/stefaanc/golang-exec/runner"
+    "github.com/stefaanc/golang-exec/script"
+)
+
+//------------------------------------------------------------------------------
+
+type NetworkRoute struct {
+    DestinationPrefix  string
+    NextHop            string
+
+    NetworkAdapterGUID string
+    NetworkAdapterName string
+    InterfaceIndex     uint32
+    InterfaceAlias     string
+
+    AddressFamily      string   // "IPv4" or "IPv6"
+    RouteMetric        uint16
+    PolicyStore        string   // "PersistentStore" (persistent and active) or "ActiveStore" (active only)
+
+    // status
+    Protocol           string
+}
+
+//------------------------------------------------------------------------------
+
+func (c *WindowsClient) CreateNetworkRoute(nrProperties *NetworkRoute) error {
+    if ( nrProperties.NetworkAdapterGUID == "" ) &&
+       ( nrProperties.NetworkAdapterName == "" ) {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/CreateNetworkRoute(nrProperties)] missing 'nrProperties.NetworkAdapterGUID' or 'nrProperties.NetworkAdapterName'")
+    }
+    if nrProperties.DestinationPrefix == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/CreateNetworkRoute(nrProperties)] missing 'nrProperties.DestinationPrefix'")
+    }
+    if nrProperties.NextHop == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/CreateNetworkRoute(nrProperties)] missing 'nrProperties.NextHop'")
+    }
+    if ( nrProperties.PolicyStore != "PersistentStore" ) && ( nrProperties.PolicyStore != "ActiveStore" ) {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/CreateNetworkRoute(nrProperties)] invalid 'nrProperties.PolicyStore', must be \"PersistentStore\" or \"ActiveStore\"")
+    }
+
+    return createNetworkRoute(c, nrProperties)
+}
+
+func (c *WindowsClient) ReadNetworkRoute(nrQuery *NetworkRoute) (nrProperties *NetworkRoute, err error) {
+    if ( nrQuery.NetworkAdapterGUID == "" ) &&
+       ( nrQuery.NetworkAdapterName == "" ) {
+        return nil, fmt.Errorf("[ERROR][terraform-provider-windows/api/ReadNetworkRoute(nrQuery)] missing 'nrQuery.NetworkAdapterGUID' or 'nrQuery.NetworkAdapterName'")
+    }
+    if nrQuery.DestinationPrefix == "" {
+        return nil, fmt.Errorf("[ERROR][terraform-provider-windows/api/ReadNetworkRoute(nrQuery)] missing 'nrQuery.DestinationPrefix'")
+    }
+    if nrQuery.NextHop == "" {
+        return nil, fmt.Errorf("[ERROR][terraform-provider-windows/api/ReadNetworkRoute(nrQuery)] missing 'nrQuery.NextHop'")
+    }
+
+    return readNetworkRoute(c, nrQuery)
+}
+
+func (c *WindowsClient) ReadNetworkRoutes(nrQuery *NetworkRoute) (nrPropertiesList []NetworkRoute, err error) {
+    return readNetworkRoutes(c, nrQuery)
+}
+
+func (c *WindowsClient) UpdateNetworkRoute(nrQuery *NetworkRoute, nrProperties *NetworkRoute) error {
+    if nrQuery.NetworkAdapterGUID == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/UpdateNetworkRoute(nrQuery)] missing 'nrQuery.NetworkAdapterGUID'")
+    }
+    if nrQuery.DestinationPrefix == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/UpdateNetworkRoute(nrQuery)] missing 'nrQuery.DestinationPrefix'")
+    }
+    if nrQuery.NextHop == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/UpdateNetworkRoute(nrQuery)] missing 'nrQuery.NextHop'")
+    }
+
+    return updateNetworkRoute(c, nrQuery, nrProperties)
+}
+
+func (c *WindowsClient) DeleteNetworkRoute(nrQuery *NetworkRoute) error {
+    if nrQuery.NetworkAdapterGUID == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/DeleteNetworkRoute(nrQuery)] missing 'nrQuery.NetworkAdapterGUID'")
+    }
+    if nrQuery.DestinationPrefix == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/DeleteNetworkRoute(nrQuery)] missing 'nrQuery.DestinationPrefix'")
+    }
+    if nrQuery.NextHop == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/DeleteNetworkRoute(nrQuery)] missing 'nrQuery.NextHop'")
+    }
+
+    return deleteNetworkRoute(c, nrQuery)
+}
+
+//------------------------------------------------------------------------------
+
+func createNetworkRoute(c *WindowsClient, nrProperties *NetworkRoute) error {
+    // find id
+    id := fmt.Sprintf("%s via %s", nrProperties.DestinationPrefix, nrProperties.NextHop)
+
+    // convert properties to JSON
+    nrPropertiesJSON, err := json.Marshal(nrProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/createNetworkRoute(nrProperties)] cannot cannot convert 'nrProperties' to json for network_route %#v\n", id)
+        return err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, createNetworkRouteScript, createNetworkRouteArguments{
+        NRPropertiesJSON: string(nrPropertiesJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/createNetworkRoute()] cannot create network_route %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/createNetworkRoute()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/createNetworkRoute()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/createNetworkRoute()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/createNetworkRoute()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/createNetworkRoute()] created network_route %#v\n", id)
+
+    return nil
+}
+
+type createNetworkRouteArguments struct{
+    NRPropertiesJSON string
+}
+
+var createNetworkRouteScript = script.New("createNetworkRoute", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $nrProperties = ConvertFrom-Json -InputObject '{{.NRPropertiesJSON}}'
+    $guid = $nrProperties.NetworkAdapterGUID
+    $name = $nrProperties.NetworkAdapterName
+
+    if ( $guid -ne "" ) {
+        $id = $guid
+        $networkAdapter = Get-NetAdapter -IncludeHidden -ErrorAction 'Ignore' | where { $_.InstanceID -eq "{$guid}" }
+    }
+    elseif ( $name -ne "" ) {
+        $id = $name
+        $networkAdapter = Get-NetAdapter -Name $name -ErrorAction 'Ignore'
+    }
+    if ( -not $networkAdapter ) {
+        throw "cannot find network_adapter '$id'"
+    }
+
+    $route = "$( $nrProperties.DestinationPrefix ) via $( $nrProperties.NextHop )"
+    $arguments = @{
+        InterfaceIndex    = $networkAdapter.InterfaceIndex
+        DestinationPrefix = $nrProperties.DestinationPrefix
+        NextHop           = $nrProperties.NextHop
+    }
+    if ( Get-NetRoute @arguments -PolicyStore 'ActiveStore' -ErrorAction 'Ignore' ) {
+        throw "cannot create network_route '$route', network_route already exists"
+    }
+
+    # without a policy store, the route is added to both the persistent store and the active store
+    if ( $nrProperties.PolicyStore -eq 'ActiveStore' ) {
+        $arguments.PolicyStore = 'ActiveStore'
+    }
+    New-NetRoute @arguments -RouteMetric $nrProperties.RouteMetric -Confirm:$false | Out-Null
+`)
+
+//------------------------------------------------------------------------------
+
+func readNetworkRoute(c *WindowsClient, nrQuery *NetworkRoute) (nrProperties *NetworkRoute, err error) {
+    // find id
+    id := fmt.Sprintf("%s via %s", nrQuery.DestinationPrefix, nrQuery.NextHop)
+
+    // convert query to JSON
+    nrQueryJSON, err := json.Marshal(nrQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkRoute()] cannot cannot convert 'nrQuery' to json for network_route %#v\n", id)
+        return nil, err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, readNetworkRouteScript, readNetworkRouteArguments{
+        NRQueryJSON: string(nrQueryJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkRoute()] cannot read network_route %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkRoute()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkRoute()] script stdout: \n%s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkRoute()] script stderr: \n%s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/readNetworkRoute()] runner: %s", stderr.String())
+        }
+
+        return nil, err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/readNetworkRoute()] read network_route %#v \n%s", id, stdout.String())
+
+    // convert stdout-JSON to nrProperties
+    nrProperties = new(NetworkRoute)
+    err = json.Unmarshal(stdout.Bytes(), nrProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkRoute()] cannot convert json to 'nrProperties' for network_route %#v\n", id)
+        return nil, err
+    }
+
+    return nrProperties, nil
+}
+
+type readNetworkRouteArguments struct{
+    NRQueryJSON string
+}
+
+var readNetworkRouteScript = script.New("readNetworkRoute", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $nrQuery = ConvertFrom-Json -InputObject '{{.NRQueryJSON}}'
+    $guid = $nrQuery.NetworkAdapterGUID
+    $name = $nrQuery.NetworkAdapterName
+
+    if ( $guid -ne "" ) {
+        $id = $guid
+        $networkAdapter = Get-NetAdapter -IncludeHidden -ErrorAction 'Ignore' | where { $_.InstanceID -eq "{$guid}" }
+    }
+    elseif ( $name -ne "" ) {
+        $id = $name
+        $networkAdapter = Get-NetAdapter -Name $name -ErrorAction 'Ignore'
+    }
+    if ( -not $networkAdapter ) {
+        throw "cannot find network_adapter '$id'"
+    }
+
+    $route = "$( $nrQuery.DestinationPrefix ) via $( $nrQuery.NextHop )"
+    $arguments = @{
+        InterfaceIndex    = $networkAdapter.InterfaceIndex
+        DestinationPrefix = $nrQuery.DestinationPrefix
+        NextHop           = $nrQuery.NextHop
+    }
+    $networkRoute = Get-NetRoute @arguments -PolicyStore 'ActiveStore' -ErrorAction 'Ignore'
+    $persistentNetworkRoute = Get-NetRoute @arguments -PolicyStore 'PersistentStore' -ErrorAction 'Ignore'
+    if ( -not $networkRoute ) {
+        $networkRoute = $persistentNetworkRoute
+    }
+    if ( -not $networkRoute ) {
+        throw "cannot find network_route '$route'"
+    }
+
+    # prepare result
+    $nrProperties = @{
+        DestinationPrefix  = $networkRoute.DestinationPrefix
+        NextHop            = $networkRoute.NextHop
+        NetworkAdapterGUID = $networkAdapter.InstanceID.Trim("{}")
+        NetworkAdapterName = $networkAdapter.Name
+        InterfaceIndex     = $networkAdapter.InterfaceIndex
+        InterfaceAlias     = $networkAdapter.Name
+        AddressFamily      = $networkRoute.AddressFamily.ToString()
+        RouteMetric        = $networkRoute.RouteMetric
+        PolicyStore        = if ( $persistentNetworkRoute ) { 'PersistentStore' } else { 'ActiveStore' }
+        Protocol           = $networkRoute.Protocol.ToString()
+    }
+
+    Write-Output $( ConvertTo-Json -InputObject $nrProperties -Depth 100 )
+`)
+
+//------------------------------------------------------------------------------
+
+func readNetworkRoutes(c *WindowsClient, nrQuery *NetworkRoute) (nrPropertiesList []NetworkRoute, err error) {
+    // find id
+    id := nrQuery.DestinationPrefix
+    if id == "" {
+        id = "*"
+    }
+
+    // convert query to JSON
+    nrQueryJSON, err := json.Marshal(nrQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkRoutes()] cannot cannot convert 'nrQuery' to json for network_routes %#v\n", id)
+        return nil, err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, readNetworkRoutesScript, readNetworkRoutesArguments{
+        NRQueryJSON: string(nrQueryJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkRoutes()] cannot read network_routes %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkRoutes()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkRoutes()] script stdout: \n%s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkRoutes()] script stderr: \n%s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/readNetworkRoutes()] runner: %s", stderr.String())
+        }
+
+        return nil, err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/readNetworkRoutes()] read network_routes %#v \n%s", id, stdout.String())
+
+    // convert stdout-JSON to nrPropertiesList
+    nrPropertiesList = make([]NetworkRoute, 0)
+    err = json.Unmarshal(stdout.Bytes(), &nrPropertiesList)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkRoutes()] cannot convert json to 'nrPropertiesList' for network_routes %#v\n", id)
+        return nil, err
+    }
+
+    return nrPropertiesList, nil
+}
+
+type readNetworkRoutesArguments struct{
+    NRQueryJSON string
+}
+
+var readNetworkRoutesScript = script.New("readNetworkRoutes", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $nrQuery = ConvertFrom-Json -InputObject '{{.NRQueryJSON}}'
+
+    $arguments = @{}
+    if ( $nrQuery.DestinationPrefix -ne "" ) { $arguments.DestinationPrefix = $nrQuery.DestinationPrefix }
+    if ( $nrQuery.InterfaceIndex    -ne 0  ) { $arguments.InterfaceIndex    = $nrQuery.InterfaceIndex    }
+    if ( $nrQuery.InterfaceAlias    -ne "" ) { $arguments.InterfaceAlias    = $nrQuery.InterfaceAlias    }
+    if ( $nrQuery.AddressFamily     -ne "" ) { $arguments.AddressFamily     = $nrQuery.AddressFamily     }
+
+    $persistentNetworkRoutes = @( Get-NetRoute @arguments -PolicyStore 'PersistentStore' -ErrorAction 'Ignore' )
+    $networkRoutes = @( Get-NetRoute @arguments -PolicyStore 'ActiveStore' -ErrorAction 'Ignore' )
+
+    $networkAdapters = @{}
+    Get-NetAdapter -IncludeHidden -ErrorAction 'Ignore' | foreach {
+        $networkAdapters[$_.InterfaceIndex] = $_
+    }
+
+    # prepare result
+    $nrPropertiesList = @()
+    $networkRoutes | Sort-Object -Property 'AddressFamily', 'DestinationPrefix', 'InterfaceIndex', 'NextHop' | foreach {
+        $networkRoute = $_
+        $networkAdapter = $networkAdapters[$networkRoute.InterfaceIndex]
+
+        $isPersistent = $persistentNetworkRoutes | where {
+            ( $_.InterfaceIndex -eq $networkRoute.InterfaceIndex ) -and
+            ( $_.DestinationPrefix -eq $networkRoute.DestinationPrefix ) -and
+            ( $_.NextHop -eq $networkRoute.NextHop )
+        }
+
+        $nrPropertiesList += @{
+            DestinationPrefix  = $networkRoute.DestinationPrefix
+            NextHop            = $networkRoute.NextHop
+            NetworkAdapterGUID = if ( $networkAdapter ) { $networkAdapter.InstanceID.Trim("{}") } else { "" }
+            NetworkAdapterName = if ( $networkAdapter ) { $networkAdapter.Name } else { "" }
+            InterfaceIndex     = $networkRoute.InterfaceIndex
+            InterfaceAlias     = $networkRoute.InterfaceAlias
+            AddressFamily      = $networkRoute.AddressFamily.ToString()
+            RouteMetric        = $networkRoute.RouteMetric
+            PolicyStore        = if ( $isPersistent ) { 'PersistentStore' } else { 'ActiveStore' }
+            Protocol           = $networkRoute.Protocol.ToString()
+        }
+    }
+
+    Write-Output $( ConvertTo-Json -InputObject @( $nrPropertiesList ) -Depth 100 )
+`)
+
+//------------------------------------------------------------------------------
+
+func updateNetworkRoute(c *WindowsClient, nrQuery *NetworkRoute, nrProperties *NetworkRoute) error {
+    // find id
+    id := fmt.Sprintf("%s via %s", nrQuery.DestinationPrefix, nrQuery.NextHop)
+
+    // convert query to JSON
+    nrQueryJSON, err := json.Marshal(nrQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/updateNetworkRoute(nrQuery, nrProperties)] cannot cannot convert 'nrQuery' to json for network_route %#v\n", id)
+        return err
+    }
+
+    // convert properties to JSON
+    nrPropertiesJSON, err := json.Marshal(nrProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/updateNetworkRoute(nrQuery, nrProperties)] cannot cannot convert 'nrProperties' to json for network_route %#v\n", id)
+        return err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, updateNetworkRouteScript, updateNetworkRouteArguments{
+        NRQueryJSON:      string(nrQueryJSON),
+        NRPropertiesJSON: string(nrPropertiesJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/updateNetworkRoute()] cannot update network_route %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/updateNetworkRoute()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/updateNetworkRoute()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/updateNetworkRoute()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/updateNetworkRoute()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/updateNetworkRoute()] updated network_route %#v\n", id)
+
+    return nil
+}
+
+type updateNetworkRouteArguments struct{
+    NRQueryJSON      string
+    NRPropertiesJSON string
+}
+
+var updateNetworkRouteScript = script.New("updateNetworkRoute", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $nrQuery = ConvertFrom-Json -InputObject '{{.NRQueryJSON}}'
+    $guid = $nrQuery.NetworkAdapterGUID
+
+    $networkAdapter = Get-NetAdapter -IncludeHidden -ErrorAction 'Ignore' | where { $_.InstanceID -eq "{$guid}" }
+    if ( -not $networkAdapter ) {
+        throw "cannot find network_adapter '$guid'"
+    }
+
+    $route = "$( $nrQuery.DestinationPrefix ) via $( $nrQuery.NextHop )"
+    $arguments = @{
+        InterfaceIndex    = $networkAdapter.InterfaceIndex
+        DestinationPrefix = $nrQuery.DestinationPrefix
+        NextHop           = $nrQuery.NextHop
+    }
+    $found = $false
+    foreach ( $policyStore in @( 'PersistentStore', 'ActiveStore' ) ) {
+        if ( Get-NetRoute @arguments -PolicyStore $policyStore -ErrorAction 'Ignore' ) {
+            $found = $true
+        }
+    }
+    if ( -not $found ) {
+        throw "cannot find network_route '$route'"
+    }
+
+    $nrProperties = ConvertFrom-Json -InputObject '{{.NRPropertiesJSON}}'
+
+    foreach ( $policyStore in @( 'PersistentStore', 'ActiveStore' ) ) {
+        $networkRoute = Get-NetRoute @arguments -PolicyStore $policyStore -ErrorAction 'Ignore'
+        if ( $networkRoute -and ( $networkRoute.RouteMetric -ne $nrProperties.RouteMetric ) ) {
+            Set-NetRoute @arguments -PolicyStore $policyStore -RouteMetric $nrProperties.RouteMetric -Confirm:$false | Out-Default
+        }
+    }
+`)
+
+//------------------------------------------------------------------------------
+
+func deleteNetworkRoute(c *WindowsClient, nrQuery *NetworkRoute) error {
+    // find id
+    id := fmt.Sprintf("%s via %s", nrQuery.DestinationPrefix, nrQuery.NextHop)
+
+    // convert query to JSON
+    nrQueryJSON, err := json.Marshal(nrQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteNetworkRoute(nrQuery)] cannot cannot convert 'nrQuery' to json for network_route %#v\n", id)
+        return err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, deleteNetworkRouteScript, deleteNetworkRouteArguments{
+        NRQueryJSON: string(nrQueryJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteNetworkRoute()] cannot delete network_route %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteNetworkRoute()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteNetworkRoute()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteNetworkRoute()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/deleteNetworkRoute()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/deleteNetworkRoute()] deleted network_route %#v\n", id)
+
+    return nil
+}
+
+type deleteNetworkRouteArguments struct{
+    NRQueryJSON string
+}
+
+var deleteNetworkRouteScript = script.New("deleteNetworkRoute", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $nrQuery = ConvertFrom-Json -InputObject '{{.NRQueryJSON}}'
+    $guid = $nrQuery.NetworkAdapterGUID
+
+    $networkAdapter = Get-NetAdapter -IncludeHidden -ErrorAction 'Ignore' | where { $_.InstanceID -eq "{$guid}" }
+    if ( -not $networkAdapter ) {
+        throw "cannot find network_adapter '$guid'"
+    }
+
+    $arguments = @{
+        InterfaceIndex    = $networkAdapter.InterfaceIndex
+        DestinationPrefix = $nrQuery.DestinationPrefix
+        NextHop           = $nrQuery.NextHop
+    }
+    foreach ( $policyStore in @( 'PersistentStore', 'ActiveStore' ) ) {
+        if ( Get-NetRoute @arguments -PolicyStore $policyStore -ErrorAction 'Ignore' ) {
+            Remove-NetRoute @arguments -PolicyStore $policyStore -Confirm:$false | Out-Default
+        }
+    }
+`)
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,473 @@
+//
+// Copyright (c) 2019 Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-windows
+//
+package api
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "encoding/json"
+    "log"
+    "strings"
+
+    "github.com/stefaanc/golang-exec/runner"
+    "github.com/stefaanc/golang-exec/script"
+)
+
+//------------------------------------------------------------------------------
+
+type NetworkTeam struct {
+    Name                   string
+
+    Members                []string   // network adapter names
+    TeamingMode            string     // "SwitchIndependent", "LACP" or "Static"
+    LoadBalancingAlgorithm string     // "Dynamic", "TransportPorts", "IPAddresses", "MacAddresses" or "HyperVPort"
+    StandbyMember          string     // "" when all members are active
+
+    TeamNICs               []NetworkTeamNIC   // additional team NICs, the primary team NIC is not included
+
+    // status
+    PrimaryTeamNICName     string
+    Status                 string
+}
+
+type NetworkTeamNIC struct {
+    Name   string
+    VlanID uint16
+}
+
+//------------------------------------------------------------------------------
+
+func (c *WindowsClient) CreateNetworkTeam(ntProperties *NetworkTeam) error {
+    if ntProperties.Name == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/CreateNetworkTeam(ntProperties)] missing 'ntProperties.Name'")
+    }
+    if len(ntProperties.Members) == 0 {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/CreateNetworkTeam(ntProperties)] missing 'ntProperties.Members'")
+    }
+
+    return createNetworkTeam(c, ntProperties)
+}
+
+func (c *WindowsClient) ReadNetworkTeam(ntQuery *NetworkTeam) (ntProperties *NetworkTeam, err error) {
+    if ntQuery.Name == "" {
+        return nil, fmt.Errorf("[ERROR][terraform-provider-windows/api/ReadNetworkTeam(ntQuery)] missing 'ntQuery.Name'")
+    }
+
+    return readNetworkTeam(c, ntQuery)
+}
+
+func (c *WindowsClient) UpdateNetworkTeam(ntQuery *NetworkTeam, ntProperties *NetworkTeam) error {
+    if ntQuery.Name == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/UpdateNetworkTeam(ntQuery)] missing 'ntQuery.Name'")
+    }
+
+    return updateNetworkTeam(c, ntQuery, ntProperties)
+}
+
+func (c *WindowsClient) DeleteNetworkTeam(ntQuery *NetworkTeam) error {
+    if ntQuery.Name == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/DeleteNetworkTeam(ntQuery)] missing 'ntQuery.Name'")
+    }
+
+    return deleteNetworkTeam(c, ntQuery)
+}
+
+//------------------------------------------------------------------------------
+
+func createNetworkTeam(c *WindowsClient, ntProperties *NetworkTeam) error {
+    // find id
+    id := ntProperties.Name
+
+    // convert properties to JSON
+    ntPropertiesJSON, err := json.Marshal(ntProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/createNetworkTeam(ntProperties)] cannot cannot convert 'ntProperties' to json for network_team %#v\n", id)
+        return err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, createNetworkTeamScript, createNetworkTeamArguments{
+        NTPropertiesJSON: string(ntPropertiesJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/createNetworkTeam()] cannot create network_team %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/createNetworkTeam()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/createNetworkTeam()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/createNetworkTeam()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/createNetworkTeam()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/createNetworkTeam()] created network_team %#v\n", id)
+
+    return nil
+}
+
+type createNetworkTeamArguments struct{
+    NTPropertiesJSON string
+}
+
+var createNetworkTeamScript = script.New("createNetworkTeam", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $ntProperties = ConvertFrom-Json -InputObject '{{.NTPropertiesJSON}}'
+    $name = $ntProperties.Name
+
+    if ( -not ( Get-Command -Name 'New-NetLbfoTeam' -ErrorAction 'Ignore' ) ) {
+        throw "cannot create network_team '$name', NIC teaming is not supported on this windows-computer"
+    }
+    if ( Get-NetLbfoTeam -Name $name -ErrorAction 'Ignore' ) {
+        throw "cannot create network_team '$name', network_team already exists"
+    }
+    if ( ( $ntProperties.StandbyMember -ne "" ) -and ( $ntProperties.Members -notcontains $ntProperties.StandbyMember ) ) {
+        throw "cannot create network_team '$name', standby member '$( $ntProperties.StandbyMember )' is not a member"
+    }
+
+    $arguments = @{
+        Name        = $name
+        TeamMembers = @( $ntProperties.Members )
+    }
+    if ( $ntProperties.TeamingMode            -ne "" ) { $arguments.TeamingMode            = $ntProperties.TeamingMode            }
+    if ( $ntProperties.LoadBalancingAlgorithm -ne "" ) { $arguments.LoadBalancingAlgorithm = $ntProperties.LoadBalancingAlgorithm }
+    New-NetLbfoTeam @arguments -Confirm:$false | Out-Null
+
+    if ( $ntProperties.StandbyMember -ne "" ) {
+        Set-NetLbfoTeamMember -Name $ntProperties.StandbyMember -Team $name -AdministrativeMode 'Standby' -Confirm:$false | Out-Null
+    }
+
+    foreach ( $teamNIC in $ntProperties.TeamNICs ) {
+        Add-NetLbfoTeamNic -Team $name -Name $teamNIC.Name -VlanID $teamNIC.VlanID -Confirm:$false | Out-Null
+    }
+`)
+
+//------------------------------------------------------------------------------
+
+func readNetworkTeam(c *WindowsClient, ntQuery *NetworkTeam) (ntProperties *NetworkTeam, err error) {
+    // find id
+    id := ntQuery.Name
+
+    // convert query to JSON
+    ntQueryJSON, err := json.Marshal(ntQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkTeam()] cannot cannot convert 'ntQuery' to json for network_team %#v\n", id)
+        return nil, err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, readNetworkTeamScript, readNetworkTeamArguments{
+        NTQueryJSON: string(ntQueryJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkTeam()] cannot read network_team %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkTeam()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkTeam()] script stdout: \n%s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkTeam()] script stderr: \n%s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/readNetworkTeam()] runner: %s", stderr.String())
+        }
+
+        return nil, err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/readNetworkTeam()] read network_team %#v \n%s", id, stdout.String())
+
+    // convert stdout-JSON to ntProperties
+    ntProperties = new(NetworkTeam)
+    err = json.Unmarshal(stdout.Bytes(), ntProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/readNetworkTeam()] cannot convert json to 'ntProperties' for network_team %#v\n", id)
+        return nil, err
+    }
+
+    return ntProperties, nil
+}
+
+type readNetworkTeamArguments struct{
+    NTQueryJSON string
+}
+
+var readNetworkTeamScript = script.New("readNetworkTeam", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $ntQuery = ConvertFrom-Json -InputObject '{{.NTQueryJSON}}'
+    $name = $ntQuery.Name
+
+    if ( Get-Command -Name 'Get-NetLbfoTeam' -ErrorAction 'Ignore' ) {
+        $networkTeam = Get-NetLbfoTeam -Name $name -ErrorAction 'Ignore'
+    }
+    if ( -not $networkTeam ) {
+        throw "cannot find network_team '$name'"
+    }
+
+    $teamMembers = @( Get-NetLbfoTeamMember -Team $name -ErrorAction 'Ignore' | Sort-Object -Property 'Name' )
+    $teamNICs = @( Get-NetLbfoTeamNic -Team $name -ErrorAction 'Ignore' | Sort-Object -Property 'Name' )
+
+    # prepare result
+    $ntProperties = @{
+        Name                   = $networkTeam.Name
+        Members                = @( $teamMembers | foreach { $_.Name } )
+        TeamingMode            = $networkTeam.TeamingMode.ToString()
+        LoadBalancingAlgorithm = $networkTeam.LoadBalancingAlgorithm.ToString()
+        StandbyMember          = ""
+        TeamNICs               = @()
+        PrimaryTeamNICName     = ""
+        Status                 = $networkTeam.Status.ToString()
+    }
+
+    $standbyMember = $teamMembers | where { $_.AdministrativeMode.ToString() -eq 'Standby' } | Select-Object -First 1
+    if ( $standbyMember ) {
+        $ntProperties.StandbyMember = $standbyMember.Name
+    }
+
+    foreach ( $teamNIC in $teamNICs ) {
+        if ( $teamNIC.Primary ) {
+            $ntProperties.PrimaryTeamNICName = $teamNIC.Name
+        }
+        else {
+            $ntProperties.TeamNICs += @{
+                Name   = $teamNIC.Name
+                VlanID = if ( $teamNIC.VlanID ) { $teamNIC.VlanID } else { 0 }
+            }
+        }
+    }
+
+    Write-Output $( ConvertTo-Json -InputObject $ntProperties -Depth 100 )
+`)
+
+//------------------------------------------------------------------------------
+
+func updateNetworkTeam(c *WindowsClient, ntQuery *NetworkTeam, ntProperties *NetworkTeam) error {
+    // find id
+    id := ntQuery.Name
+
+    // convert query to JSON
+    ntQueryJSON, err := json.Marshal(ntQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/updateNetworkTeam(ntQuery, ntProperties)] cannot cannot convert 'ntQuery' to json for network_team %#v\n", id)
+        return err
+    }
+
+    // convert properties to JSON
+    ntPropertiesJSON, err := json.Marshal(ntProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/updateNetworkTeam(ntQuery, ntProperties)] cannot cannot convert 'ntProperties' to json for network_team %#v\n", id)
+        return err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, updateNetworkTeamScript, updateNetworkTeamArguments{
+        NTQueryJSON:      string(ntQueryJSON),
+        NTPropertiesJSON: string(ntPropertiesJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/updateNetworkTeam()] cannot update network_team %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/updateNetworkTeam()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/updateNetworkTeam()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/updateNetworkTeam()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/updateNetworkTeam()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/updateNetworkTeam()] updated network_team %#v\n", id)
+
+    return nil
+}
+
+type updateNetworkTeamArguments struct{
+    NTQueryJSON      string
+    NTPropertiesJSON string
+}
+
+var updateNetworkTeamScript = script.New("updateNetworkTeam", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $ntQuery = ConvertFrom-Json -InputObject '{{.NTQueryJSON}}'
+    $name = $ntQuery.Name
+
+    if ( Get-Command -Name 'Get-NetLbfoTeam' -ErrorAction 'Ignore' ) {
+        $networkTeam = Get-NetLbfoTeam -Name $name -ErrorAction 'Ignore'
+    }
+    if ( -not $networkTeam ) {
+        throw "cannot find network_team '$name'"
+    }
+
+    $ntProperties = ConvertFrom-Json -InputObject '{{.NTPropertiesJSON}}'
+
+    if ( $ntProperties.Members.Count -gt 0 ) {
+        if ( ( $ntProperties.StandbyMember -ne "" ) -and ( $ntProperties.Members -notcontains $ntProperties.StandbyMember ) ) {
+            throw "cannot update network_team '$name', standby member '$( $ntProperties.StandbyMember )' is not a member"
+        }
+
+        # add new members before removing old members, so the team never becomes empty
+        $currentMembers = @( Get-NetLbfoTeamMember -Team $name -ErrorAction 'Ignore' | foreach { $_.Name } )
+        foreach ( $member in $ntProperties.Members ) {
+            if ( $currentMembers -notcontains $member ) {
+                Add-NetLbfoTeamMember -Name $member -Team $name -Confirm:$false | Out-Default
+            }
+        }
+        foreach ( $member in $currentMembers ) {
+            if ( $ntProperties.Members -notcontains $member ) {
+                Remove-NetLbfoTeamMember -Name $member -Team $name -Confirm:$false | Out-Default
+            }
+        }
+
+        # activate members before putting a member in standby, so there is always an active member
+        $teamMembers = @( Get-NetLbfoTeamMember -Team $name -ErrorAction 'Ignore' )
+        foreach ( $teamMember in $teamMembers ) {
+            if ( ( $teamMember.Name -ne $ntProperties.StandbyMember ) -and ( $teamMember.AdministrativeMode.ToString() -ne 'Active' ) ) {
+                Set-NetLbfoTeamMember -Name $teamMember.Name -Team $name -AdministrativeMode 'Active' -Confirm:$false | Out-Default
+            }
+        }
+        foreach ( $teamMember in $teamMembers ) {
+            if ( ( $teamMember.Name -eq $ntProperties.StandbyMember ) -and ( $teamMember.AdministrativeMode.ToString() -ne 'Standby' ) ) {
+                Set-NetLbfoTeamMember -Name $teamMember.Name -Team $name -AdministrativeMode 'Standby' -Confirm:$false | Out-Default
+            }
+        }
+    }
+
+    $arguments = @{}
+    if ( ( $ntProperties.TeamingMode -ne "" ) -and ( $ntProperties.TeamingMode -ne $networkTeam.TeamingMode.ToString() ) ) {
+        $arguments.TeamingMode = $ntProperties.TeamingMode
+    }
+    if ( ( $ntProperties.LoadBalancingAlgorithm -ne "" ) -and ( $ntProperties.LoadBalancingAlgorithm -ne $networkTeam.LoadBalancingAlgorithm.ToString() ) ) {
+        $arguments.LoadBalancingAlgorithm = $ntProperties.LoadBalancingAlgorithm
+    }
+    if ( $arguments.Count -gt 0 ) {
+        Set-NetLbfoTeam -Name $name @arguments -Confirm:$false | Out-Default
+    }
+
+    $currentTeamNICs = @( Get-NetLbfoTeamNic -Team $name -ErrorAction 'Ignore' | where { -not $_.Primary } )
+    foreach ( $teamNIC in $currentTeamNICs ) {
+        if ( -not ( $ntProperties.TeamNICs | where { $_.Name -eq $teamNIC.Name } ) ) {
+            Remove-NetLbfoTeamNic -Team $name -VlanID $teamNIC.VlanID -Confirm:$false | Out-Default
+        }
+    }
+    foreach ( $teamNIC in $ntProperties.TeamNICs ) {
+        $currentTeamNIC = $currentTeamNICs | where { $_.Name -eq $teamNIC.Name }
+        if ( -not $currentTeamNIC ) {
+            Add-NetLbfoTeamNic -Team $name -Name $teamNIC.Name -VlanID $teamNIC.VlanID -Confirm:$false | Out-Default
+        }
+        elseif ( $currentTeamNIC.VlanID -ne $teamNIC.VlanID ) {
+            Set-NetLbfoTeamNic -Team $name -Name $teamNIC.Name -VlanID $teamNIC.VlanID -Confirm:$false | Out-Default
+        }
+    }
+`)
+
+//------------------------------------------------------------------------------
+
+func deleteNetworkTeam(c *WindowsClient, ntQuery *NetworkTeam) error {
+    // find id
+    id := ntQuery.Name
+
+    // convert query to JSON
+    ntQueryJSON, err := json.Marshal(ntQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteNetworkTeam(ntQuery)] cannot cannot convert 'ntQuery' to json for network_team %#v\n", id)
+        return err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, deleteNetworkTeamScript, deleteNetworkTeamArguments{
+        NTQueryJSON: string(ntQueryJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteNetworkTeam()] cannot delete network_team %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteNetworkTeam()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteNetworkTeam()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteNetworkTeam()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/deleteNetworkTeam()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/deleteNetworkTeam()] deleted network_team %#v\n", id)
+
+    return nil
+}
+
+type deleteNetworkTeamArguments struct{
+    NTQueryJSON string
+}
+
+var deleteNetworkTeamScript = script.New("deleteNetworkTeam", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $ntQuery = ConvertFrom-Json -InputObject '{{.NTQueryJSON}}'
+    $name = $ntQuery.Name
+
+    if ( Get-Command -Name 'Get-NetLbfoTeam' -ErrorAction 'Ignore' ) {
+        $networkTeam = Get-NetLbfoTeam -Name $name -ErrorAction 'Ignore'
+    }
+    if ( -not $networkTeam ) {
+        return
+    }
+
+    if ( $env:SSH_CONNECTION ) {
+        # refuse to remove the team that carries the ssh-connection of the provider
+        $sshAddress = ( $env:SSH_CONNECTION -split ' ' )[2]
+        $sshIPAddress = Get-NetIPAddress -IPAddress $sshAddress -ErrorAction 'Ignore' | Select-Object -First 1
+        if ( $sshIPAddress ) {
+            $teamNICNames = @( Get-NetLbfoTeamNic -Team $name -ErrorAction 'Ignore' | foreach { $_.Name } )
+            if ( $teamNICNames -contains $sshIPAddress.InterfaceAlias ) {
+                throw "cannot delete network_team '$name', network_team carries the ssh-connection of the provider"
+            }
+        }
+    }
+
+    Remove-NetLbfoTeam -Name $name -Confirm:$false | Out-Default
+`)
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,258 @@
+//
+// Copyright (c) 2019 Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-windows
+//
+package api
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "encoding/base64"
+    "encoding/json"
+    "io/ioutil"
+    "log"
+    "strings"
+
+    "github.com/stefaanc/golang-exec/runner"
+    "github.com/stefaanc/golang-exec/script"
+)
+
+//------------------------------------------------------------------------------
+
+// a rollback is a scheduled task on the windows-computer that restores the original values of a resource
+// unless it is deleted before its window expires, it acts as a dead-man's switch for risky updates
+type Rollback struct {
+    Name   string   // name of the scheduled task, f.i. "network_adapter-E34DC156-C49F-42DB-A6F6-D5609648D274"
+    Window uint32   // seconds
+    Code   string   // base64-encoded powershell code that restores the original values
+}
+
+//------------------------------------------------------------------------------
+
+func (c *WindowsClient) CreateRollback(rbProperties *Rollback) error {
+    if rbProperties.Name == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/CreateRollback(rbProperties)] missing 'rbProperties.Name'")
+    }
+    if rbProperties.Window == 0 {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/CreateRollback(rbProperties)] missing 'rbProperties.Window'")
+    }
+    if rbProperties.Code == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/CreateRollback(rbProperties)] missing 'rbProperties.Code'")
+    }
+
+    return createRollback(c, rbProperties)
+}
+
+func (c *WindowsClient) DeleteRollback(rbQuery *Rollback) error {
+    if rbQuery.Name == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/DeleteRollback(rbQuery)] missing 'rbQuery.Name'")
+    }
+
+    return deleteRollback(c, rbQuery)
+}
+
+//------------------------------------------------------------------------------
+
+// renderRollbackCode renders a script with its arguments, so it can be run by the scheduled task of a rollback
+func renderRollbackCode(s *script.Script, arguments interface{}) (code string, err error) {
+    if s.Error != nil {
+        return "", s.Error
+    }
+
+    reader, err := s.NewReader(arguments)
+    if err != nil {
+        return "", err
+    }
+
+    rendered, err := ioutil.ReadAll(reader)
+    if err != nil {
+        return "", err
+    }
+
+    return base64.StdEncoding.EncodeToString(rendered), nil
+}
+
+//------------------------------------------------------------------------------
+
+func createRollback(c *WindowsClient, rbProperties *Rollback) error {
+    // find id
+    id := rbProperties.Name
+
+    // convert properties to JSON
+    rbPropertiesJSON, err := json.Marshal(rbProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/createRollback(rbProperties)] cannot cannot convert 'rbProperties' to json for rollback %#v\n", id)
+        return err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, createRollbackScript, createRollbackArguments{
+        RBPropertiesJSON: string(rbPropertiesJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/createRollback()] cannot create rollback %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/createRollback()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/createRollback()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/createRollback()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/createRollback()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/createRollback()] created rollback %#v\n", id)
+
+    return nil
+}
+
+type createRollbackArguments struct{
+    RBPropertiesJSON string
+}
+
+var createRollbackScript = script.New("createRollback", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $rbProperties = ConvertFrom-Json -InputObject '{{.RBPropertiesJSON}}'
+    $name   = $rbProperties.Name
+    $window = $rbProperties.Window
+    $code   = [System.Text.Encoding]::UTF8.GetString( [System.Convert]::FromBase64String( $rbProperties.Code ) )
+
+    $taskPath  = '\terraform-provider-windows\'
+    $directory = Join-Path $env:ProgramData 'terraform-provider-windows'
+    $path      = Join-Path $directory "rollback-$name.ps1"
+
+    $task = Get-ScheduledTask -TaskName $name -TaskPath $taskPath -ErrorAction 'Ignore'
+    if ( $task ) {
+        # replace a rollback that is left behind by a failed apply, when it has already run or its trigger time has passed
+        # a task that never ran has a 'LastTaskResult' of 0x41303 (SCHED_S_TASK_HAS_NOT_RUN)
+        $hasRun    = ( ( Get-ScheduledTaskInfo -InputObject $task ).LastTaskResult -ne 0x41303 )
+        $isExpired = ( [datetime]$task.Triggers[0].StartBoundary -lt ( Get-Date ) )
+        if ( -not ( $hasRun -or $isExpired ) ) {
+            throw "cannot create rollback '$name', rollback already exists and is pending, retry after it has restored the original values"
+        }
+
+        Unregister-ScheduledTask -TaskName $name -TaskPath $taskPath -Confirm:$false | Out-Null
+    }
+
+    # the scheduled task runs the code as SYSTEM, so only SYSTEM and Administrators can be allowed to create or change files in the directory
+    if ( Test-Path -Path $directory ) {
+        if ( ( Get-Item -Path $directory -Force ).Attributes -band [System.IO.FileAttributes]::ReparsePoint ) {
+            throw "cannot create rollback '$name', '$directory' is a reparse point"
+        }
+    }
+    else {
+        New-Item -Path $directory -ItemType 'Directory' -Force | Out-Null
+    }
+
+    $system         = New-Object System.Security.Principal.SecurityIdentifier( 'S-1-5-18' )
+    $administrators = New-Object System.Security.Principal.SecurityIdentifier( 'S-1-5-32-544' )
+    $acl = New-Object System.Security.AccessControl.DirectorySecurity
+    $acl.SetOwner( $administrators )
+    $acl.SetAccessRuleProtection( $true, $false )   # don't inherit from ProgramData
+    foreach ( $sid in @( $system, $administrators ) ) {
+        $acl.AddAccessRule( ( New-Object System.Security.AccessControl.FileSystemAccessRule( $sid, 'FullControl', 'ContainerInherit, ObjectInherit', 'None', 'Allow' ) ) )
+    }
+    Set-Acl -Path $directory -AclObject $acl
+
+    # never reuse an existing file, it may have been created before the directory was restricted
+    Remove-Item -Path $path -Force -ErrorAction 'Ignore'
+    if ( Test-Path -Path $path ) {
+        throw "cannot create rollback '$name', cannot remove existing '$path'"
+    }
+    Set-Content -Path $path -Value $code -Encoding 'UTF8'
+
+    $action    = New-ScheduledTaskAction -Execute 'PowerShell.exe' -Argument "-NoProfile -ExecutionPolicy ByPass -File ""$path"""
+    $trigger   = New-ScheduledTaskTrigger -Once -At ( Get-Date ).AddSeconds( $window )
+    $principal = New-ScheduledTaskPrincipal -UserId 'SYSTEM' -LogonType 'ServiceAccount' -RunLevel 'Highest'
+    Register-ScheduledTask -TaskName $name -TaskPath $taskPath -Action $action -Trigger $trigger -Principal $principal -Force | Out-Null
+`)
+
+//------------------------------------------------------------------------------
+
+func deleteRollback(c *WindowsClient, rbQuery *Rollback) error {
+    // find id
+    id := rbQuery.Name
+
+    // convert query to JSON
+    rbQueryJSON, err := json.Marshal(rbQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteRollback(rbQuery)] cannot cannot convert 'rbQuery' to json for rollback %#v\n", id)
+        return err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, deleteRollbackScript, deleteRollbackArguments{
+        RBQueryJSON: string(rbQueryJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteRollback()] cannot delete rollback %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteRollback()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteRollback()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteRollback()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/deleteRollback()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/deleteRollback()] deleted rollback %#v\n", id)
+
+    return nil
+}
+
+type deleteRollbackArguments struct{
+    RBQueryJSON string
+}
+
+var deleteRollbackScript = script.New("deleteRollback", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $rbQuery = ConvertFrom-Json -InputObject '{{.RBQueryJSON}}'
+    $name = $rbQuery.Name
+
+    $taskPath  = '\terraform-provider-windows\'
+    $directory = Join-Path $env:ProgramData 'terraform-provider-windows'
+    $path      = Join-Path $directory "rollback-$name.ps1"
+
+    $task = Get-ScheduledTask -TaskName $name -TaskPath $taskPath -ErrorAction 'Ignore'
+    if ( -not $task ) {
+        throw "cannot find rollback '$name'"
+    }
+
+    # a task that never ran has a 'LastTaskResult' of 0x41303 (SCHED_S_TASK_HAS_NOT_RUN)
+    $hasRun = ( ( Get-ScheduledTaskInfo -InputObject $task ).LastTaskResult -ne 0x41303 )
+
+    Unregister-ScheduledTask -TaskName $name -TaskPath $taskPath -Confirm:$false | Out-Null
+    Remove-Item -Path $path -Force -ErrorAction 'Ignore'
+
+    if ( $hasRun ) {
+        throw "cannot delete rollback '$name', rollback has already restored the original values"
+    }
+`)
+
+//------------------------------------------------------------------------------
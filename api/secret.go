@@ -0,0 +1,206 @@
+//
+// Copyright (c) 2019 Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-windows
+//
+package api
+
+import (
+    "bytes"
+    "crypto/rand"
+    "crypto/rsa"
+    "crypto/sha1"
+    "errors"
+    "fmt"
+    "encoding/base64"
+    "encoding/hex"
+    "encoding/json"
+    "log"
+    "math/big"
+    "strings"
+
+    "github.com/stefaanc/golang-exec/runner"
+    "github.com/stefaanc/golang-exec/script"
+)
+
+//------------------------------------------------------------------------------
+
+// a secret key is a one-time RSA key pair in the machine key store of the windows-computer
+// secrets are encrypted with its public key, so they are never rendered in plaintext in a script
+// the script that decrypts the secrets deletes the key pair
+type secretKey struct {
+    Container string           // name of the key container, f.i. "terraform-provider-windows-3F2A..."
+    publicKey *rsa.PublicKey
+}
+
+// encrypt returns the base64-encoded RSA-OAEP (SHA-1) encryption of a secret, or "" for an empty secret
+func (k *secretKey) encrypt(secret string) (string, error) {
+    if secret == "" {
+        return "", nil
+    }
+
+    encrypted, err := rsa.EncryptOAEP(sha1.New(), rand.Reader, k.publicKey, []byte(secret), nil)
+    if err != nil {
+        return "", fmt.Errorf("[terraform-provider-windows/api/encrypt()] cannot encrypt secret: %s", err)
+    }
+
+    return base64.StdEncoding.EncodeToString(encrypted), nil
+}
+
+//------------------------------------------------------------------------------
+
+func createSecretKey(c *WindowsClient) (key *secretKey, err error) {
+    // create a unique name for the key container
+    random := make([]byte, 16)
+    _, err = rand.Read(random)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/createSecretKey()] cannot create name for secret key\n")
+        return nil, err
+    }
+    container := fmt.Sprintf("terraform-provider-windows-%s", strings.ToUpper(hex.EncodeToString(random)))
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, createSecretKeyScript, createSecretKeyArguments{
+        Container: container,
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/createSecretKey()] cannot create secret key %q\n", container)
+        log.Printf("[ERROR][terraform-provider-windows/api/createSecretKey()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/createSecretKey()] script stdout: \n%s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/createSecretKey()] script stderr: \n%s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/createSecretKey()] runner: %s", stderr.String())
+        }
+
+        return nil, err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/createSecretKey()] created secret key %q \n%s", container, stdout.String())
+
+    // convert stdout-JSON to public key
+    var publicKey struct {
+        Modulus  []byte   // base64-encoded in JSON
+        Exponent []byte   // base64-encoded in JSON
+    }
+    err = json.Unmarshal(stdout.Bytes(), &publicKey)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/createSecretKey()] cannot convert json to 'publicKey' for secret key %q\n", container)
+        deleteSecretKey(c, &secretKey{ Container: container })
+        return nil, err
+    }
+
+    key = new(secretKey)
+    key.Container = container
+    key.publicKey = &rsa.PublicKey{
+        N: new(big.Int).SetBytes(publicKey.Modulus),
+        E: int(new(big.Int).SetBytes(publicKey.Exponent).Int64()),
+    }
+
+    return key, nil
+}
+
+type createSecretKeyArguments struct{
+    Container string
+}
+
+var createSecretKeyScript = script.New("createSecretKey", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    # only SYSTEM and the Administrators can use the key pair
+    $keySecurity = New-Object -TypeName 'System.Security.AccessControl.CryptoKeySecurity'
+    $keySecurity.SetAccessRuleProtection($true, $false)
+    foreach ( $sid in @( 'S-1-5-18', 'S-1-5-32-544' ) ) {
+        $identity = New-Object -TypeName 'System.Security.Principal.SecurityIdentifier' -ArgumentList $sid
+        $rule = New-Object -TypeName 'System.Security.AccessControl.CryptoKeyAccessRule' -ArgumentList $identity, 'FullControl', 'Allow'
+        $keySecurity.AddAccessRule($rule)
+    }
+
+    $cspParameters = New-Object -TypeName 'System.Security.Cryptography.CspParameters'
+    $cspParameters.KeyContainerName = '{{.Container}}'
+    $cspParameters.Flags = [System.Security.Cryptography.CspProviderFlags]::UseMachineKeyStore
+    $cspParameters.CryptoKeySecurity = $keySecurity
+
+    $rsa = New-Object -TypeName 'System.Security.Cryptography.RSACryptoServiceProvider' -ArgumentList 2048, $cspParameters
+    try {
+        $rsa.PersistKeyInCsp = $true
+        $publicKey = $rsa.ExportParameters($false)
+    }
+    finally {
+        $rsa.Dispose()
+    }
+
+    $result = @{
+        Modulus  = [System.Convert]::ToBase64String($publicKey.Modulus)
+        Exponent = [System.Convert]::ToBase64String($publicKey.Exponent)
+    }
+
+    Write-Output $( ConvertTo-Json -InputObject $result -Depth 100 )
+`)
+
+//------------------------------------------------------------------------------
+
+// deleteSecretKey is only needed when the script that decrypts the secrets didn't run
+func deleteSecretKey(c *WindowsClient, key *secretKey) error {
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err := runner.Run(c, deleteSecretKeyScript, deleteSecretKeyArguments{
+        Container: key.Container,
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteSecretKey()] cannot delete secret key %q\n", key.Container)
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteSecretKey()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteSecretKey()] script stdout: \n%s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteSecretKey()] script stderr: \n%s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/deleteSecretKey()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/deleteSecretKey()] deleted secret key %q \n%s", key.Container, stdout.String())
+
+    return nil
+}
+
+type deleteSecretKeyArguments struct{
+    Container string
+}
+
+var deleteSecretKeyScript = script.New("deleteSecretKey", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $cspParameters = New-Object -TypeName 'System.Security.Cryptography.CspParameters'
+    $cspParameters.KeyContainerName = '{{.Container}}'
+    $cspParameters.Flags = [System.Security.Cryptography.CspProviderFlags]::UseMachineKeyStore -bor [System.Security.Cryptography.CspProviderFlags]::UseExistingKey
+
+    try {
+        $rsa = New-Object -TypeName 'System.Security.Cryptography.RSACryptoServiceProvider' -ArgumentList $cspParameters
+    }
+    catch {
+        # the key pair was already deleted
+        return
+    }
+    $rsa.PersistKeyInCsp = $false
+    $rsa.Clear()
+`)
@@ -0,0 +1,460 @@
+//
+// Copyright (c) 2019 Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-windows
+//
+package api
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "encoding/json"
+    "log"
+    "strings"
+
+    "github.com/stefaanc/golang-exec/runner"
+    "github.com/stefaanc/golang-exec/script"
+)
+
+//------------------------------------------------------------------------------
+
+type VNetworkAdapter struct {
+    Name                   string   // vnetwork-adapter of the management OS
+    VSwitchName            string
+
+    StaticMACAddress       string   // "" means dynamic MAC address
+    VlanID                 uint16   // 0 means untagged
+    MinimumBandwidthWeight uint32   // 0 means unchanged
+
+    // status
+    MACAddress             string
+    NetworkAdapterName     string
+    NetworkAdapterGUID     string
+}
+
+//------------------------------------------------------------------------------
+
+func (c *WindowsClient) CreateVNetworkAdapter(vnaProperties *VNetworkAdapter) error {
+    if vnaProperties.Name == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/CreateVNetworkAdapter(vnaProperties)] missing 'vnaProperties.Name'")
+    }
+    if vnaProperties.VSwitchName == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/CreateVNetworkAdapter(vnaProperties)] missing 'vnaProperties.VSwitchName'")
+    }
+    if vnaProperties.VlanID > 4094 {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/CreateVNetworkAdapter(vnaProperties)] invalid 'vnaProperties.VlanID', must be between 0 and 4094")
+    }
+    if vnaProperties.MinimumBandwidthWeight > 100 {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/CreateVNetworkAdapter(vnaProperties)] invalid 'vnaProperties.MinimumBandwidthWeight', must be between 0 and 100")
+    }
+
+    return createVNetworkAdapter(c, vnaProperties)
+}
+
+func (c *WindowsClient) ReadVNetworkAdapter(vnaQuery *VNetworkAdapter) (vnaProperties *VNetworkAdapter, err error) {
+    if vnaQuery.Name == "" {
+        return nil, fmt.Errorf("[ERROR][terraform-provider-windows/api/ReadVNetworkAdapter(vnaQuery)] missing 'vnaQuery.Name'")
+    }
+
+    return readVNetworkAdapter(c, vnaQuery)
+}
+
+func (c *WindowsClient) UpdateVNetworkAdapter(vnaQuery *VNetworkAdapter, vnaProperties *VNetworkAdapter) error {
+    if vnaQuery.Name == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/UpdateVNetworkAdapter(vnaQuery)] missing 'vnaQuery.Name'")
+    }
+    if vnaProperties.VlanID > 4094 {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/UpdateVNetworkAdapter(vnaProperties)] invalid 'vnaProperties.VlanID', must be between 0 and 4094")
+    }
+    if vnaProperties.MinimumBandwidthWeight > 100 {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/UpdateVNetworkAdapter(vnaProperties)] invalid 'vnaProperties.MinimumBandwidthWeight', must be between 0 and 100")
+    }
+
+    return updateVNetworkAdapter(c, vnaQuery, vnaProperties)
+}
+
+func (c *WindowsClient) DeleteVNetworkAdapter(vnaQuery *VNetworkAdapter) error {
+    if vnaQuery.Name == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/DeleteVNetworkAdapter(vnaQuery)] missing 'vnaQuery.Name'")
+    }
+
+    return deleteVNetworkAdapter(c, vnaQuery)
+}
+
+//------------------------------------------------------------------------------
+
+func createVNetworkAdapter(c *WindowsClient, vnaProperties *VNetworkAdapter) error {
+    // find id
+    id := vnaProperties.Name
+
+    // convert properties to JSON
+    vnaPropertiesJSON, err := json.Marshal(vnaProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/createVNetworkAdapter(vnaProperties)] cannot cannot convert 'vnaProperties' to json for vnetwork_adapter %#v\n", id)
+        return err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, createVNetworkAdapterScript, createVNetworkAdapterArguments{
+        VNAPropertiesJSON: string(vnaPropertiesJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/createVNetworkAdapter()] cannot create vnetwork_adapter %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/createVNetworkAdapter()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/createVNetworkAdapter()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/createVNetworkAdapter()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/createVNetworkAdapter()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/createVNetworkAdapter()] created vnetwork_adapter %#v\n", id)
+
+    return nil
+}
+
+type createVNetworkAdapterArguments struct{
+    VNAPropertiesJSON string
+}
+
+var createVNetworkAdapterScript = script.New("createVNetworkAdapter", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $vnaProperties = ConvertFrom-Json -InputObject '{{.VNAPropertiesJSON}}'
+    $name = $vnaProperties.Name
+
+    if ( -not ( Get-Command -Name 'Add-VMNetworkAdapter' -ErrorAction 'Ignore' ) ) {
+        throw "cannot create vnetwork_adapter '$name', Hyper-V is not installed on this windows-computer"
+    }
+    if ( Get-VMNetworkAdapter -ManagementOS -Name $name -ErrorAction 'Ignore' ) {
+        throw "cannot create vnetwork_adapter '$name', vnetwork_adapter already exists"
+    }
+
+    $arguments = @{}
+    if ( $vnaProperties.StaticMACAddress -ne "" ) {
+        $arguments.StaticMacAddress = $vnaProperties.StaticMACAddress -replace '[-:]', ''
+    }
+    Add-VMNetworkAdapter -ManagementOS -Name $name -SwitchName $vnaProperties.VSwitchName @arguments -Confirm:$false | Out-Null
+
+    if ( $vnaProperties.VlanID -ne 0 ) {
+        Set-VMNetworkAdapterVlan -ManagementOS -VMNetworkAdapterName $name -Access -VlanId $vnaProperties.VlanID -Confirm:$false | Out-Null
+    }
+    if ( $vnaProperties.MinimumBandwidthWeight -ne 0 ) {
+        Set-VMNetworkAdapter -ManagementOS -Name $name -MinimumBandwidthWeight $vnaProperties.MinimumBandwidthWeight -Confirm:$false | Out-Null
+    }
+`)
+
+//------------------------------------------------------------------------------
+
+func readVNetworkAdapter(c *WindowsClient, vnaQuery *VNetworkAdapter) (vnaProperties *VNetworkAdapter, err error) {
+    // find id
+    id := vnaQuery.Name
+
+    // convert query to JSON
+    vnaQueryJSON, err := json.Marshal(vnaQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/readVNetworkAdapter()] cannot cannot convert 'vnaQuery' to json for vnetwork_adapter %#v\n", id)
+        return nil, err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, readVNetworkAdapterScript, readVNetworkAdapterArguments{
+        VNAQueryJSON: string(vnaQueryJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/readVNetworkAdapter()] cannot read vnetwork_adapter %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/readVNetworkAdapter()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/readVNetworkAdapter()] script stdout: \n%s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/readVNetworkAdapter()] script stderr: \n%s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/readVNetworkAdapter()] runner: %s", stderr.String())
+        }
+
+        return nil, err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/readVNetworkAdapter()] read vnetwork_adapter %#v \n%s", id, stdout.String())
+
+    // convert stdout-JSON to vnaProperties
+    vnaProperties = new(VNetworkAdapter)
+    err = json.Unmarshal(stdout.Bytes(), vnaProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/readVNetworkAdapter()] cannot convert json to 'vnaProperties' for vnetwork_adapter %#v\n", id)
+        return nil, err
+    }
+
+    return vnaProperties, nil
+}
+
+type readVNetworkAdapterArguments struct{
+    VNAQueryJSON string
+}
+
+var readVNetworkAdapterScript = script.New("readVNetworkAdapter", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $vnaQuery = ConvertFrom-Json -InputObject '{{.VNAQueryJSON}}'
+    $name = $vnaQuery.Name
+
+    if ( Get-Command -Name 'Get-VMNetworkAdapter' -ErrorAction 'Ignore' ) {
+        $vnetworkAdapter = Get-VMNetworkAdapter -ManagementOS -Name $name -ErrorAction 'Ignore' | Select-Object -First 1
+    }
+    if ( -not $vnetworkAdapter ) {
+        throw "cannot find vnetwork_adapter '$name'"
+    }
+
+    # prepare result
+    $vnaProperties = @{
+        Name                   = $vnetworkAdapter.Name
+        VSwitchName            = "$( $vnetworkAdapter.SwitchName )"
+        StaticMACAddress       = ""
+        VlanID                 = [uint16]0
+        MinimumBandwidthWeight = [uint32]0
+        MACAddress             = $vnetworkAdapter.MacAddress -replace '..(?!$)', '$&-'
+        NetworkAdapterName     = ""
+        NetworkAdapterGUID     = ""
+    }
+
+    if ( -not $vnetworkAdapter.DynamicMacAddressEnabled ) {
+        $vnaProperties.StaticMACAddress = $vnaProperties.MACAddress
+    }
+
+    $vlan = Get-VMNetworkAdapterVlan -ManagementOS -VMNetworkAdapterName $name -ErrorAction 'Ignore' | Select-Object -First 1
+    if ( $vlan -and ( $vlan.OperationMode.ToString() -eq 'Access' ) ) {
+        $vnaProperties.VlanID = [uint16]$vlan.AccessVlanId
+    }
+
+    if ( $vnetworkAdapter.BandwidthSetting -and $vnetworkAdapter.BandwidthSetting.MinimumBandwidthWeight ) {
+        $vnaProperties.MinimumBandwidthWeight = [uint32]$vnetworkAdapter.BandwidthSetting.MinimumBandwidthWeight
+    }
+
+    $networkAdapter = Get-NetAdapter -IncludeHidden | where { $_.DeviceID -eq $vnetworkAdapter.DeviceID }
+    if ( $networkAdapter ) {
+        $vnaProperties.NetworkAdapterName = $networkAdapter.Name
+        $vnaProperties.NetworkAdapterGUID = $networkAdapter.InstanceID.Trim("{}")
+    }
+
+    Write-Output $( ConvertTo-Json -InputObject $vnaProperties -Depth 100 )
+`)
+
+//------------------------------------------------------------------------------
+
+func updateVNetworkAdapter(c *WindowsClient, vnaQuery *VNetworkAdapter, vnaProperties *VNetworkAdapter) error {
+    // find id
+    id := vnaQuery.Name
+
+    // convert query to JSON
+    vnaQueryJSON, err := json.Marshal(vnaQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/updateVNetworkAdapter(vnaQuery, vnaProperties)] cannot cannot convert 'vnaQuery' to json for vnetwork_adapter %#v\n", id)
+        return err
+    }
+
+    // convert properties to JSON
+    vnaPropertiesJSON, err := json.Marshal(vnaProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/updateVNetworkAdapter(vnaQuery, vnaProperties)] cannot cannot convert 'vnaProperties' to json for vnetwork_adapter %#v\n", id)
+        return err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, updateVNetworkAdapterScript, updateVNetworkAdapterArguments{
+        VNAQueryJSON:      string(vnaQueryJSON),
+        VNAPropertiesJSON: string(vnaPropertiesJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/updateVNetworkAdapter()] cannot update vnetwork_adapter %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/updateVNetworkAdapter()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/updateVNetworkAdapter()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/updateVNetworkAdapter()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/updateVNetworkAdapter()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/updateVNetworkAdapter()] updated vnetwork_adapter %#v\n", id)
+
+    return nil
+}
+
+type updateVNetworkAdapterArguments struct{
+    VNAQueryJSON      string
+    VNAPropertiesJSON string
+}
+
+var updateVNetworkAdapterScript = script.New("updateVNetworkAdapter", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $vnaQuery = ConvertFrom-Json -InputObject '{{.VNAQueryJSON}}'
+    $name = $vnaQuery.Name
+
+    if ( Get-Command -Name 'Get-VMNetworkAdapter' -ErrorAction 'Ignore' ) {
+        $vnetworkAdapter = Get-VMNetworkAdapter -ManagementOS -Name $name -ErrorAction 'Ignore' | Select-Object -First 1
+    }
+    if ( -not $vnetworkAdapter ) {
+        throw "cannot find vnetwork_adapter '$name'"
+    }
+
+    $vnaProperties = ConvertFrom-Json -InputObject '{{.VNAPropertiesJSON}}'
+
+    $vlan = Get-VMNetworkAdapterVlan -ManagementOS -VMNetworkAdapterName $name -ErrorAction 'Ignore' | Select-Object -First 1
+    $vlanID = 0
+    if ( $vlan -and ( $vlan.OperationMode.ToString() -eq 'Access' ) ) {
+        $vlanID = $vlan.AccessVlanId
+    }
+
+    $changesSwitch = ( $vnaProperties.VSwitchName -ne "" ) -and ( $vnaProperties.VSwitchName -ne $vnetworkAdapter.SwitchName )
+    $changesVlan   = ( $vnaProperties.VlanID -ne $vlanID )
+    if ( ( $changesSwitch -or $changesVlan ) -and $env:SSH_CONNECTION ) {
+        # refuse to move the vnetwork adapter that carries the ssh-connection of the provider
+        $sshAddress = ( $env:SSH_CONNECTION -split ' ' )[2]
+        $sshIPAddress = Get-NetIPAddress -IPAddress $sshAddress -ErrorAction 'Ignore' | Select-Object -First 1
+        if ( $sshIPAddress ) {
+            $sshNetworkAdapter = Get-NetAdapter -InterfaceIndex $sshIPAddress.InterfaceIndex -IncludeHidden -ErrorAction 'Ignore'
+            if ( $sshNetworkAdapter -and ( $sshNetworkAdapter.DeviceID -eq $vnetworkAdapter.DeviceID ) ) {
+                throw "cannot change vswitch or vlan of vnetwork_adapter '$name', vnetwork_adapter carries the ssh-connection of the provider"
+            }
+        }
+    }
+
+    if ( $changesSwitch ) {
+        Connect-VMNetworkAdapter -ManagementOS -Name $name -SwitchName $vnaProperties.VSwitchName -Confirm:$false | Out-Default
+    }
+
+    if ( $vnaProperties.StaticMACAddress -ne "" ) {
+        $staticMACAddress = $vnaProperties.StaticMACAddress -replace '[-:]', ''
+        if ( $vnetworkAdapter.DynamicMacAddressEnabled -or ( $vnetworkAdapter.MacAddress -ne $staticMACAddress ) ) {
+            Set-VMNetworkAdapter -ManagementOS -Name $name -StaticMacAddress $staticMACAddress -Confirm:$false | Out-Default
+        }
+    }
+    elseif ( -not $vnetworkAdapter.DynamicMacAddressEnabled ) {
+        Set-VMNetworkAdapter -ManagementOS -Name $name -DynamicMacAddress -Confirm:$false | Out-Default
+    }
+
+    if ( $changesVlan ) {
+        if ( $vnaProperties.VlanID -eq 0 ) {
+            Set-VMNetworkAdapterVlan -ManagementOS -VMNetworkAdapterName $name -Untagged -Confirm:$false | Out-Default
+        }
+        else {
+            Set-VMNetworkAdapterVlan -ManagementOS -VMNetworkAdapterName $name -Access -VlanId $vnaProperties.VlanID -Confirm:$false | Out-Default
+        }
+    }
+
+    if ( ( $vnaProperties.MinimumBandwidthWeight -ne 0 ) -and ( $vnaProperties.MinimumBandwidthWeight -ne $vnetworkAdapter.BandwidthSetting.MinimumBandwidthWeight ) ) {
+        Set-VMNetworkAdapter -ManagementOS -Name $name -MinimumBandwidthWeight $vnaProperties.MinimumBandwidthWeight -Confirm:$false | Out-Default
+    }
+`)
+
+//------------------------------------------------------------------------------
+
+func deleteVNetworkAdapter(c *WindowsClient, vnaQuery *VNetworkAdapter) error {
+    // find id
+    id := vnaQuery.Name
+
+    // convert query to JSON
+    vnaQueryJSON, err := json.Marshal(vnaQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteVNetworkAdapter(vnaQuery)] cannot cannot convert 'vnaQuery' to json for vnetwork_adapter %#v\n", id)
+        return err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, deleteVNetworkAdapterScript, deleteVNetworkAdapterArguments{
+        VNAQueryJSON: string(vnaQueryJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteVNetworkAdapter()] cannot delete vnetwork_adapter %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteVNetworkAdapter()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteVNetworkAdapter()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteVNetworkAdapter()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/deleteVNetworkAdapter()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/deleteVNetworkAdapter()] deleted vnetwork_adapter %#v\n", id)
+
+    return nil
+}
+
+type deleteVNetworkAdapterArguments struct{
+    VNAQueryJSON string
+}
+
+var deleteVNetworkAdapterScript = script.New("deleteVNetworkAdapter", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $vnaQuery = ConvertFrom-Json -InputObject '{{.VNAQueryJSON}}'
+    $name = $vnaQuery.Name
+
+    if ( Get-Command -Name 'Get-VMNetworkAdapter' -ErrorAction 'Ignore' ) {
+        $vnetworkAdapter = Get-VMNetworkAdapter -ManagementOS -Name $name -ErrorAction 'Ignore' | Select-Object -First 1
+    }
+    if ( -not $vnetworkAdapter ) {
+        return
+    }
+
+    if ( $env:SSH_CONNECTION ) {
+        # refuse to remove the vnetwork adapter that carries the ssh-connection of the provider
+        $sshAddress = ( $env:SSH_CONNECTION -split ' ' )[2]
+        $sshIPAddress = Get-NetIPAddress -IPAddress $sshAddress -ErrorAction 'Ignore' | Select-Object -First 1
+        if ( $sshIPAddress ) {
+            $sshNetworkAdapter = Get-NetAdapter -InterfaceIndex $sshIPAddress.InterfaceIndex -IncludeHidden -ErrorAction 'Ignore'
+            if ( $sshNetworkAdapter -and ( $sshNetworkAdapter.DeviceID -eq $vnetworkAdapter.DeviceID ) ) {
+                throw "cannot delete vnetwork_adapter '$name', vnetwork_adapter carries the ssh-connection of the provider"
+            }
+        }
+    }
+
+    Remove-VMNetworkAdapter -ManagementOS -Name $name -Confirm:$false | Out-Default
+`)
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,476 @@
+//
+// Copyright (c) 2019 Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-windows
+//
+package api
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "encoding/json"
+    "log"
+    "strings"
+
+    "github.com/stefaanc/golang-exec/runner"
+    "github.com/stefaanc/golang-exec/script"
+)
+
+//------------------------------------------------------------------------------
+
+type VSwitch struct {
+    Name                                string
+    SwitchType                          string     // "External", "Internal" or "Private"
+
+    NetworkAdapterNames                 []string   // only for external switches, multiple adapters require embedded teaming
+    AllowManagementOS                   bool       // only for external switches
+    EnableEmbeddedTeaming               bool       // switch embedded teaming (SET)
+
+    BandwidthReservationMode            string     // "Absolute", "Weight" or "None", cannot be changed after creation
+    DefaultFlowMinimumBandwidthWeight   uint32     // only for "Weight" mode, 0 means unchanged
+    DefaultFlowMinimumBandwidthAbsolute uint64     // only for "Absolute" mode, in bits per second, 0 means unchanged
+
+    // status
+    GUID                                string
+}
+
+//------------------------------------------------------------------------------
+
+func (c *WindowsClient) CreateVSwitch(vsProperties *VSwitch) error {
+    if vsProperties.Name == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/CreateVSwitch(vsProperties)] missing 'vsProperties.Name'")
+    }
+    if ( vsProperties.SwitchType != "External" ) &&
+       ( vsProperties.SwitchType != "Internal" ) &&
+       ( vsProperties.SwitchType != "Private" ) {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/CreateVSwitch(vsProperties)] invalid 'vsProperties.SwitchType', must be \"External\", \"Internal\" or \"Private\"")
+    }
+    if ( vsProperties.SwitchType == "External" ) && ( len(vsProperties.NetworkAdapterNames) == 0 ) {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/CreateVSwitch(vsProperties)] missing 'vsProperties.NetworkAdapterNames' for an external vswitch")
+    }
+    if ( vsProperties.SwitchType != "External" ) && ( len(vsProperties.NetworkAdapterNames) > 0 ) {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/CreateVSwitch(vsProperties)] invalid 'vsProperties.NetworkAdapterNames', only an external vswitch can be bound to network adapters")
+    }
+    if ( len(vsProperties.NetworkAdapterNames) > 1 ) && !vsProperties.EnableEmbeddedTeaming {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/CreateVSwitch(vsProperties)] invalid 'vsProperties.NetworkAdapterNames', multiple network adapters require 'vsProperties.EnableEmbeddedTeaming'")
+    }
+
+    return createVSwitch(c, vsProperties)
+}
+
+func (c *WindowsClient) ReadVSwitch(vsQuery *VSwitch) (vsProperties *VSwitch, err error) {
+    if vsQuery.Name == "" {
+        return nil, fmt.Errorf("[ERROR][terraform-provider-windows/api/ReadVSwitch(vsQuery)] missing 'vsQuery.Name'")
+    }
+
+    return readVSwitch(c, vsQuery)
+}
+
+func (c *WindowsClient) UpdateVSwitch(vsQuery *VSwitch, vsProperties *VSwitch) error {
+    if vsQuery.Name == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/UpdateVSwitch(vsQuery)] missing 'vsQuery.Name'")
+    }
+
+    return updateVSwitch(c, vsQuery, vsProperties)
+}
+
+func (c *WindowsClient) DeleteVSwitch(vsQuery *VSwitch) error {
+    if vsQuery.Name == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows/api/DeleteVSwitch(vsQuery)] missing 'vsQuery.Name'")
+    }
+
+    return deleteVSwitch(c, vsQuery)
+}
+
+//------------------------------------------------------------------------------
+
+func createVSwitch(c *WindowsClient, vsProperties *VSwitch) error {
+    // find id
+    id := vsProperties.Name
+
+    // convert properties to JSON
+    vsPropertiesJSON, err := json.Marshal(vsProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/createVSwitch(vsProperties)] cannot cannot convert 'vsProperties' to json for vswitch %#v\n", id)
+        return err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, createVSwitchScript, createVSwitchArguments{
+        VSPropertiesJSON: string(vsPropertiesJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/createVSwitch()] cannot create vswitch %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/createVSwitch()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/createVSwitch()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/createVSwitch()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/createVSwitch()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/createVSwitch()] created vswitch %#v\n", id)
+
+    return nil
+}
+
+type createVSwitchArguments struct{
+    VSPropertiesJSON string
+}
+
+var createVSwitchScript = script.New("createVSwitch", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $vsProperties = ConvertFrom-Json -InputObject '{{.VSPropertiesJSON}}'
+    $name = $vsProperties.Name
+
+    if ( -not ( Get-Command -Name 'New-VMSwitch' -ErrorAction 'Ignore' ) ) {
+        throw "cannot create vswitch '$name', Hyper-V is not installed on this windows-computer"
+    }
+    if ( Get-VMSwitch -Name $name -ErrorAction 'Ignore' ) {
+        throw "cannot create vswitch '$name', vswitch already exists"
+    }
+
+    $arguments = @{
+        Name = $name
+    }
+    if ( $vsProperties.BandwidthReservationMode -ne "" ) {
+        $arguments.MinimumBandwidthMode = $vsProperties.BandwidthReservationMode
+    }
+    if ( $vsProperties.SwitchType -eq 'External' ) {
+        $arguments.NetAdapterName        = @( $vsProperties.NetworkAdapterNames )
+        $arguments.AllowManagementOS     = $vsProperties.AllowManagementOS
+        $arguments.EnableEmbeddedTeaming = $vsProperties.EnableEmbeddedTeaming
+    }
+    else {
+        $arguments.SwitchType = $vsProperties.SwitchType
+    }
+    New-VMSwitch @arguments -Confirm:$false | Out-Null
+
+    $arguments = @{}
+    if ( $vsProperties.DefaultFlowMinimumBandwidthWeight   -ne 0 ) { $arguments.DefaultFlowMinimumBandwidthWeight   = $vsProperties.DefaultFlowMinimumBandwidthWeight   }
+    if ( $vsProperties.DefaultFlowMinimumBandwidthAbsolute -ne 0 ) { $arguments.DefaultFlowMinimumBandwidthAbsolute = $vsProperties.DefaultFlowMinimumBandwidthAbsolute }
+    if ( $arguments.Count -gt 0 ) {
+        Set-VMSwitch -Name $name @arguments -Confirm:$false | Out-Null
+    }
+`)
+
+//------------------------------------------------------------------------------
+
+func readVSwitch(c *WindowsClient, vsQuery *VSwitch) (vsProperties *VSwitch, err error) {
+    // find id
+    id := vsQuery.Name
+
+    // convert query to JSON
+    vsQueryJSON, err := json.Marshal(vsQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/readVSwitch()] cannot cannot convert 'vsQuery' to json for vswitch %#v\n", id)
+        return nil, err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, readVSwitchScript, readVSwitchArguments{
+        VSQueryJSON: string(vsQueryJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/readVSwitch()] cannot read vswitch %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/readVSwitch()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/readVSwitch()] script stdout: \n%s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/readVSwitch()] script stderr: \n%s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/readVSwitch()] runner: %s", stderr.String())
+        }
+
+        return nil, err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/readVSwitch()] read vswitch %#v \n%s", id, stdout.String())
+
+    // convert stdout-JSON to vsProperties
+    vsProperties = new(VSwitch)
+    err = json.Unmarshal(stdout.Bytes(), vsProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/readVSwitch()] cannot convert json to 'vsProperties' for vswitch %#v\n", id)
+        return nil, err
+    }
+
+    return vsProperties, nil
+}
+
+type readVSwitchArguments struct{
+    VSQueryJSON string
+}
+
+var readVSwitchScript = script.New("readVSwitch", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $vsQuery = ConvertFrom-Json -InputObject '{{.VSQueryJSON}}'
+    $name = $vsQuery.Name
+
+    if ( Get-Command -Name 'Get-VMSwitch' -ErrorAction 'Ignore' ) {
+        $vswitch = Get-VMSwitch -Name $name -ErrorAction 'Ignore'
+    }
+    if ( -not $vswitch ) {
+        throw "cannot find vswitch '$name'"
+    }
+
+    # prepare result
+    $vsProperties = @{
+        Name                                = $vswitch.Name
+        SwitchType                          = $vswitch.SwitchType.ToString()
+        NetworkAdapterNames                 = @()
+        AllowManagementOS                   = $vswitch.AllowManagementOS
+        EnableEmbeddedTeaming               = [bool]$vswitch.EmbeddedTeamingEnabled
+        BandwidthReservationMode            = $vswitch.BandwidthReservationMode.ToString()
+        DefaultFlowMinimumBandwidthWeight   = [uint32]$vswitch.DefaultFlowMinimumBandwidthWeight
+        DefaultFlowMinimumBandwidthAbsolute = [uint64]$vswitch.DefaultFlowMinimumBandwidthAbsolute
+        GUID                                = $vswitch.Id.ToString().ToUpper()
+    }
+
+    $descriptions = @( $vswitch.NetAdapterInterfaceDescriptions | where { $_ } )
+    if ( ( $descriptions.Count -eq 0 ) -and $vswitch.NetAdapterInterfaceDescription ) {
+        $descriptions = @( $vswitch.NetAdapterInterfaceDescription )
+    }
+    foreach ( $description in $descriptions ) {
+        $networkAdapter = Get-NetAdapter -InterfaceDescription $description -ErrorAction 'Ignore'
+        if ( $networkAdapter ) {
+            $vsProperties.NetworkAdapterNames += $networkAdapter.Name
+        }
+    }
+    $vsProperties.NetworkAdapterNames = @( $vsProperties.NetworkAdapterNames | Sort-Object )
+
+    Write-Output $( ConvertTo-Json -InputObject $vsProperties -Depth 100 )
+`)
+
+//------------------------------------------------------------------------------
+
+func updateVSwitch(c *WindowsClient, vsQuery *VSwitch, vsProperties *VSwitch) error {
+    // find id
+    id := vsQuery.Name
+
+    // convert query to JSON
+    vsQueryJSON, err := json.Marshal(vsQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/updateVSwitch(vsQuery, vsProperties)] cannot cannot convert 'vsQuery' to json for vswitch %#v\n", id)
+        return err
+    }
+
+    // convert properties to JSON
+    vsPropertiesJSON, err := json.Marshal(vsProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/updateVSwitch(vsQuery, vsProperties)] cannot cannot convert 'vsProperties' to json for vswitch %#v\n", id)
+        return err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, updateVSwitchScript, updateVSwitchArguments{
+        VSQueryJSON:      string(vsQueryJSON),
+        VSPropertiesJSON: string(vsPropertiesJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/updateVSwitch()] cannot update vswitch %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/updateVSwitch()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/updateVSwitch()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/updateVSwitch()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/updateVSwitch()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/updateVSwitch()] updated vswitch %#v\n", id)
+
+    return nil
+}
+
+type updateVSwitchArguments struct{
+    VSQueryJSON      string
+    VSPropertiesJSON string
+}
+
+var updateVSwitchScript = script.New("updateVSwitch", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $vsQuery = ConvertFrom-Json -InputObject '{{.VSQueryJSON}}'
+    $name = $vsQuery.Name
+
+    if ( Get-Command -Name 'Get-VMSwitch' -ErrorAction 'Ignore' ) {
+        $vswitch = Get-VMSwitch -Name $name -ErrorAction 'Ignore'
+    }
+    if ( -not $vswitch ) {
+        throw "cannot find vswitch '$name'"
+    }
+
+    $vsProperties = ConvertFrom-Json -InputObject '{{.VSPropertiesJSON}}'
+
+    if ( ( -not $vsProperties.AllowManagementOS ) -and $vswitch.AllowManagementOS -and ( $vswitch.SwitchType.ToString() -eq 'External' ) -and $env:SSH_CONNECTION ) {
+        # refuse to remove the vnetwork adapter of the management OS that carries the ssh-connection of the provider
+        $sshAddress = ( $env:SSH_CONNECTION -split ' ' )[2]
+        $sshIPAddress = Get-NetIPAddress -IPAddress $sshAddress -ErrorAction 'Ignore' | Select-Object -First 1
+        if ( $sshIPAddress ) {
+            $sshNetworkAdapter = Get-NetAdapter -InterfaceIndex $sshIPAddress.InterfaceIndex -IncludeHidden -ErrorAction 'Ignore'
+            $sshVNetworkAdapter = Get-VMNetworkAdapter -ManagementOS -ErrorAction 'Ignore' | where { $_.DeviceID -eq $sshNetworkAdapter.DeviceID }
+            if ( $sshVNetworkAdapter -and ( $sshVNetworkAdapter.SwitchName -eq $name ) ) {
+                throw "cannot disallow management OS for vswitch '$name', vswitch carries the ssh-connection of the provider"
+            }
+        }
+    }
+
+    if ( ( $vswitch.SwitchType.ToString() -eq 'External' ) -and ( $vsProperties.NetworkAdapterNames.Count -gt 0 ) ) {
+        $descriptions = @( $vswitch.NetAdapterInterfaceDescriptions | where { $_ } )
+        if ( ( $descriptions.Count -eq 0 ) -and $vswitch.NetAdapterInterfaceDescription ) {
+            $descriptions = @( $vswitch.NetAdapterInterfaceDescription )
+        }
+        $currentNames = @( $descriptions | foreach { ( Get-NetAdapter -InterfaceDescription $_ -ErrorAction 'Ignore' ).Name } )
+
+        if ( $vswitch.EmbeddedTeamingEnabled ) {
+            # add new team members before removing old team members, so the vswitch never becomes unbound
+            foreach ( $networkAdapterName in $vsProperties.NetworkAdapterNames ) {
+                if ( $currentNames -notcontains $networkAdapterName ) {
+                    Add-VMSwitchTeamMember -VMSwitchName $name -NetAdapterName $networkAdapterName -Confirm:$false | Out-Default
+                }
+            }
+            foreach ( $networkAdapterName in $currentNames ) {
+                if ( $vsProperties.NetworkAdapterNames -notcontains $networkAdapterName ) {
+                    Remove-VMSwitchTeamMember -VMSwitchName $name -NetAdapterName $networkAdapterName -Confirm:$false | Out-Default
+                }
+            }
+        }
+        else {
+            if ( $vsProperties.NetworkAdapterNames.Count -gt 1 ) {
+                throw "cannot update vswitch '$name', multiple network adapters require embedded teaming"
+            }
+            if ( $currentNames -notcontains $vsProperties.NetworkAdapterNames[0] ) {
+                Set-VMSwitch -Name $name -NetAdapterName $vsProperties.NetworkAdapterNames[0] -AllowManagementOS $vsProperties.AllowManagementOS -Confirm:$false | Out-Default
+            }
+        }
+    }
+
+    $arguments = @{}
+    if ( ( $vswitch.SwitchType.ToString() -eq 'External' ) -and ( $vsProperties.AllowManagementOS -ne $vswitch.AllowManagementOS ) ) {
+        $arguments.AllowManagementOS = $vsProperties.AllowManagementOS
+    }
+    if ( ( $vsProperties.DefaultFlowMinimumBandwidthWeight -ne 0 ) -and ( $vsProperties.DefaultFlowMinimumBandwidthWeight -ne $vswitch.DefaultFlowMinimumBandwidthWeight ) ) {
+        $arguments.DefaultFlowMinimumBandwidthWeight = $vsProperties.DefaultFlowMinimumBandwidthWeight
+    }
+    if ( ( $vsProperties.DefaultFlowMinimumBandwidthAbsolute -ne 0 ) -and ( $vsProperties.DefaultFlowMinimumBandwidthAbsolute -ne $vswitch.DefaultFlowMinimumBandwidthAbsolute ) ) {
+        $arguments.DefaultFlowMinimumBandwidthAbsolute = $vsProperties.DefaultFlowMinimumBandwidthAbsolute
+    }
+    if ( $arguments.Count -gt 0 ) {
+        Set-VMSwitch -Name $name @arguments -Confirm:$false | Out-Default
+    }
+`)
+
+//------------------------------------------------------------------------------
+
+func deleteVSwitch(c *WindowsClient, vsQuery *VSwitch) error {
+    // find id
+    id := vsQuery.Name
+
+    // convert query to JSON
+    vsQueryJSON, err := json.Marshal(vsQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteVSwitch(vsQuery)] cannot cannot convert 'vsQuery' to json for vswitch %#v\n", id)
+        return err
+    }
+
+    // create buffer to capture stdout & stderr
+    var stdout bytes.Buffer
+    var stderr bytes.Buffer
+
+    // run script
+    c.Lock.Lock()
+    err = runner.Run(c, deleteVSwitchScript, deleteVSwitchArguments{
+        VSQueryJSON: string(vsQueryJSON),
+    }, &stdout, &stderr)
+    c.Lock.Unlock()
+    if err != nil {
+        var runnerErr runner.Error
+        errors.As(err, &runnerErr)
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteVSwitch()] cannot delete vswitch %#v\n", id)
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteVSwitch()] script exitcode: %d", runnerErr.ExitCode())
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteVSwitch()] script stdout: %s", stdout.String())
+        log.Printf("[ERROR][terraform-provider-windows/api/deleteVSwitch()] script stderr: %s", stderr.String())
+
+        // get to the cause of a "runner failed" error to display in terraform UI
+        if strings.Contains(runnerErr.Error(), "runner failed") {
+            err = fmt.Errorf("[terraform-provider-windows/api/deleteVSwitch()] runner: %s", stderr.String())
+        }
+
+        return err
+    }
+    log.Printf("[INFO][terraform-provider-windows/api/deleteVSwitch()] deleted vswitch %#v\n", id)
+
+    return nil
+}
+
+type deleteVSwitchArguments struct{
+    VSQueryJSON string
+}
+
+var deleteVSwitchScript = script.New("deleteVSwitch", "powershell", `
+    $ErrorActionPreference = 'Stop'
+    $ProgressPreference = 'SilentlyContinue'   # progress-bar fails when using ssh
+
+    $vsQuery = ConvertFrom-Json -InputObject '{{.VSQueryJSON}}'
+    $name = $vsQuery.Name
+
+    if ( Get-Command -Name 'Get-VMSwitch' -ErrorAction 'Ignore' ) {
+        $vswitch = Get-VMSwitch -Name $name -ErrorAction 'Ignore'
+    }
+    if ( -not $vswitch ) {
+        return
+    }
+
+    if ( $env:SSH_CONNECTION ) {
+        # refuse to remove the vswitch that carries the ssh-connection of the provider
+        $sshAddress = ( $env:SSH_CONNECTION -split ' ' )[2]
+        $sshIPAddress = Get-NetIPAddress -IPAddress $sshAddress -ErrorAction 'Ignore' | Select-Object -First 1
+        if ( $sshIPAddress ) {
+            $sshNetworkAdapter = Get-NetAdapter -InterfaceIndex $sshIPAddress.InterfaceIndex -IncludeHidden -ErrorAction 'Ignore'
+            $sshVNetworkAdapter = Get-VMNetworkAdapter -ManagementOS -ErrorAction 'Ignore' | where { $_.DeviceID -eq $sshNetworkAdapter.DeviceID }
+            if ( $sshVNetworkAdapter -and ( $sshVNetworkAdapter.SwitchName -eq $name ) ) {
+                throw "cannot delete vswitch '$name', vswitch carries the ssh-connection of the provider"
+            }
+        }
+    }
+
+    Remove-VMSwitch -Name $name -Force -Confirm:$false | Out-Default
+`)
+
+//------------------------------------------------------------------------------
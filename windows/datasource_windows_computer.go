@@ -9,10 +9,12 @@ package windows
 import (
     "fmt"
     "log"
+    "strings"
 
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 
     "github.com/stefaanc/terraform-provider-windows/api"
+    "github.com/stefaanc/terraform-provider-windows/windows/tfutil"
 )
 
 //------------------------------------------------------------------------------
@@ -36,6 +38,26 @@ func dataSourceWindowsComputer() *schema.Resource {
                 Elem: dataSourceWindowsComputerDNSClient(),
             },
 
+            "domain_membership": &schema.Schema{
+                Type:     schema.TypeList,
+                MaxItems: 1,
+                Computed: true,
+                Elem: dataSourceWindowsComputerDomainMembership(),
+            },
+
+            "operating_system": &schema.Schema{
+                Type:     schema.TypeList,
+                MaxItems: 1,
+                Computed: true,
+                Elem: dataSourceWindowsComputerOperatingSystem(),
+            },
+            "hardware": &schema.Schema{
+                Type:     schema.TypeList,
+                MaxItems: 1,
+                Computed: true,
+                Elem: dataSourceWindowsComputerHardware(),
+            },
+
             "reboot_pending": &schema.Schema{
                 Type:     schema.TypeBool,
                 Computed: true,
@@ -57,6 +79,8 @@ func dataSourceWindowsComputer() *schema.Resource {
                 Computed: true,
                 Elem:     &schema.Schema{ Type: schema.TypeString },
             },
+
+            "x_lifecycle": &tfutil.DataSourceXLifecycleSchema,
         },
 
         Read:   dataSourceWindowsComputerRead,
@@ -79,6 +103,143 @@ func dataSourceWindowsComputerDNSClient() *schema.Resource {
                 Type:     schema.TypeInt,   // uint32
                 Computed: true,
             },
+            "primary_suffix": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "change_primary_suffix_with_membership": &schema.Schema{
+                Type:     schema.TypeBool,
+                Computed: true,
+            },
+            "doh_server": &schema.Schema{
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem: &schema.Resource{
+                    Schema: map[string]*schema.Schema{
+                        "server_address": &schema.Schema{
+                            Type:     schema.TypeString,
+                            Computed: true,
+                        },
+                        "doh_template": &schema.Schema{
+                            Type:     schema.TypeString,
+                            Computed: true,
+                        },
+                        "allow_fallback_to_udp": &schema.Schema{
+                            Type:     schema.TypeBool,
+                            Computed: true,
+                        },
+                        "auto_upgrade": &schema.Schema{
+                            Type:     schema.TypeBool,
+                            Computed: true,
+                        },
+                    },
+                },
+            },
+        },
+    }
+}
+
+func dataSourceWindowsComputerDomainMembership() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "domain": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "workgroup": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "part_of_domain": &schema.Schema{
+                Type:     schema.TypeBool,
+                Computed: true,
+            },
+        },
+    }
+}
+
+func dataSourceWindowsComputerOperatingSystem() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "caption": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "edition": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "version": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "display_version": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "build": &schema.Schema{
+                Type:     schema.TypeInt,   // uint32
+                Computed: true,
+            },
+            "ubr": &schema.Schema{
+                Type:     schema.TypeInt,   // uint32
+                Computed: true,
+            },
+            "install_date": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "last_boot_time": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "uptime": &schema.Schema{
+                Type:     schema.TypeInt,   // uint64, seconds
+                Computed: true,
+            },
+            "domain_role": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "system_locale": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "time_zone": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+        },
+    }
+}
+
+func dataSourceWindowsComputerHardware() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "manufacturer": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "model": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "serial_number": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "processor_count": &schema.Schema{
+                Type:     schema.TypeInt,   // uint32
+                Computed: true,
+            },
+            "logical_processor_count": &schema.Schema{
+                Type:     schema.TypeInt,   // uint32
+                Computed: true,
+            },
+            "total_memory": &schema.Schema{
+                Type:     schema.TypeInt,   // uint64, bytes
+                Computed: true,
+            },
         },
     }
 }
@@ -152,14 +313,54 @@ func dataSourceWindowsComputerRead(d *schema.ResourceData, m interface{}) error
 
     log.Printf("[INFO][terraform-provider-windows] reading windows_computer %q\n", id)
 
+    x_lifecycle := tfutil.GetResource(d, "x_lifecycle")
+
     // read
-    computer, err := c.ReadComputer()
+    // lifecycle customizations: wait_until_exists
+    // the computer always exists, but it cannot be connected to while it is booting
+    var computer *api.Computer
+    err := tfutil.WaitUntilExists(x_lifecycle, "cannot connect to computer", func() (err error) {
+        computer, err = c.ReadComputer()
+        return err
+    })
     if err != nil {
+        // lifecycle customizations: ignore_error_if_not_exists
+        v, ok := x_lifecycle["ignore_error_if_not_exists"]
+        if ok && v.(bool) && strings.Contains(err.Error(), "cannot connect to computer") {
+            log.Printf("[INFO][terraform-provider-windows] cannot import windows_computer %q into terraform state\n", id)
+
+            // set zeroed properties
+            d.Set("name", "")
+            d.Set("new_name", "")
+            d.Set("dns_client", nil)
+            d.Set("domain_membership", nil)
+            d.Set("operating_system", nil)
+            d.Set("hardware", nil)
+            d.Set("reboot_pending", false)
+            d.Set("reboot_pending_details", nil)
+            d.Set("network_adapter_names", nil)
+            d.Set("network_connection_names", nil)
+
+            // set computed lifecycle properties
+            x_lifecycle["exists"] = false
+            d.Set("x_lifecycle", []interface{}{ x_lifecycle })
+
+            // set id
+            d.SetId(id)
+
+            log.Printf("[INFO][terraform-provider-windows] ignored error and added zeroed windows_computer %q to terraform state\n", id)
+            return nil
+        }
+
         // no lifecycle customizations
         log.Printf("[ERROR][terraform-provider-windows] cannot read windows_computer %q\n", id)
         return err
     }
 
+    // set computed lifecycle properties
+    x_lifecycle["exists"] = true
+    d.Set("x_lifecycle", []interface{}{ x_lifecycle })
+
     // set properties
     setDataComputerProperties(d, computer)
 
@@ -178,8 +379,41 @@ func setDataComputerProperties(d *schema.ResourceData, cProperties *api.Computer
     dnsClient["suffix_search_list"] = cProperties.DNSClient.SuffixSearchList
     dnsClient["enable_devolution"]  = cProperties.DNSClient.EnableDevolution
     dnsClient["devolution_level"]   = cProperties.DNSClient.DevolutionLevel
+    dnsClient["primary_suffix"]     = cProperties.DNSClient.PrimarySuffix
+    dnsClient["change_primary_suffix_with_membership"] = cProperties.DNSClient.ChangePrimarySuffixWithMembership
+    dnsClient["doh_server"]         = flattenComputerDoHServers(cProperties.DNSClient.DoHServers)
     d.Set("dns_client", []interface{}{ dnsClient })
 
+    domainMembership := make(map[string]interface{})
+    domainMembership["domain"]         = cProperties.DomainMembership.Domain
+    domainMembership["workgroup"]      = cProperties.DomainMembership.Workgroup
+    domainMembership["part_of_domain"] = cProperties.DomainMembership.PartOfDomain
+    d.Set("domain_membership", []interface{}{ domainMembership })
+
+    operatingSystem := make(map[string]interface{})
+    operatingSystem["caption"]         = cProperties.OperatingSystem.Caption
+    operatingSystem["edition"]         = cProperties.OperatingSystem.Edition
+    operatingSystem["version"]         = cProperties.OperatingSystem.Version
+    operatingSystem["display_version"] = cProperties.OperatingSystem.DisplayVersion
+    operatingSystem["build"]           = cProperties.OperatingSystem.Build
+    operatingSystem["ubr"]             = cProperties.OperatingSystem.UBR
+    operatingSystem["install_date"]    = cProperties.OperatingSystem.InstallDate
+    operatingSystem["last_boot_time"]  = cProperties.OperatingSystem.LastBootTime
+    operatingSystem["uptime"]          = cProperties.OperatingSystem.Uptime
+    operatingSystem["domain_role"]     = cProperties.OperatingSystem.DomainRole
+    operatingSystem["system_locale"]   = cProperties.OperatingSystem.SystemLocale
+    operatingSystem["time_zone"]       = cProperties.OperatingSystem.TimeZone
+    d.Set("operating_system", []interface{}{ operatingSystem })
+
+    hardware := make(map[string]interface{})
+    hardware["manufacturer"]            = cProperties.Hardware.Manufacturer
+    hardware["model"]                   = cProperties.Hardware.Model
+    hardware["serial_number"]           = cProperties.Hardware.SerialNumber
+    hardware["processor_count"]         = cProperties.Hardware.ProcessorCount
+    hardware["logical_processor_count"] = cProperties.Hardware.LogicalProcessorCount
+    hardware["total_memory"]            = cProperties.Hardware.TotalMemory
+    d.Set("hardware", []interface{}{ hardware })
+
     d.Set("reboot_pending", cProperties.RebootPending)
 
     rebootPendingDetails := make(map[string]interface{})
@@ -71,6 +71,18 @@ func dataSourceWindowsNetworkAdapter() *schema.Resource {
                 Type:     schema.TypeBool,
                 Computed: true,
             },
+            "is_team_member": &schema.Schema{
+                Type:     schema.TypeBool,
+                Computed: true,
+            },
+            "is_team_interface": &schema.Schema{
+                Type:     schema.TypeBool,
+                Computed: true,
+            },
+            "team_name": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
 
             // lifecycle customizations that are not supported by the 'lifecycle' meta-argument for persistent resources (similar to data-sources)
             "x_lifecycle": &tfutil.DataSourceXLifecycleSchema,
@@ -91,6 +103,17 @@ func dataSourceWindowsNetworkAdapterDNSClient() *schema.Resource {
                 Type:     schema.TypeString,
                 Computed: true,
             },
+            "server_addresses": &schema.Schema{
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem: &schema.Schema{
+                    Type: schema.TypeString,
+                },
+            },
+            "reset_to_dhcp": &schema.Schema{
+                Type:     schema.TypeBool,
+                Computed: true,
+            },
         },
     }
 }
@@ -122,7 +145,12 @@ func dataSourceWindowsNetworkAdapterRead(d *schema.ResourceData, m interface{})
     naQuery.GUID    = d.Get("guid").(string)
     naQuery.Name    = d.Get("name").(string)
 
-    networkAdapter, err := c.ReadNetworkAdapter(naQuery)
+    // lifecycle customizations: wait_until_exists
+    var networkAdapter *api.NetworkAdapter
+    err := tfutil.WaitUntilExists(x_lifecycle, "cannot find network_adapter", func() (err error) {
+        networkAdapter, err = c.ReadNetworkAdapter(naQuery)
+        return err
+    })
     if err != nil {
         // lifecycle customizations: ignore_error_if_not_exists
         v, ok := x_lifecycle["ignore_error_if_not_exists"]
@@ -139,6 +167,9 @@ func dataSourceWindowsNetworkAdapterRead(d *schema.ResourceData, m interface{})
             d.Set("connection_status", "")
             d.Set("connection_speed", "")
             d.Set("is_physical", false)
+            d.Set("is_team_member", false)
+            d.Set("is_team_interface", false)
+            d.Set("team_name", "")
 
             // set computed lifecycle properties
             x_lifecycle["exists"] = false
@@ -186,6 +217,8 @@ func setDataNetworkAdapterProperties(d *schema.ResourceData, naProperties *api.N
         } else {
             dnsClient["register_connection_suffix"] = naProperties.DNSClient[0].RegisterConnectionSuffix
         }
+        dnsClient["server_addresses"] = naProperties.DNSClient[0].ServerAddresses
+        dnsClient["reset_to_dhcp"]    = naProperties.DNSClient[0].ResetToDHCP
         d.Set("dns_client", []interface{}{ dnsClient })
     } else {
         d.Set("dns_client", []interface{}{ })
@@ -196,6 +229,9 @@ func setDataNetworkAdapterProperties(d *schema.ResourceData, naProperties *api.N
     d.Set("connection_status", naProperties.ConnectionStatus)
     d.Set("connection_speed", naProperties.ConnectionSpeed)
     d.Set("is_physical", naProperties.IsPhysical)
+    d.Set("is_team_member", naProperties.IsTeamMember)
+    d.Set("is_team_interface", naProperties.IsTeamInterface)
+    d.Set("team_name", naProperties.TeamName)
 }
 
 //------------------------------------------------------------------------------
@@ -0,0 +1,174 @@
+//
+// Copyright (c) 2019 Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-windows
+//
+package windows
+
+import (
+    "fmt"
+    "log"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+    "github.com/stefaanc/terraform-provider-windows/api"
+    "github.com/stefaanc/terraform-provider-windows/windows/tfutil"
+)
+
+//------------------------------------------------------------------------------
+
+func dataSourceWindowsNetworkAdapterAdvancedProperties() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "guid": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Computed: true,
+
+                ValidateFunc: tfutil.ValidateUUID(),
+                StateFunc: tfutil.StateToUpper(),
+            },
+            "name": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Computed: true,
+
+                ConflictsWith: []string{ "guid" },
+            },
+
+            "advanced_properties": &schema.Schema{
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem: dataSourceWindowsNetworkAdapterAdvancedPropertiesAdvancedProperty(),
+            },
+        },
+
+        Read: dataSourceWindowsNetworkAdapterAdvancedPropertiesRead,
+    }
+}
+
+func dataSourceWindowsNetworkAdapterAdvancedPropertiesAdvancedProperty() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "registry_keyword": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "registry_value": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "display_name": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "display_value": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "default_registry_value": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "valid_registry_values": &schema.Schema{
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem: &schema.Schema{
+                    Type: schema.TypeString,
+                },
+            },
+            "valid_display_values": &schema.Schema{
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem: &schema.Schema{
+                    Type: schema.TypeString,
+                },
+            },
+            "numeric_min_value": &schema.Schema{
+                Type:     schema.TypeInt,
+                Computed: true,
+            },
+            "numeric_max_value": &schema.Schema{
+                Type:     schema.TypeInt,
+                Computed: true,
+            },
+            "numeric_step_value": &schema.Schema{
+                Type:     schema.TypeInt,
+                Computed: true,
+            },
+        },
+    }
+}
+
+//------------------------------------------------------------------------------
+
+func dataSourceWindowsNetworkAdapterAdvancedPropertiesRead(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    guid            := d.Get("guid").(string)
+    name            := d.Get("name").(string)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    var id string
+    if guid    != "" { id = guid    } else
+    if name    != "" { id = name    }
+    id = fmt.Sprintf("//%s/network_adapters/%s/advanced_properties", host, id)
+
+    log.Printf("[INFO][terraform-provider-windows] reading windows_network_adapter_advanced_properties %q\n", id)
+
+    // read
+    naQuery := new(api.NetworkAdapter)
+    naQuery.GUID    = guid
+    naQuery.Name    = name
+
+    networkAdapter, err := c.ReadNetworkAdapter(naQuery)
+    if err != nil {
+        log.Printf("[INFO][terraform-provider-windows] cannot read windows_network_adapter_advanced_properties %q\n", id)
+        return err
+    }
+
+    advancedProperties, err := c.ReadNetworkAdapterAdvancedProperties(naQuery)
+    if err != nil {
+        log.Printf("[INFO][terraform-provider-windows] cannot read windows_network_adapter_advanced_properties %q\n", id)
+        return err
+    }
+
+    // set properties
+    d.Set("guid", networkAdapter.GUID)
+    d.Set("name", networkAdapter.Name)
+    setDataNetworkAdapterAdvancedPropertiesProperties(d, advancedProperties)
+
+    // set id
+    d.SetId(id)
+
+    log.Printf("[INFO][terraform-provider-windows] read windows_network_adapter_advanced_properties %q\n", id)
+    return nil
+}
+
+//------------------------------------------------------------------------------
+
+func setDataNetworkAdapterAdvancedPropertiesProperties(d *schema.ResourceData, apPropertiesList []api.NetworkAdapterAdvancedProperty) {
+    advancedProperties := make([]interface{}, 0, len(apPropertiesList))
+    for _, apProperties := range apPropertiesList {
+        advancedProperty := make(map[string]interface{})
+        advancedProperty["registry_keyword"]       = apProperties.RegistryKeyword
+        advancedProperty["registry_value"]         = apProperties.RegistryValue
+        advancedProperty["display_name"]           = apProperties.DisplayName
+        advancedProperty["display_value"]          = apProperties.DisplayValue
+        advancedProperty["default_registry_value"] = apProperties.DefaultRegistryValue
+        advancedProperty["valid_registry_values"]  = apProperties.ValidRegistryValues
+        advancedProperty["valid_display_values"]   = apProperties.ValidDisplayValues
+        advancedProperty["numeric_min_value"]      = apProperties.NumericMinValue
+        advancedProperty["numeric_max_value"]      = apProperties.NumericMaxValue
+        advancedProperty["numeric_step_value"]     = apProperties.NumericStepValue
+        advancedProperties = append(advancedProperties, advancedProperty)
+    }
+    d.Set("advanced_properties", advancedProperties)
+}
+
+//------------------------------------------------------------------------------
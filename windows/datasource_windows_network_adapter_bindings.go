@@ -0,0 +1,136 @@
+//
+// Copyright (c) 2019 Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-windows
+//
+package windows
+
+import (
+    "fmt"
+    "log"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+    "github.com/stefaanc/terraform-provider-windows/api"
+    "github.com/stefaanc/terraform-provider-windows/windows/tfutil"
+)
+
+//------------------------------------------------------------------------------
+
+func dataSourceWindowsNetworkAdapterBindings() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "guid": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Computed: true,
+
+                ValidateFunc: tfutil.ValidateUUID(),
+                StateFunc: tfutil.StateToUpper(),
+            },
+            "name": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Computed: true,
+
+                ConflictsWith: []string{ "guid" },
+            },
+
+            "bindings": &schema.Schema{
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem: dataSourceWindowsNetworkAdapterBindingsBinding(),
+            },
+        },
+
+        Read: dataSourceWindowsNetworkAdapterBindingsRead,
+    }
+}
+
+func dataSourceWindowsNetworkAdapterBindingsBinding() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "component_id": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "display_name": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "enabled": &schema.Schema{
+                Type:     schema.TypeBool,
+                Computed: true,
+            },
+        },
+    }
+}
+
+//------------------------------------------------------------------------------
+
+func dataSourceWindowsNetworkAdapterBindingsRead(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    guid            := d.Get("guid").(string)
+    name            := d.Get("name").(string)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    var id string
+    if guid    != "" { id = guid    } else
+    if name    != "" { id = name    }
+    id = fmt.Sprintf("//%s/network_adapters/%s/bindings", host, id)
+
+    log.Printf("[INFO][terraform-provider-windows] reading windows_network_adapter_bindings %q\n", id)
+
+    // read
+    naQuery := new(api.NetworkAdapter)
+    naQuery.GUID    = guid
+    naQuery.Name    = name
+
+    networkAdapter, err := c.ReadNetworkAdapter(naQuery)
+    if err != nil {
+        log.Printf("[INFO][terraform-provider-windows] cannot read windows_network_adapter_bindings %q\n", id)
+        return err
+    }
+
+    nabQuery := new(api.NetworkAdapterBinding)
+    nabQuery.NetworkAdapterGUID = networkAdapter.GUID
+
+    networkAdapterBindings, err := c.ReadNetworkAdapterBindings(nabQuery)
+    if err != nil {
+        log.Printf("[INFO][terraform-provider-windows] cannot read windows_network_adapter_bindings %q\n", id)
+        return err
+    }
+
+    // set properties
+    d.Set("guid", networkAdapter.GUID)
+    d.Set("name", networkAdapter.Name)
+    setDataNetworkAdapterBindingsProperties(d, networkAdapterBindings)
+
+    // set id
+    d.SetId(id)
+
+    log.Printf("[INFO][terraform-provider-windows] read windows_network_adapter_bindings %q\n", id)
+    return nil
+}
+
+//------------------------------------------------------------------------------
+
+func setDataNetworkAdapterBindingsProperties(d *schema.ResourceData, nabPropertiesList []api.NetworkAdapterBinding) {
+    bindings := make([]interface{}, 0, len(nabPropertiesList))
+    for _, nabProperties := range nabPropertiesList {
+        binding := make(map[string]interface{})
+        binding["component_id"] = nabProperties.ComponentID
+        binding["display_name"] = nabProperties.DisplayName
+        binding["enabled"]      = nabProperties.Enabled
+        bindings = append(bindings, binding)
+    }
+    d.Set("bindings", bindings)
+}
+
+//------------------------------------------------------------------------------
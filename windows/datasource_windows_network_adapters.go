@@ -0,0 +1,185 @@
+//
+// Copyright (c) 2019 Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-windows
+//
+package windows
+
+import (
+    "fmt"
+    "log"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+    "github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+
+    "github.com/stefaanc/terraform-provider-windows/api"
+)
+
+//------------------------------------------------------------------------------
+
+func dataSourceWindowsNetworkAdapters() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            // filters
+            "name": &schema.Schema{
+                Type:     schema.TypeString,   // wildcard pattern
+                Optional: true,
+            },
+            "is_physical": &schema.Schema{
+                Type:     schema.TypeBool,
+                Optional: true,
+            },
+            "operational_status": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+
+                ValidateFunc: validation.StringInSlice([]string{ "Up", "Down", "Testing", "Unknown", "Dormant", "NotPresent", "LowerLayerDown" }, false),
+            },
+
+            "network_adapters": &schema.Schema{
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem: dataSourceWindowsNetworkAdaptersNetworkAdapter(),
+            },
+        },
+
+        Read: dataSourceWindowsNetworkAdaptersRead,
+    }
+}
+
+func dataSourceWindowsNetworkAdaptersNetworkAdapter() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "guid": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "name": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "mac_address": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "dns_client": &schema.Schema{
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem: dataSourceWindowsNetworkAdapterDNSClient(),
+            },
+            "admin_status": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "operational_status": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "connection_status": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "connection_speed": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "is_physical": &schema.Schema{
+                Type:     schema.TypeBool,
+                Computed: true,
+            },
+            "is_team_member": &schema.Schema{
+                Type:     schema.TypeBool,
+                Computed: true,
+            },
+            "is_team_interface": &schema.Schema{
+                Type:     schema.TypeBool,
+                Computed: true,
+            },
+            "team_name": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+        },
+    }
+}
+
+//------------------------------------------------------------------------------
+
+func dataSourceWindowsNetworkAdaptersRead(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    id := fmt.Sprintf("//%s/network_adapters", host)
+
+    log.Printf("[INFO][terraform-provider-windows] reading windows_network_adapters %q\n", id)
+
+    // read
+    naFilter := new(api.NetworkAdapterFilter)
+    naFilter.Name              = d.Get("name").(string)
+    naFilter.OperationalStatus = d.Get("operational_status").(string)
+    if v, ok := d.GetOkExists("is_physical"); ok {
+        isPhysical := v.(bool)
+        naFilter.IsPhysical = &isPhysical
+    }
+
+    networkAdapters, err := c.ReadNetworkAdapters(naFilter)
+    if err != nil {
+        log.Printf("[INFO][terraform-provider-windows] cannot read windows_network_adapters %q\n", id)
+        return err
+    }
+
+    // set properties
+    setDataNetworkAdaptersProperties(d, networkAdapters)
+
+    // set id
+    d.SetId(id)
+
+    log.Printf("[INFO][terraform-provider-windows] read windows_network_adapters %q\n", id)
+    return nil
+}
+
+//------------------------------------------------------------------------------
+
+func setDataNetworkAdaptersProperties(d *schema.ResourceData, naPropertiesList []api.NetworkAdapter) {
+    networkAdapters := make([]interface{}, 0, len(naPropertiesList))
+    for _, naProperties := range naPropertiesList {
+        networkAdapter := make(map[string]interface{})
+        networkAdapter["guid"]        = naProperties.GUID
+        networkAdapter["name"]        = naProperties.Name
+        networkAdapter["mac_address"] = naProperties.MACAddress
+
+        dnsClients := make([]interface{}, 0, 1)
+        if len(naProperties.DNSClient) > 0 {
+            dnsClient := make(map[string]interface{})
+            dnsClient["register_connection_address"] = naProperties.DNSClient[0].RegisterConnectionAddress
+            if naProperties.DNSClient[0].RegisterConnectionSuffix == "" {
+                // replace "" with "<empty>", consistent with the windows_network_adapter data source
+                dnsClient["register_connection_suffix"] = "<empty>"
+            } else {
+                dnsClient["register_connection_suffix"] = naProperties.DNSClient[0].RegisterConnectionSuffix
+            }
+            dnsClient["server_addresses"] = naProperties.DNSClient[0].ServerAddresses
+            dnsClient["reset_to_dhcp"]    = naProperties.DNSClient[0].ResetToDHCP
+            dnsClients = append(dnsClients, dnsClient)
+        }
+        networkAdapter["dns_client"] = dnsClients
+
+        networkAdapter["admin_status"]       = naProperties.AdminStatus
+        networkAdapter["operational_status"] = naProperties.OperationalStatus
+        networkAdapter["connection_status"]  = naProperties.ConnectionStatus
+        networkAdapter["connection_speed"]   = naProperties.ConnectionSpeed
+        networkAdapter["is_physical"]        = naProperties.IsPhysical
+        networkAdapter["is_team_member"]     = naProperties.IsTeamMember
+        networkAdapter["is_team_interface"]  = naProperties.IsTeamInterface
+        networkAdapter["team_name"]          = naProperties.TeamName
+        networkAdapters = append(networkAdapters, networkAdapter)
+    }
+    d.Set("network_adapters", networkAdapters)
+}
+
+//------------------------------------------------------------------------------
@@ -125,7 +125,12 @@ func dataSourceWindowsNetworkConnectionRead(d *schema.ResourceData, m interface{
     ncQuery.Name               = d.Get("name").(string)
     ncQuery.AllowDisconnect    = d.Get("allow_disconnect").(bool)
 
-    networkConnection, err := c.ReadNetworkConnection(ncQuery)
+    // lifecycle customizations: wait_until_exists
+    var networkConnection *api.NetworkConnection
+    err := tfutil.WaitUntilExists(x_lifecycle, "cannot find network_connection", func() (err error) {
+        networkConnection, err = c.ReadNetworkConnection(ncQuery)
+        return err
+    })
     if err != nil {
         // lifecycle customizations: ignore_error_if_not_exists
         v, ok := x_lifecycle["ignore_error_if_not_exists"]
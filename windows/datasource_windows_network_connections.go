@@ -0,0 +1,149 @@
+//
+// Copyright (c) 2019 Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-windows
+//
+package windows
+
+import (
+    "fmt"
+    "log"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+    "github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+
+    "github.com/stefaanc/terraform-provider-windows/api"
+)
+
+//------------------------------------------------------------------------------
+
+func dataSourceWindowsNetworkConnections() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            // filters
+            "name": &schema.Schema{
+                Type:     schema.TypeString,   // wildcard pattern
+                Optional: true,
+            },
+            "connection_profile": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+
+                ValidateFunc: validation.StringInSlice([]string{ "Public", "Private", "DomainAuthenticated" }, true),
+            },
+            "has_gateway": &schema.Schema{
+                Type:     schema.TypeBool,
+                Optional: true,
+            },
+
+            "network_connections": &schema.Schema{
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem: dataSourceWindowsNetworkConnectionsNetworkConnection(),
+            },
+        },
+
+        Read: dataSourceWindowsNetworkConnectionsRead,
+    }
+}
+
+func dataSourceWindowsNetworkConnectionsNetworkConnection() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "guid": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "ipv4_gateway_address": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "ipv6_gateway_address": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "name": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "connection_profile": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "ipv4_connectivity": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "ipv6_connectivity": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "network_adapter_names": &schema.Schema{
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem:     &schema.Schema{ Type: schema.TypeString },
+            },
+        },
+    }
+}
+
+//------------------------------------------------------------------------------
+
+func dataSourceWindowsNetworkConnectionsRead(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    id := fmt.Sprintf("//%s/network_connections", host)
+
+    log.Printf("[INFO][terraform-provider-windows] reading windows_network_connections %q\n", id)
+
+    // read
+    ncFilter := new(api.NetworkConnectionFilter)
+    ncFilter.Name              = d.Get("name").(string)
+    ncFilter.ConnectionProfile = d.Get("connection_profile").(string)
+    if v, ok := d.GetOkExists("has_gateway"); ok {
+        hasGateway := v.(bool)
+        ncFilter.HasGateway = &hasGateway
+    }
+
+    networkConnections, err := c.ReadNetworkConnections(ncFilter)
+    if err != nil {
+        log.Printf("[INFO][terraform-provider-windows] cannot read windows_network_connections %q\n", id)
+        return err
+    }
+
+    // set properties
+    setDataNetworkConnectionsProperties(d, networkConnections)
+
+    // set id
+    d.SetId(id)
+
+    log.Printf("[INFO][terraform-provider-windows] read windows_network_connections %q\n", id)
+    return nil
+}
+
+//------------------------------------------------------------------------------
+
+func setDataNetworkConnectionsProperties(d *schema.ResourceData, ncPropertiesList []api.NetworkConnection) {
+    networkConnections := make([]interface{}, 0, len(ncPropertiesList))
+    for _, ncProperties := range ncPropertiesList {
+        networkConnection := make(map[string]interface{})
+        networkConnection["guid"]                  = ncProperties.GUID
+        networkConnection["ipv4_gateway_address"]  = ncProperties.IPv4GatewayAddress
+        networkConnection["ipv6_gateway_address"]  = ncProperties.IPv6GatewayAddress
+        networkConnection["name"]                  = ncProperties.Name
+        networkConnection["connection_profile"]    = ncProperties.ConnectionProfile
+        networkConnection["ipv4_connectivity"]     = ncProperties.IPv4Connectivity
+        networkConnection["ipv6_connectivity"]     = ncProperties.IPv6Connectivity
+        networkConnection["network_adapter_names"] = ncProperties.NetworkAdapterNames
+        networkConnections = append(networkConnections, networkConnection)
+    }
+    d.Set("network_connections", networkConnections)
+}
+
+//------------------------------------------------------------------------------
@@ -62,7 +62,8 @@ func dataSourceWindowsNetworkInterface() *schema.Resource {
 
                 ConflictsWith: []string{ "guid", "index", "alias", "description" },
                 ValidateFunc: tfutil.ValidateSingleMAC(),
-                StateFunc: tfutil.StateToUpper(),
+                StateFunc: tfutil.StateToMAC(),
+                DiffSuppressFunc: tfutil.DiffSuppressMAC(),
             },
             "network_adapter_name": &schema.Schema{
                 Type:     schema.TypeString,
@@ -78,6 +79,20 @@ func dataSourceWindowsNetworkInterface() *schema.Resource {
 
                 ConflictsWith: []string{ "guid", "index", "alias", "description", "mac_address","network_adapter_name" },
             },
+            "ip_address": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+
+                ConflictsWith: []string{ "guid", "index", "alias", "description", "mac_address","network_adapter_name", "vnetwork_adapter_name" },
+                ValidateFunc: validation.SingleIP(),
+            },
+            "subnet": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+
+                ConflictsWith: []string{ "guid", "index", "alias", "description", "mac_address","network_adapter_name", "vnetwork_adapter_name", "ip_address" },
+                ValidateFunc: validation.CIDRNetwork(0, 128),
+            },
 
             "network_connection_names": &schema.Schema{
                 Type:     schema.TypeList,
@@ -93,6 +108,28 @@ func dataSourceWindowsNetworkInterface() *schema.Resource {
                 Computed: true,
             },
 
+            "ipv4_interface": &schema.Schema{
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem: dataSourceWindowsNetworkInterfaceIPInterface(),
+            },
+            "ipv6_interface": &schema.Schema{
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem: dataSourceWindowsNetworkInterfaceIPInterface(),
+            },
+
+            "ipv4_addresses": &schema.Schema{
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem: dataSourceWindowsNetworkInterfaceIPAddress(),
+            },
+            "ipv6_addresses": &schema.Schema{
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem: dataSourceWindowsNetworkInterfaceIPAddress(),
+            },
+
             // lifecycle customizations that are not supported by the 'lifecycle' meta-argument for data sources
             "x_lifecycle": &tfutil.DataSourceXLifecycleSchema,
         },
@@ -101,6 +138,64 @@ func dataSourceWindowsNetworkInterface() *schema.Resource {
     }
 }
 
+func dataSourceWindowsNetworkInterfaceIPInterface() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "dhcp": &schema.Schema{
+                Type:     schema.TypeBool,
+                Computed: true,
+            },
+            "automatic_metric": &schema.Schema{
+                Type:     schema.TypeBool,
+                Computed: true,
+            },
+            "interface_metric": &schema.Schema{
+                Type:     schema.TypeInt,   // uint32
+                Computed: true,
+            },
+            "nl_mtu": &schema.Schema{
+                Type:     schema.TypeInt,   // uint32
+                Computed: true,
+            },
+            "forwarding": &schema.Schema{
+                Type:     schema.TypeBool,
+                Computed: true,
+            },
+            "router_discovery": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "weak_host_send": &schema.Schema{
+                Type:     schema.TypeBool,
+                Computed: true,
+            },
+            "weak_host_receive": &schema.Schema{
+                Type:     schema.TypeBool,
+                Computed: true,
+            },
+            "connection_state": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+        },
+    }
+}
+
+func dataSourceWindowsNetworkInterfaceIPAddress() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "ip_address": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "prefix_length": &schema.Schema{
+                Type:     schema.TypeInt,   // uint8
+                Computed: true,
+            },
+        },
+    }
+}
+
 //------------------------------------------------------------------------------
 
 func dataSourceWindowsNetworkInterfaceRead(d *schema.ResourceData, m interface{}) error {
@@ -110,9 +205,11 @@ func dataSourceWindowsNetworkInterfaceRead(d *schema.ResourceData, m interface{}
     index               := uint32(d.Get("index").(int))
     alias               := d.Get("alias").(string)
     description         := d.Get("description").(string)
-    macAddress          := d.Get("mac_address").(string)
+    macAddress          := tfutil.NormalizeMAC(d.Get("mac_address").(string))
     networkAdapterName  := d.Get("network_adapter_name").(string)
     vnetworkAdapterName := d.Get("vnetwork_adapter_name").(string)
+    ipAddress           := d.Get("ip_address").(string)
+    subnet              := d.Get("subnet").(string)
 
     host := "localhost"
     if c.Type != "local" {
@@ -126,7 +223,9 @@ func dataSourceWindowsNetworkInterfaceRead(d *schema.ResourceData, m interface{}
     if description         != "" { id = description                           } else
     if macAddress          != "" { id = macAddress                            } else
     if networkAdapterName  != "" { id = networkAdapterName                    } else
-    if vnetworkAdapterName != "" { id = vnetworkAdapterName                   }
+    if vnetworkAdapterName != "" { id = vnetworkAdapterName                   } else
+    if ipAddress           != "" { id = ipAddress                             } else
+    if subnet              != "" { id = subnet                                }
     id = fmt.Sprintf("//%s/network_interfaces/%s", host, id)
 
     log.Printf("[INFO][terraform-provider-windows] reading windows_network_interface %q\n", id)
@@ -142,8 +241,15 @@ func dataSourceWindowsNetworkInterfaceRead(d *schema.ResourceData, m interface{}
     niQuery.MACAddress          = macAddress
     niQuery.NetworkAdapterName  = networkAdapterName
     niQuery.VNetworkAdapterName = vnetworkAdapterName
+    niQuery.IPAddress           = ipAddress
+    niQuery.Subnet              = subnet
 
-    networkInterface, err := c.ReadNetworkInterface(niQuery)
+    // lifecycle customizations: wait_until_exists
+    var networkInterface *api.NetworkInterface
+    err := tfutil.WaitUntilExists(x_lifecycle, "cannot find network_interface", func() (err error) {
+        networkInterface, err = c.ReadNetworkInterface(niQuery)
+        return err
+    })
     if err != nil {
         // lifecycle customizations: ignore_error_if_not_exists
         v, ok := x_lifecycle["ignore_error_if_not_exists"]
@@ -161,6 +267,10 @@ func dataSourceWindowsNetworkInterfaceRead(d *schema.ResourceData, m interface{}
             d.Set("network_connection_names", nil)
             d.Set("vswitch_name", "")
             d.Set("computer_name", "")
+            d.Set("ipv4_interface", nil)
+            d.Set("ipv6_interface", nil)
+            d.Set("ipv4_addresses", nil)
+            d.Set("ipv6_addresses", nil)
 
             // set computed lifecycle properties
             x_lifecycle["exists"] = false
@@ -205,6 +315,39 @@ func setDataNetworkInterfaceProperties(d *schema.ResourceData, niProperties *api
     d.Set("network_connection_names", niProperties.NetworkConnectionNames)
     d.Set("vswitch_name", niProperties.VSwitchName)
     d.Set("computer_name", niProperties.ComputerName)
+    d.Set("ipv4_interface", flattenDataNetworkInterfaceIPInterface(niProperties.IPv4Interface))
+    d.Set("ipv6_interface", flattenDataNetworkInterfaceIPInterface(niProperties.IPv6Interface))
+    d.Set("ipv4_addresses", flattenDataNetworkInterfaceIPAddresses(niProperties.IPv4Addresses))
+    d.Set("ipv6_addresses", flattenDataNetworkInterfaceIPAddresses(niProperties.IPv6Addresses))
+}
+
+func flattenDataNetworkInterfaceIPInterface(niiPropertiesList []api.NetworkIPInterface) []interface{} {
+    ipInterfaces := make([]interface{}, 0, len(niiPropertiesList))
+    for _, niiProperties := range niiPropertiesList {
+        ipInterface := make(map[string]interface{})
+        ipInterface["dhcp"]              = niiProperties.DHCP
+        ipInterface["automatic_metric"]  = niiProperties.AutomaticMetric
+        ipInterface["interface_metric"]  = niiProperties.InterfaceMetric
+        ipInterface["nl_mtu"]            = niiProperties.NlMTU
+        ipInterface["forwarding"]        = niiProperties.Forwarding
+        ipInterface["router_discovery"]  = niiProperties.RouterDiscovery
+        ipInterface["weak_host_send"]    = niiProperties.WeakHostSend
+        ipInterface["weak_host_receive"] = niiProperties.WeakHostReceive
+        ipInterface["connection_state"]  = niiProperties.ConnectionState
+        ipInterfaces = append(ipInterfaces, ipInterface)
+    }
+    return ipInterfaces
+}
+
+func flattenDataNetworkInterfaceIPAddresses(niaPropertiesList []api.NetworkInterfaceIPAddress) []interface{} {
+    ipAddresses := make([]interface{}, 0, len(niaPropertiesList))
+    for _, niaProperties := range niaPropertiesList {
+        ipAddress := make(map[string]interface{})
+        ipAddress["ip_address"]    = niaProperties.IPAddress
+        ipAddress["prefix_length"] = niaProperties.PrefixLength
+        ipAddresses = append(ipAddresses, ipAddress)
+    }
+    return ipAddresses
 }
 
 //------------------------------------------------------------------------------
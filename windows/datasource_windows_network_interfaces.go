@@ -0,0 +1,180 @@
+//
+// Copyright (c) 2019 Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-windows
+//
+package windows
+
+import (
+    "fmt"
+    "log"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+    "github.com/stefaanc/terraform-provider-windows/api"
+)
+
+//------------------------------------------------------------------------------
+
+func dataSourceWindowsNetworkInterfaces() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            // filters
+            "alias": &schema.Schema{
+                Type:     schema.TypeString,   // wildcard pattern
+                Optional: true,
+            },
+            "is_physical": &schema.Schema{
+                Type:     schema.TypeBool,
+                Optional: true,
+            },
+            "vswitch_name": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+            },
+
+            "network_interfaces": &schema.Schema{
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem: dataSourceWindowsNetworkInterfacesNetworkInterface(),
+            },
+        },
+
+        Read: dataSourceWindowsNetworkInterfacesRead,
+    }
+}
+
+func dataSourceWindowsNetworkInterfacesNetworkInterface() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "guid": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "index": &schema.Schema{
+                Type:     schema.TypeInt,   // uint32
+                Computed: true,
+            },
+            "alias": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "description": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "mac_address": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "network_adapter_name": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "vnetwork_adapter_name": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "network_connection_names": &schema.Schema{
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem:     &schema.Schema{ Type: schema.TypeString },
+            },
+            "vswitch_name": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "computer_name": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "ipv4_interface": &schema.Schema{
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem: dataSourceWindowsNetworkInterfaceIPInterface(),
+            },
+            "ipv6_interface": &schema.Schema{
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem: dataSourceWindowsNetworkInterfaceIPInterface(),
+            },
+            "ipv4_addresses": &schema.Schema{
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem: dataSourceWindowsNetworkInterfaceIPAddress(),
+            },
+            "ipv6_addresses": &schema.Schema{
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem: dataSourceWindowsNetworkInterfaceIPAddress(),
+            },
+        },
+    }
+}
+
+//------------------------------------------------------------------------------
+
+func dataSourceWindowsNetworkInterfacesRead(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    id := fmt.Sprintf("//%s/network_interfaces", host)
+
+    log.Printf("[INFO][terraform-provider-windows] reading windows_network_interfaces %q\n", id)
+
+    // read
+    niFilter := new(api.NetworkInterfaceFilter)
+    niFilter.Alias       = d.Get("alias").(string)
+    niFilter.VSwitchName = d.Get("vswitch_name").(string)
+    if v, ok := d.GetOkExists("is_physical"); ok {
+        isPhysical := v.(bool)
+        niFilter.IsPhysical = &isPhysical
+    }
+
+    networkInterfaces, err := c.ReadNetworkInterfaces(niFilter)
+    if err != nil {
+        log.Printf("[INFO][terraform-provider-windows] cannot read windows_network_interfaces %q\n", id)
+        return err
+    }
+
+    // set properties
+    setDataNetworkInterfacesProperties(d, networkInterfaces)
+
+    // set id
+    d.SetId(id)
+
+    log.Printf("[INFO][terraform-provider-windows] read windows_network_interfaces %q\n", id)
+    return nil
+}
+
+//------------------------------------------------------------------------------
+
+func setDataNetworkInterfacesProperties(d *schema.ResourceData, niPropertiesList []api.NetworkInterface) {
+    networkInterfaces := make([]interface{}, 0, len(niPropertiesList))
+    for _, niProperties := range niPropertiesList {
+        networkInterface := make(map[string]interface{})
+        networkInterface["guid"]                     = niProperties.GUID
+        networkInterface["index"]                    = niProperties.Index
+        networkInterface["alias"]                    = niProperties.Alias
+        networkInterface["description"]              = niProperties.Description
+        networkInterface["mac_address"]              = niProperties.MACAddress
+        networkInterface["network_adapter_name"]     = niProperties.NetworkAdapterName
+        networkInterface["vnetwork_adapter_name"]    = niProperties.VNetworkAdapterName
+        networkInterface["network_connection_names"] = niProperties.NetworkConnectionNames
+        networkInterface["vswitch_name"]             = niProperties.VSwitchName
+        networkInterface["computer_name"]            = niProperties.ComputerName
+        networkInterface["ipv4_interface"]           = flattenDataNetworkInterfaceIPInterface(niProperties.IPv4Interface)
+        networkInterface["ipv6_interface"]           = flattenDataNetworkInterfaceIPInterface(niProperties.IPv6Interface)
+        networkInterface["ipv4_addresses"]           = flattenDataNetworkInterfaceIPAddresses(niProperties.IPv4Addresses)
+        networkInterface["ipv6_addresses"]           = flattenDataNetworkInterfaceIPAddresses(niProperties.IPv6Addresses)
+        networkInterfaces = append(networkInterfaces, networkInterface)
+    }
+    d.Set("network_interfaces", networkInterfaces)
+}
+
+//------------------------------------------------------------------------------
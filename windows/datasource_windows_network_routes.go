@@ -0,0 +1,164 @@
+//
+// Copyright (c) 2019 Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-windows
+//
+package windows
+
+import (
+    "fmt"
+    "log"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+    "github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+
+    "github.com/stefaanc/terraform-provider-windows/api"
+)
+
+//------------------------------------------------------------------------------
+
+func dataSourceWindowsNetworkRoutes() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            // filters
+            "destination_prefix": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+
+                ValidateFunc: validation.CIDRNetwork(0, 128),
+            },
+            "interface_index": &schema.Schema{
+                Type:     schema.TypeInt,   // uint32
+                Optional: true,
+            },
+            "interface_alias": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+
+                ConflictsWith: []string{ "interface_index" },
+            },
+            "address_family": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+
+                ValidateFunc: validation.StringInSlice([]string{ "IPv4", "IPv6" }, false),
+            },
+
+            "routes": &schema.Schema{
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem: dataSourceWindowsNetworkRoutesRoute(),
+            },
+        },
+
+        Read: dataSourceWindowsNetworkRoutesRead,
+    }
+}
+
+func dataSourceWindowsNetworkRoutesRoute() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "destination_prefix": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "next_hop": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "network_adapter_guid": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "network_adapter_name": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "interface_index": &schema.Schema{
+                Type:     schema.TypeInt,   // uint32
+                Computed: true,
+            },
+            "interface_alias": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "address_family": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "route_metric": &schema.Schema{
+                Type:     schema.TypeInt,   // uint16
+                Computed: true,
+            },
+            "policy_store": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "protocol": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+        },
+    }
+}
+
+//------------------------------------------------------------------------------
+
+func dataSourceWindowsNetworkRoutesRead(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    id := fmt.Sprintf("//%s/network_routes", host)
+
+    log.Printf("[INFO][terraform-provider-windows] reading windows_network_routes %q\n", id)
+
+    // read
+    nrQuery := new(api.NetworkRoute)
+    nrQuery.DestinationPrefix = d.Get("destination_prefix").(string)
+    nrQuery.InterfaceIndex    = uint32(d.Get("interface_index").(int))
+    nrQuery.InterfaceAlias    = d.Get("interface_alias").(string)
+    nrQuery.AddressFamily     = d.Get("address_family").(string)
+
+    networkRoutes, err := c.ReadNetworkRoutes(nrQuery)
+    if err != nil {
+        log.Printf("[INFO][terraform-provider-windows] cannot read windows_network_routes %q\n", id)
+        return err
+    }
+
+    // set properties
+    setDataNetworkRoutesProperties(d, networkRoutes)
+
+    // set id
+    d.SetId(id)
+
+    log.Printf("[INFO][terraform-provider-windows] read windows_network_routes %q\n", id)
+    return nil
+}
+
+//------------------------------------------------------------------------------
+
+func setDataNetworkRoutesProperties(d *schema.ResourceData, nrPropertiesList []api.NetworkRoute) {
+    routes := make([]interface{}, 0, len(nrPropertiesList))
+    for _, nrProperties := range nrPropertiesList {
+        route := make(map[string]interface{})
+        route["destination_prefix"]   = nrProperties.DestinationPrefix
+        route["next_hop"]             = nrProperties.NextHop
+        route["network_adapter_guid"] = nrProperties.NetworkAdapterGUID
+        route["network_adapter_name"] = nrProperties.NetworkAdapterName
+        route["interface_index"]      = nrProperties.InterfaceIndex
+        route["interface_alias"]      = nrProperties.InterfaceAlias
+        route["address_family"]       = nrProperties.AddressFamily
+        route["route_metric"]         = nrProperties.RouteMetric
+        route["policy_store"]         = nrProperties.PolicyStore
+        route["protocol"]             = nrProperties.Protocol
+        routes = append(routes, route)
+    }
+    d.Set("routes", routes)
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,149 @@
+//
+// Copyright (c) 2019 Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-windows
+//
+package windows
+
+import (
+    "fmt"
+    "log"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+    "github.com/stefaanc/terraform-provider-windows/api"
+    "github.com/stefaanc/terraform-provider-windows/windows/tfutil"
+)
+
+//------------------------------------------------------------------------------
+
+func dataSourceWindowsVSwitch() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "name": &schema.Schema{
+                Type:     schema.TypeString,
+                Required: true,
+            },
+
+            "switch_type": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+
+            "network_adapter_names": &schema.Schema{
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem:     &schema.Schema{ Type: schema.TypeString },
+            },
+            "allow_management_os": &schema.Schema{
+                Type:     schema.TypeBool,
+                Computed: true,
+            },
+            "enable_embedded_teaming": &schema.Schema{
+                Type:     schema.TypeBool,
+                Computed: true,
+            },
+
+            "bandwidth_reservation_mode": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "default_flow_minimum_bandwidth_weight": &schema.Schema{
+                Type:     schema.TypeInt,
+                Computed: true,
+            },
+            "default_flow_minimum_bandwidth_absolute": &schema.Schema{
+                Type:     schema.TypeInt,
+                Computed: true,
+            },
+
+            "guid": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+
+            // lifecycle customizations that are not supported by the 'lifecycle' meta-argument for persistent resources (similar to data-sources)
+            "x_lifecycle": &tfutil.DataSourceXLifecycleSchema,
+        },
+
+        Read:   dataSourceWindowsVSwitchRead,
+    }
+}
+
+//------------------------------------------------------------------------------
+
+func dataSourceWindowsVSwitchRead(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    name := d.Get("name").(string)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    id := fmt.Sprintf("//%s/vswitches/%s", host, name)
+
+    log.Printf("[INFO][terraform-provider-windows] reading windows_vswitch %q\n", id)
+
+    x_lifecycle := tfutil.GetResource(d, "x_lifecycle")
+
+    // read
+    vsQuery := new(api.VSwitch)
+    vsQuery.Name = name
+
+    // lifecycle customizations: wait_until_exists
+    var vswitch *api.VSwitch
+    err := tfutil.WaitUntilExists(x_lifecycle, "cannot find vswitch", func() (err error) {
+        vswitch, err = c.ReadVSwitch(vsQuery)
+        return err
+    })
+    if err != nil {
+        // lifecycle customizations: ignore_error_if_not_exists
+        v, ok := x_lifecycle["ignore_error_if_not_exists"]
+        if ok && v.(bool) && strings.Contains(err.Error(), "cannot find vswitch") {
+            log.Printf("[INFO][terraform-provider-windows] cannot read windows_vswitch %q\n", id)
+
+            // set zeroed properties
+            d.Set("switch_type", "")
+            d.Set("network_adapter_names", nil)
+            d.Set("allow_management_os", false)
+            d.Set("enable_embedded_teaming", false)
+            d.Set("bandwidth_reservation_mode", "")
+            d.Set("default_flow_minimum_bandwidth_weight", 0)
+            d.Set("default_flow_minimum_bandwidth_absolute", 0)
+            d.Set("guid", "")
+
+            // set computed lifecycle properties
+            x_lifecycle["exists"] = false
+            d.Set("x_lifecycle", []interface{}{ x_lifecycle })
+
+            // set id
+            d.SetId(id)
+
+            log.Printf("[INFO][terraform-provider-windows] ignored error and added zeroed windows_vswitch %q to terraform state\n", id)
+            return nil
+        }
+
+        // no lifecycle customizations
+        log.Printf("[ERROR][terraform-provider-windows] cannot read windows_vswitch %q\n", id)
+        return err
+    }
+
+    // set computed lifecycle properties
+    x_lifecycle["exists"] = true
+    d.Set("x_lifecycle", []interface{}{ x_lifecycle })
+
+    // set properties
+    setVSwitchProperties(d, vswitch)
+
+    // set id
+    d.SetId(id)
+
+    log.Printf("[INFO][terraform-provider-windows] read windows_vswitch %q\n", id)
+    return nil
+}
+
+//------------------------------------------------------------------------------
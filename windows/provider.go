@@ -67,14 +67,31 @@ func Provider() terraform.ResourceProvider {
         DataSourcesMap: map[string]*schema.Resource {
             "windows_computer": dataSourceWindowsComputer(),
             "windows_network_adapter": dataSourceWindowsNetworkAdapter(),
+            "windows_network_adapter_advanced_properties": dataSourceWindowsNetworkAdapterAdvancedProperties(),
+            "windows_network_adapter_bindings": dataSourceWindowsNetworkAdapterBindings(),
+            "windows_network_adapters": dataSourceWindowsNetworkAdapters(),
             "windows_network_connection": dataSourceWindowsNetworkConnection(),
+            "windows_network_connections": dataSourceWindowsNetworkConnections(),
             "windows_network_interface": dataSourceWindowsNetworkInterface(),
+            "windows_network_interfaces": dataSourceWindowsNetworkInterfaces(),
+            "windows_network_routes": dataSourceWindowsNetworkRoutes(),
+            "windows_vswitch": dataSourceWindowsVSwitch(),
         },
 
         ResourcesMap: map[string]*schema.Resource{
             "windows_computer": resourceWindowsComputer(),
+            "windows_firewall_profile": resourceWindowsFirewallProfile(),
+            "windows_firewall_rule": resourceWindowsFirewallRule(),
             "windows_network_adapter": resourceWindowsNetworkAdapter(),
+            "windows_network_adapter_binding": resourceWindowsNetworkAdapterBinding(),
             "windows_network_connection": resourceWindowsNetworkConnection(),
+            "windows_network_ip_address": resourceWindowsNetworkIPAddress(),
+            "windows_network_ip_interface": resourceWindowsNetworkIPInterface(),
+            "windows_network_nat": resourceWindowsNetworkNAT(),
+            "windows_network_route": resourceWindowsNetworkRoute(),
+            "windows_network_team": resourceWindowsNetworkTeam(),
+            "windows_vnetwork_adapter": resourceWindowsVNetworkAdapter(),
+            "windows_vswitch": resourceWindowsVSwitch(),
         },
 
         ConfigureFunc: providerConfigure,
@@ -10,7 +10,10 @@ import (
     "fmt"
     "log"
     "reflect"
+    "regexp"
+    "sort"
     "strings"
+    "time"
 
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
     "github.com/hashicorp/terraform-plugin-sdk/helper/validation"
@@ -44,6 +47,14 @@ func resourceWindowsComputer() *schema.Resource {
                 Elem: resourceWindowsComputerDNSClient(),
             },
 
+            "domain_membership": &schema.Schema{
+                Type:     schema.TypeList,
+                MaxItems: 1,
+                Optional: true,
+                Computed: true,
+                Elem: resourceWindowsComputerDomainMembership(),
+            },
+
             "reboot_pending": &schema.Schema{
                 Type:     schema.TypeBool,
                 Computed: true,
@@ -55,6 +66,21 @@ func resourceWindowsComputer() *schema.Resource {
                 Elem: resourceWindowsComputerRebootPendingDetails(),
             },
 
+            "reboot": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Default:  "never",
+
+                ValidateFunc: validation.StringInSlice([]string{ "never", "if_pending", "always_after_change" }, false),
+            },
+            "reboot_timeout": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Default:  "15m",
+
+                ValidateFunc: tfutil.ValidateDuration(),
+            },
+
             "network_adapter_names": &schema.Schema{
                 Type:     schema.TypeList,
                 Computed: true,
@@ -66,6 +92,19 @@ func resourceWindowsComputer() *schema.Resource {
                 Elem:     &schema.Schema{ Type: schema.TypeString },
             },
 
+            // lifecycle customizations that are not supported by the 'lifecycle' meta-argument
+            // the computer always exists, so only 'wait_until_exists' is supported, to wait until the computer can be connected to
+            "x_lifecycle": &schema.Schema{
+                Type:     schema.TypeList,
+                MaxItems: 1,
+                Optional: true,
+                Elem: &schema.Resource{
+                    Schema: map[string]*schema.Schema{
+                        "wait_until_exists": &tfutil.WaitUntilExistsSchema,
+                    },
+                },
+            },
+
             // used to reset values on terraform destroy
             "original": &schema.Schema{
                 Type:     schema.TypeList,
@@ -105,6 +144,120 @@ func resourceWindowsComputerDNSClient() *schema.Resource {
 
                 ValidateFunc: validation.IntBetween(0, 4294967295),
             },
+
+            "primary_suffix": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Computed: true,
+
+                StateFunc: tfutil.StateAll(
+                    tfutil.StateToLower(),
+                    tfutil.StateAcceptEmptyString(),   // workaround for ?terraform bug?, replaces "" with "<empty>"
+                ),
+            },
+            "change_primary_suffix_with_membership": &schema.Schema{
+                Type:     schema.TypeBool,
+                Optional: true,
+                Computed: true,
+            },
+
+            "doh_server": &schema.Schema{
+                Type:     schema.TypeSet,
+                Optional: true,
+                Computed: true,
+                Elem: resourceWindowsComputerDNSClientDoHServer(),
+            },
+        },
+    }
+}
+
+func resourceWindowsComputerDNSClientDoHServer() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "server_address": &schema.Schema{
+                Type:     schema.TypeString,
+                Required: true,
+
+                ValidateFunc: validation.SingleIP(),
+                StateFunc: tfutil.StateToLower(),
+            },
+            "doh_template": &schema.Schema{
+                Type:     schema.TypeString,
+                Required: true,
+
+                ValidateFunc: validation.StringMatch(regexp.MustCompile(`^https://`), "expected an https-url"),
+            },
+            "allow_fallback_to_udp": &schema.Schema{
+                Type:     schema.TypeBool,
+                Optional: true,
+                Default:  false,
+            },
+            "auto_upgrade": &schema.Schema{
+                Type:     schema.TypeBool,
+                Optional: true,
+                Default:  false,
+            },
+        },
+    }
+}
+
+func resourceWindowsComputerDomainMembership() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "domain": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Computed: true,
+
+                ConflictsWith: []string{ "domain_membership.0.workgroup" },
+                StateFunc: tfutil.StateToLower(),
+            },
+            "workgroup": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Computed: true,
+
+                ConflictsWith: []string{ "domain_membership.0.domain" },
+                StateFunc: tfutil.StateToUpper(),
+            },
+            "ou_path": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+
+                ConflictsWith: []string{ "domain_membership.0.workgroup" },
+            },
+            "user": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+            },
+            "password": &schema.Schema{
+                Type:      schema.TypeString,
+                Optional:  true,
+                Sensitive: true,
+            },
+            "unjoin_user": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+            },
+            "unjoin_password": &schema.Schema{
+                Type:      schema.TypeString,
+                Optional:  true,
+                Sensitive: true,
+            },
+            "options": &schema.Schema{
+                Type:     schema.TypeList,
+                Optional: true,
+                Elem:     &schema.Schema{
+                    Type: schema.TypeString,
+
+                    ValidateFunc: validation.StringInSlice([]string{ "AccountCreate", "Win9XUpgrade", "UnsecuredJoin", "PasswordPass", "JoinWithNewName", "JoinReadOnly", "InstallInvoke" }, false),
+                },
+            },
+
+            "part_of_domain": &schema.Schema{
+                Type:     schema.TypeBool,
+                Computed: true,
+            },
         },
     }
 }
@@ -192,6 +345,56 @@ func resourceWindowsComputerOriginal() *schema.Resource {
                             Type:     schema.TypeInt,   // uint32
                             Computed: true,
                         },
+                        "primary_suffix": &schema.Schema{
+                            Type:     schema.TypeString,
+                            Computed: true,
+                        },
+                        "change_primary_suffix_with_membership": &schema.Schema{
+                            Type:     schema.TypeBool,
+                            Computed: true,
+                        },
+                        "doh_server": &schema.Schema{
+                            Type:     schema.TypeList,
+                            Computed: true,
+                            Elem: &schema.Resource{
+                                Schema: map[string]*schema.Schema{
+                                    "server_address": &schema.Schema{
+                                        Type:     schema.TypeString,
+                                        Computed: true,
+                                    },
+                                    "doh_template": &schema.Schema{
+                                        Type:     schema.TypeString,
+                                        Computed: true,
+                                    },
+                                    "allow_fallback_to_udp": &schema.Schema{
+                                        Type:     schema.TypeBool,
+                                        Computed: true,
+                                    },
+                                    "auto_upgrade": &schema.Schema{
+                                        Type:     schema.TypeBool,
+                                        Computed: true,
+                                    },
+                                },
+                            },
+                        },
+                    },
+                },
+            },
+
+            "domain_membership": &schema.Schema{
+                Type:     schema.TypeList,
+                MaxItems: 1,
+                Computed: true,
+                Elem: &schema.Resource{
+                    Schema: map[string]*schema.Schema{
+                        "domain": &schema.Schema{
+                            Type:     schema.TypeString,
+                            Computed: true,
+                        },
+                        "workgroup": &schema.Schema{
+                            Type:     schema.TypeString,
+                            Computed: true,
+                        },
                     },
                 },
             },
@@ -202,6 +405,32 @@ func resourceWindowsComputerOriginal() *schema.Resource {
 //------------------------------------------------------------------------------
 
 func resourceWindowsComputerCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+    domainMembershipChanged := d.HasChange("domain_membership.0.domain") || d.HasChange("domain_membership.0.workgroup")
+
+    // when rebooting, the name and the reboot_pending attributes are only known after the reboot
+    if ( d.Get("reboot").(string) != "never" ) && ( d.HasChange("new_name") || d.HasChange("dns_client") || domainMembershipChanged ) {
+        d.SetNewComputed("name")
+        d.SetNewComputed("reboot_pending")
+        d.SetNewComputed("reboot_pending_details")
+
+        return nil
+    }
+
+    // a new primary dns-suffix changes the full computer-name, the reboot_pending attributes are only known after applying the change
+    if d.HasChange("dns_client.0.primary_suffix") {
+        d.SetNewComputed("reboot_pending")
+        d.SetNewComputed("reboot_pending_details")
+
+        return nil
+    }
+
+    v, ok := d.GetOk("reboot_pending_details")
+    if !ok {
+        return nil
+    }
+    details := v.([]interface{})[0].(map[string]interface{})
+    detailsChanged := false
+
     // set reboot_pending attributes when new_name changes
     if d.HasChange("new_name") {
         oldName := d.Get("name").(string)
@@ -215,16 +444,23 @@ func resourceWindowsComputerCustomizeDiff(d *schema.ResourceDiff, m interface{})
         }
 
         if newComputerNamePending != oldComputerNamePending {
-            if v, ok := d.GetOk("reboot_pending_details"); ok {
-                details := v.([]interface{})[0].(map[string]interface{})
-                details["computer_rename_pending"] = newComputerNamePending
-                d.SetNew("reboot_pending_details", []interface{}{ details })
-
-                d.SetNewComputed("reboot_pending")
-            }
+            details["computer_rename_pending"] = newComputerNamePending
+            detailsChanged = true
         }
     }
 
+    // set reboot_pending attributes when domain_membership changes, joining or leaving a domain sets the netlogon reboot-signal
+    if domainMembershipChanged && !d.Get("reboot_pending_details.0.netlogon_pending").(bool) {
+        details["netlogon_pending"] = true
+        detailsChanged = true
+    }
+
+    if detailsChanged {
+        d.SetNew("reboot_pending_details", []interface{}{ details })
+
+        d.SetNewComputed("reboot_pending")
+    }
+
     return nil
 }
 
@@ -233,8 +469,10 @@ func resourceWindowsComputerCustomizeDiff(d *schema.ResourceDiff, m interface{})
 func resourceWindowsComputerCreate(d *schema.ResourceData, m interface{}) error {
     c := m.(*api.WindowsClient)
 
-    newName     := d.Get("newName")
-    dnsClient   := tfutil.GetResource(d, "dns_client")
+    newName          := d.Get("newName")
+    dnsClient        := tfutil.GetResource(d, "dns_client")
+    domainMembership := tfutil.GetResource(d, "domain_membership")
+    reboot           := d.Get("reboot").(string)
 
     host := "localhost"
     if c.Type != "local" {
@@ -249,21 +487,48 @@ func resourceWindowsComputerCreate(d *schema.ResourceData, m interface{}) error
                     [INFO][terraform-provider-windows]         suffix_search_list: %#v
                     [INFO][terraform-provider-windows]         enable_devolution:  %#v
                     [INFO][terraform-provider-windows]         devolution_level:   %#v
+                    [INFO][terraform-provider-windows]         primary_suffix:     %#v
+                    [INFO][terraform-provider-windows]         change_primary_suffix_with_membership: %#v
+                    [INFO][terraform-provider-windows]         doh_server:         %#v
                     [INFO][terraform-provider-windows]     }
+                    [INFO][terraform-provider-windows]     domain_membership {
+                    [INFO][terraform-provider-windows]         domain:    %#v
+                    [INFO][terraform-provider-windows]         workgroup: %#v
+                    [INFO][terraform-provider-windows]         ou_path:   %#v
+                    [INFO][terraform-provider-windows]         user:      %#v
+                    [INFO][terraform-provider-windows]         password:  ********
+                    [INFO][terraform-provider-windows]         options:   %#v
+                    [INFO][terraform-provider-windows]     }
+                    [INFO][terraform-provider-windows]     reboot: %#v
 `       ,
         id,
         newName,
         dnsClient["suffix_search_list"],
         dnsClient["enable_devolution"],
         dnsClient["devolution_level"],
+        dnsClient["primary_suffix"],
+        dnsClient["change_primary_suffix_with_membership"],
+        dnsClient["doh_server"],
+        domainMembership["domain"],
+        domainMembership["workgroup"],
+        domainMembership["ou_path"],
+        domainMembership["user"],
+        domainMembership["options"],
+        reboot,
     )
 
     // import
     log.Printf("[INFO][terraform-provider-windows] importing windows_computer %q into terraform state\n", id)
 
-    computer, err := c.ReadComputer()
+    x_lifecycle := tfutil.GetResource(d, "x_lifecycle")
+
+    // lifecycle customizations: wait_until_exists
+    var computer *api.Computer
+    err := tfutil.WaitUntilExists(x_lifecycle, "cannot connect to computer", func() (err error) {
+        computer, err = c.ReadComputer()
+        return err
+    })
     if err != nil {
-        // no lifecycle customizations
         log.Printf("[ERROR][terraform-provider-windows] cannot import windows_computer %q into terraform state\n", id)
         return err
     }
@@ -274,14 +539,17 @@ func resourceWindowsComputerCreate(d *schema.ResourceData, m interface{}) error
     if !diffComputerProperties(d, computer) {
         // no update required
 
-        // set properties
-        setComputerProperties(d, computer)
-
-        // set id
+        // set id before rebooting, so the original config is kept in the terraform state when the reboot fails
         d.SetId(id)
 
+        // reboot
+        err := rebootComputer(d, c, id, false)
+        if err != nil {
+            return err
+        }
+
         log.Printf("[INFO][terraform-provider-windows] created windows_computer %q\n", id)
-        return nil
+        return resourceWindowsComputerRead(d, m)
 
     } else {
         // update
@@ -296,9 +564,15 @@ func resourceWindowsComputerCreate(d *schema.ResourceData, m interface{}) error
             return err
         }
 
-        // set id
+        // set id before rebooting, so the original config is kept in the terraform state when the reboot fails
         d.SetId(id)
 
+        // reboot
+        err = rebootComputer(d, c, id, true)
+        if err != nil {
+            return err
+        }
+
         log.Printf("[INFO][terraform-provider-windows] created windows_computer %q\n", id)
         return resourceWindowsComputerRead(d, m)
     }
@@ -338,9 +612,11 @@ func resourceWindowsComputerRead(d *schema.ResourceData, m interface{}) error {
 func resourceWindowsComputerUpdate(d *schema.ResourceData, m interface{}) error {
     c := m.(*api.WindowsClient)
 
-    id        := d.Id()
-    newName   := d.Get("newName")
-    dnsClient := tfutil.GetResource(d, "dns_client")
+    id               := d.Id()
+    newName          := d.Get("newName")
+    dnsClient        := tfutil.GetResource(d, "dns_client")
+    domainMembership := tfutil.GetResource(d, "domain_membership")
+    reboot           := d.Get("reboot").(string)
 
     log.Printf(`[INFO][terraform-provider-windows] updating windows_computer %q
                     [INFO][terraform-provider-windows]     newName: %#v
@@ -348,13 +624,34 @@ func resourceWindowsComputerUpdate(d *schema.ResourceData, m interface{}) error
                     [INFO][terraform-provider-windows]         suffix_search_list: %#v
                     [INFO][terraform-provider-windows]         enable_devolution:  %#v
                     [INFO][terraform-provider-windows]         devolution_level:   %#v
+                    [INFO][terraform-provider-windows]         primary_suffix:     %#v
+                    [INFO][terraform-provider-windows]         change_primary_suffix_with_membership: %#v
+                    [INFO][terraform-provider-windows]         doh_server:         %#v
+                    [INFO][terraform-provider-windows]     }
+                    [INFO][terraform-provider-windows]     domain_membership {
+                    [INFO][terraform-provider-windows]         domain:    %#v
+                    [INFO][terraform-provider-windows]         workgroup: %#v
+                    [INFO][terraform-provider-windows]         ou_path:   %#v
+                    [INFO][terraform-provider-windows]         user:      %#v
+                    [INFO][terraform-provider-windows]         password:  ********
+                    [INFO][terraform-provider-windows]         options:   %#v
                     [INFO][terraform-provider-windows]     }
+                    [INFO][terraform-provider-windows]     reboot: %#v
 `       ,
         id,
         newName,
         dnsClient["suffix_search_list"],
         dnsClient["enable_devolution"],
         dnsClient["devolution_level"],
+        dnsClient["primary_suffix"],
+        dnsClient["change_primary_suffix_with_membership"],
+        dnsClient["doh_server"],
+        domainMembership["domain"],
+        domainMembership["workgroup"],
+        domainMembership["ou_path"],
+        domainMembership["user"],
+        domainMembership["options"],
+        reboot,
     )
 
     // update
@@ -367,6 +664,12 @@ func resourceWindowsComputerUpdate(d *schema.ResourceData, m interface{}) error
         return err
     }
 
+    // reboot
+    err = rebootComputer(d, c, id, d.HasChange("new_name") || d.HasChange("dns_client") || d.HasChange("domain_membership"))
+    if err != nil {
+        return err
+    }
+
     log.Printf("[INFO][terraform-provider-windows] updated windows_computer %q\n", id)
     return resourceWindowsComputerRead(d, m)
 }
@@ -399,6 +702,51 @@ func resourceWindowsComputerDelete(d *schema.ResourceData, m interface{}) error
 
 //------------------------------------------------------------------------------
 
+func rebootComputer(d *schema.ResourceData, c *api.WindowsClient, id string, changed bool) error {
+    switch d.Get("reboot").(string) {
+    case "if_pending":
+        computer, err := c.ReadComputer()
+        if err != nil {
+            log.Printf("[ERROR][terraform-provider-windows] cannot read windows_computer %q\n", id)
+            return err
+        }
+        if !computer.RebootPending {
+            return nil
+        }
+    case "always_after_change":
+        if !changed {
+            return nil
+        }
+    default:
+        return nil
+    }
+
+    log.Printf("[INFO][terraform-provider-windows] rebooting windows_computer %q\n", id)
+
+    timeout, _ := time.ParseDuration(d.Get("reboot_timeout").(string))
+    err := c.RebootComputer(timeout)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows] cannot reboot windows_computer %q\n", id)
+        return err
+    }
+
+    // verify
+    computer, err := c.ReadComputer()
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows] cannot read windows_computer %q after reboot\n", id)
+        return err
+    }
+    if computer.RebootPending {
+        log.Printf("[ERROR][terraform-provider-windows] reboot still pending for windows_computer %q after reboot\n", id)
+        return fmt.Errorf("[terraform-provider-windows] reboot still pending for windows_computer %q after reboot, see 'reboot_pending_details'", id)
+    }
+
+    log.Printf("[INFO][terraform-provider-windows] rebooted windows_computer %q\n", id)
+    return nil
+}
+
+//------------------------------------------------------------------------------
+
 func setComputerProperties(d *schema.ResourceData, cProperties *api.Computer) {
     d.Set("name", cProperties.Name)
     d.Set("new_name", cProperties.NewName)
@@ -407,8 +755,32 @@ func setComputerProperties(d *schema.ResourceData, cProperties *api.Computer) {
     dnsClient["suffix_search_list"] = cProperties.DNSClient.SuffixSearchList
     dnsClient["enable_devolution"]  = cProperties.DNSClient.EnableDevolution
     dnsClient["devolution_level"]   = cProperties.DNSClient.DevolutionLevel
+    if cProperties.DNSClient.PrimarySuffix == "" {
+        // replace "" with "<empty>" (because "" is a valid value and would get skipped in diff since it is equal to the zero value)
+        dnsClient["primary_suffix"] = "<empty>"
+    } else {
+        dnsClient["primary_suffix"] = cProperties.DNSClient.PrimarySuffix
+    }
+    dnsClient["change_primary_suffix_with_membership"] = cProperties.DNSClient.ChangePrimarySuffixWithMembership
+    dnsClient["doh_server"] = flattenComputerDoHServers(cProperties.DNSClient.DoHServers)
     d.Set("dns_client", []interface{}{ dnsClient })
 
+    domainMembership := make(map[string]interface{})
+    domainMembership["domain"]          = cProperties.DomainMembership.Domain
+    domainMembership["workgroup"]       = cProperties.DomainMembership.Workgroup
+    domainMembership["ou_path"]         = d.Get("domain_membership.0.ou_path")
+    domainMembership["user"]            = d.Get("domain_membership.0.user")
+    domainMembership["password"]        = d.Get("domain_membership.0.password")
+    domainMembership["unjoin_user"]     = d.Get("domain_membership.0.unjoin_user")
+    domainMembership["unjoin_password"] = d.Get("domain_membership.0.unjoin_password")
+    domainMembership["options"]         = d.Get("domain_membership.0.options")
+    domainMembership["part_of_domain"]  = cProperties.DomainMembership.PartOfDomain
+    if v := d.Get("domain_membership.0.domain").(string); ( v != "" ) && cProperties.DomainMembership.PartOfDomain && strings.EqualFold(v, cProperties.DomainMembership.NetBIOSName) {
+        // keep the NetBIOS name of the domain when it is used in the config
+        domainMembership["domain"] = v
+    }
+    d.Set("domain_membership", []interface{}{ domainMembership })
+
     d.Set("reboot_pending", cProperties.RebootPending)
 
     rebootPendingDetails := make(map[string]interface{})
@@ -439,8 +811,16 @@ func setOriginalComputerProperties(d *schema.ResourceData, cProperties *api.Comp
     original_dnsClient["suffix_search_list"] = cProperties.DNSClient.SuffixSearchList
     original_dnsClient["enable_devolution"]  = cProperties.DNSClient.EnableDevolution
     original_dnsClient["devolution_level"]   = cProperties.DNSClient.DevolutionLevel
+    original_dnsClient["primary_suffix"]     = cProperties.DNSClient.PrimarySuffix
+    original_dnsClient["change_primary_suffix_with_membership"] = cProperties.DNSClient.ChangePrimarySuffixWithMembership
+    original_dnsClient["doh_server"]         = flattenComputerDoHServers(cProperties.DNSClient.DoHServers)
     original["dns_client"] = []interface{}{ original_dnsClient }
 
+    original_domainMembership := make(map[string]interface{})
+    original_domainMembership["domain"]    = cProperties.DomainMembership.Domain
+    original_domainMembership["workgroup"] = cProperties.DomainMembership.Workgroup
+    original["domain_membership"] = []interface{}{ original_domainMembership }
+
     d.Set("original", []interface{}{ original })
 }
 
@@ -461,6 +841,29 @@ func diffComputerProperties(d *schema.ResourceData, cProperties *api.Computer) b
         if v, ok := d.GetOkExists("dns_client.0.devolution_level"); ok && ( cProperties.DNSClient.DevolutionLevel != uint32(v.(int)) ) {
             return true
         }
+        if v, ok := d.GetOkExists("dns_client.0.primary_suffix"); ok &&
+           ( ( ( v.(string) != "<empty>" ) && ( cProperties.DNSClient.PrimarySuffix != v.(string) ) )   ||
+             ( ( v.(string) == "<empty>" ) && ( cProperties.DNSClient.PrimarySuffix != ""         ) ) ) {
+            return true
+        }
+        if v, ok := d.GetOkExists("dns_client.0.change_primary_suffix_with_membership"); ok && ( cProperties.DNSClient.ChangePrimarySuffixWithMembership != v.(bool) ) {
+            return true
+        }
+        if _, ok := d.GetOkExists("dns_client.0.doh_server"); ok {
+            dnsClient := tfutil.GetListOfResources(d, "dns_client")[0]
+            if !reflect.DeepEqual(cProperties.DNSClient.DoHServers, expandComputerDoHServers(tfutil.ExpandSetOfResources(dnsClient, "doh_server"))) {
+                return true
+            }
+        }
+    }
+
+    if v, ok := d.GetOk("domain_membership"); ok && ( len(v.([]interface{})) > 0 ) {
+        if v, ok := d.GetOk("domain_membership.0.domain"); ok && !strings.EqualFold(cProperties.DomainMembership.Domain, v.(string)) && !strings.EqualFold(cProperties.DomainMembership.NetBIOSName, v.(string)) {
+            return true
+        }
+        if v, ok := d.GetOk("domain_membership.0.workgroup"); ok && ( cProperties.DomainMembership.Workgroup != v.(string) ) {
+            return true
+        }
     }
 
     return false
@@ -498,6 +901,56 @@ func expandComputerProperties(cProperties *api.Computer, d *schema.ResourceData)
             original_dnsClient := tfutil.GetResource(d, "original.0.dns_client")
             cProperties.DNSClient.DevolutionLevel = uint32(original_dnsClient["devolution_level"].(int))
         }
+
+        if v, ok := d.GetOkExists("dns_client.0.primary_suffix"); ok {
+            if v.(string) == "<empty>" {
+                // "" has been replaced with "<empty>" (because "" is a valid value and would get skipped in diff since it is equal to the zero value)
+                cProperties.DNSClient.PrimarySuffix = ""
+            } else {
+                cProperties.DNSClient.PrimarySuffix = v.(string)
+            }
+        } else {
+            // there is no state yet (the resource is being created), and the attribute is not defined in config
+            // since we don't have a state yet, we use the previously read original properties to get the current value (using the zero-value "", would overwrite the current value)
+            original_dnsClient := tfutil.GetResource(d, "original.0.dns_client")
+            cProperties.DNSClient.PrimarySuffix = original_dnsClient["primary_suffix"].(string)
+        }
+
+        if v, ok := d.GetOkExists("dns_client.0.change_primary_suffix_with_membership"); ok {
+            cProperties.DNSClient.ChangePrimarySuffixWithMembership = v.(bool)
+        } else {
+            // there is no state yet (the resource is being created), and the attribute is not defined in config
+            // since we don't have a state yet, we use the previously read original properties to get the current value (using the zero-value 'false', would overwrite the current value)
+            original_dnsClient := tfutil.GetResource(d, "original.0.dns_client")
+            cProperties.DNSClient.ChangePrimarySuffixWithMembership = original_dnsClient["change_primary_suffix_with_membership"].(bool)
+        }
+
+        if _, ok := d.GetOkExists("dns_client.0.doh_server"); ok {
+            dnsClient := dnsClientList[0]
+            cProperties.DNSClient.DoHServers = expandComputerDoHServers(tfutil.ExpandSetOfResources(dnsClient, "doh_server"))
+        } else {
+            // there is no state yet (the resource is being created), and the attribute is not defined in config
+            // since we don't have a state yet, we use the previously read original properties to get the current value (using the zero-value 'nil', would overwrite the current value)
+            original_dnsClient := tfutil.GetResource(d, "original.0.dns_client")
+            cProperties.DNSClient.DoHServers = expandComputerDoHServers(tfutil.ExpandListOfResources(original_dnsClient, "doh_server"))
+        }
+    } else {
+        // there is no state yet (the resource is being created), and the dns_client is not defined in config
+        // we use the previously read original properties to keep the current values (using the zero-values, would overwrite the current values)
+        expandOriginalComputerDNSClientProperties(cProperties, d)
+    }
+
+    domainMembershipList := tfutil.GetListOfResources(d, "domain_membership")
+    if len(domainMembershipList) > 0 {
+        domainMembership := domainMembershipList[0]
+        cProperties.DomainMembership.Domain    = domainMembership["domain"].(string)
+        cProperties.DomainMembership.Workgroup = domainMembership["workgroup"].(string)
+        cProperties.DomainMembership.OUPath    = domainMembership["ou_path"].(string)
+        cProperties.DomainMembership.User           = domainMembership["user"].(string)
+        cProperties.DomainMembership.Password       = domainMembership["password"].(string)
+        cProperties.DomainMembership.UnjoinUser     = domainMembership["unjoin_user"].(string)
+        cProperties.DomainMembership.UnjoinPassword = domainMembership["unjoin_password"].(string)
+        cProperties.DomainMembership.Options        = tfutil.ExpandListOfStrings(domainMembership, "options")
     }
 }
 
@@ -506,10 +959,69 @@ func expandOriginalComputerProperties(cProperties *api.Computer, d *schema.Resou
 
     cProperties.NewName = original["new_name"].(string)
 
-    original_dnsClient := tfutil.ExpandResource(original, "dns_client")
+    expandOriginalComputerDNSClientProperties(cProperties, d)
+
+    // the credentials to join or leave a domain are taken from the config, they are not saved with the original properties
+    original_domainMembership := tfutil.ExpandResource(original, "domain_membership")
+    if len(original_domainMembership) > 0 {
+        domainMembership := tfutil.GetResource(d, "domain_membership")
+        cProperties.DomainMembership.Domain    = original_domainMembership["domain"].(string)
+        cProperties.DomainMembership.Workgroup = original_domainMembership["workgroup"].(string)
+        if v, ok := domainMembership["user"]; ok {
+            cProperties.DomainMembership.User     = v.(string)
+            cProperties.DomainMembership.Password = domainMembership["password"].(string)
+        }
+        if v, ok := domainMembership["unjoin_user"]; ok && ( v.(string) != "" ) {
+            // moving back to the original domain, the credentials for joining and leaving are swapped
+            cProperties.DomainMembership.UnjoinUser     = cProperties.DomainMembership.User
+            cProperties.DomainMembership.UnjoinPassword = cProperties.DomainMembership.Password
+            cProperties.DomainMembership.User           = v.(string)
+            cProperties.DomainMembership.Password       = domainMembership["unjoin_password"].(string)
+        }
+    }
+}
+
+//------------------------------------------------------------------------------
+
+func expandOriginalComputerDNSClientProperties(cProperties *api.Computer, d *schema.ResourceData) {
+    original_dnsClient := tfutil.GetResource(d, "original.0.dns_client")
     cProperties.DNSClient.SuffixSearchList = tfutil.ExpandListOfStrings(original_dnsClient, "suffix_search_list")
     cProperties.DNSClient.EnableDevolution = original_dnsClient["enable_devolution"].(bool)
     cProperties.DNSClient.DevolutionLevel  = uint32(original_dnsClient["devolution_level"].(int))
+    cProperties.DNSClient.PrimarySuffix    = original_dnsClient["primary_suffix"].(string)
+    cProperties.DNSClient.ChangePrimarySuffixWithMembership = original_dnsClient["change_primary_suffix_with_membership"].(bool)
+    cProperties.DNSClient.DoHServers       = expandComputerDoHServers(tfutil.ExpandListOfResources(original_dnsClient, "doh_server"))
+}
+
+//------------------------------------------------------------------------------
+
+func flattenComputerDoHServers(dohServers []api.ComputerDNSClientDoHServer) []interface{} {
+    l := make([]interface{}, len(dohServers))
+    for i, dohServer := range dohServers {
+        m := make(map[string]interface{})
+        m["server_address"]        = dohServer.ServerAddress
+        m["doh_template"]          = dohServer.DoHTemplate
+        m["allow_fallback_to_udp"] = dohServer.AllowFallbackToUDP
+        m["auto_upgrade"]          = dohServer.AutoUpgrade
+        l[i] = m
+    }
+
+    return l
+}
+
+func expandComputerDoHServers(l []map[string]interface{}) []api.ComputerDNSClientDoHServer {
+    dohServers := make([]api.ComputerDNSClientDoHServer, len(l))
+    for i, m := range l {
+        dohServers[i].ServerAddress      = m["server_address"].(string)
+        dohServers[i].DoHTemplate        = m["doh_template"].(string)
+        dohServers[i].AllowFallbackToUDP = m["allow_fallback_to_udp"].(bool)
+        dohServers[i].AutoUpgrade        = m["auto_upgrade"].(bool)
+    }
+
+    // sort like the windows-computer returns them
+    sort.Slice(dohServers, func(i, j int) bool { return dohServers[i].ServerAddress < dohServers[j].ServerAddress })
+
+    return dohServers
 }
 
 //------------------------------------------------------------------------------
@@ -0,0 +1,424 @@
+//
+// Copyright (c) 2019 Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-windows
+//
+package windows
+
+import (
+    "fmt"
+    "log"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+    "github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+
+    "github.com/stefaanc/terraform-provider-windows/api"
+    "github.com/stefaanc/terraform-provider-windows/windows/tfutil"
+)
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsFirewallProfile() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "name": &schema.Schema{
+                Type:     schema.TypeString,
+                Required: true,
+                ForceNew: true,
+
+                ValidateFunc: validation.StringInSlice([]string{ "Domain", "Private", "Public" }, false),
+            },
+
+            "enabled": &schema.Schema{
+                Type:     schema.TypeBool,
+                Optional: true,
+                Computed: true,
+            },
+            "default_inbound_action": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Computed: true,
+
+                ValidateFunc: validation.StringInSlice([]string{ "Allow", "Block", "NotConfigured" }, false),
+            },
+            "default_outbound_action": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Computed: true,
+
+                ValidateFunc: validation.StringInSlice([]string{ "Allow", "Block", "NotConfigured" }, false),
+            },
+            "notify_on_listen": &schema.Schema{
+                Type:     schema.TypeBool,
+                Optional: true,
+                Computed: true,
+            },
+
+            "log_file_name": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Computed: true,
+            },
+            "log_max_size_kilobytes": &schema.Schema{
+                Type:     schema.TypeInt,   // uint32
+                Optional: true,
+                Computed: true,
+
+                ValidateFunc: validation.IntBetween(1, 32767),
+            },
+            "log_allowed": &schema.Schema{
+                Type:     schema.TypeBool,
+                Optional: true,
+                Computed: true,
+            },
+            "log_blocked": &schema.Schema{
+                Type:     schema.TypeBool,
+                Optional: true,
+                Computed: true,
+            },
+
+            // used to reset values on terraform destroy
+            "original": &schema.Schema{
+                Type:     schema.TypeList,
+                MaxItems: 1,
+                Computed: true,
+                Elem: resourceWindowsFirewallProfileOriginal(),
+            },
+        },
+
+        Create: resourceWindowsFirewallProfileCreate,
+        Read:   resourceWindowsFirewallProfileRead,
+        Update: resourceWindowsFirewallProfileUpdate,
+        Delete: resourceWindowsFirewallProfileDelete,
+    }
+}
+
+func resourceWindowsFirewallProfileOriginal() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "enabled": &schema.Schema{
+                Type:     schema.TypeBool,
+                Computed: true,
+            },
+            "default_inbound_action": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "default_outbound_action": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "notify_on_listen": &schema.Schema{
+                Type:     schema.TypeBool,
+                Computed: true,
+            },
+            "log_file_name": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "log_max_size_kilobytes": &schema.Schema{
+                Type:     schema.TypeInt,
+                Computed: true,
+            },
+            "log_allowed": &schema.Schema{
+                Type:     schema.TypeBool,
+                Computed: true,
+            },
+            "log_blocked": &schema.Schema{
+                Type:     schema.TypeBool,
+                Computed: true,
+            },
+            "not_configured": &schema.Schema{
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem:     &schema.Schema{ Type: schema.TypeString },
+            },
+        },
+    }
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsFirewallProfileCreate(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    name := d.Get("name").(string)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    id := fmt.Sprintf("//%s/firewall_profiles/%s", host, strings.ToLower(name))
+
+    log.Printf(`[INFO][terraform-provider-windows] creating windows_firewall_profile %q
+                    [INFO][terraform-provider-windows]     name:                    %#v
+                    [INFO][terraform-provider-windows]     enabled:                 %#v
+                    [INFO][terraform-provider-windows]     default_inbound_action:  %#v
+                    [INFO][terraform-provider-windows]     default_outbound_action: %#v
+                    [INFO][terraform-provider-windows]     notify_on_listen:        %#v
+                    [INFO][terraform-provider-windows]     log_file_name:           %#v
+                    [INFO][terraform-provider-windows]     log_max_size_kilobytes:  %#v
+                    [INFO][terraform-provider-windows]     log_allowed:             %#v
+                    [INFO][terraform-provider-windows]     log_blocked:             %#v
+`       ,
+        id,
+        name,
+        d.Get("enabled"),
+        d.Get("default_inbound_action"),
+        d.Get("default_outbound_action"),
+        d.Get("notify_on_listen"),
+        d.Get("log_file_name"),
+        d.Get("log_max_size_kilobytes"),
+        d.Get("log_allowed"),
+        d.Get("log_blocked"),
+    )
+
+    // import
+    log.Printf("[INFO][terraform-provider-windows] importing windows_firewall_profile %q into terraform state\n", id)
+
+    fpQuery := new(api.FirewallProfile)
+    fpQuery.Name = name
+
+    firewallProfile, err := c.ReadFirewallProfile(fpQuery)
+    if err != nil {
+        // no lifecycle customizations
+        log.Printf("[ERROR][terraform-provider-windows] cannot import windows_firewall_profile %q into terraform state\n", id)
+        return err
+    }
+
+    // save original config
+    setOriginalFirewallProfileProperties(d, firewallProfile)
+
+    // update
+    fpProperties := new(api.FirewallProfile)
+    expandFirewallProfileProperties(fpProperties, d)
+
+    err = c.UpdateFirewallProfile(fpQuery, fpProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows] cannot update windows_firewall_profile %q\n", id)
+        return err
+    }
+
+    // set id
+    d.SetId(id)
+
+    log.Printf("[INFO][terraform-provider-windows] created windows_firewall_profile %q\n", id)
+    return resourceWindowsFirewallProfileRead(d, m)
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsFirewallProfileRead(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    id := d.Id()
+
+    log.Printf("[INFO][terraform-provider-windows] reading windows_firewall_profile %q\n", id)
+
+    // read
+    fpQuery := new(api.FirewallProfile)
+    fpQuery.Name = d.Get("name").(string)
+
+    firewallProfile, err := c.ReadFirewallProfile(fpQuery)
+    if err != nil {
+        if strings.Contains(err.Error(), "cannot find firewall_profile") {
+            log.Printf("[INFO][terraform-provider-windows] cannot find windows_firewall_profile %q\n", id)
+
+            // set id
+            d.SetId("")
+
+            log.Printf("[INFO][terraform-provider-windows] deleted windows_firewall_profile %q from terraform state\n", id)
+            return nil   // don't return an error to allow terraform refresh to update state
+        }
+
+        log.Printf("[ERROR][terraform-provider-windows] cannot read windows_firewall_profile %q\n", id)
+        return err
+    }
+
+    // set properties
+    setFirewallProfileProperties(d, firewallProfile)
+
+    log.Printf("[INFO][terraform-provider-windows] read windows_firewall_profile %q\n", id)
+    return nil
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsFirewallProfileUpdate(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    id := d.Id()
+
+    log.Printf(`[INFO][terraform-provider-windows] updating windows_firewall_profile %q
+                    [INFO][terraform-provider-windows]     enabled:                 %#v
+                    [INFO][terraform-provider-windows]     default_inbound_action:  %#v
+                    [INFO][terraform-provider-windows]     default_outbound_action: %#v
+                    [INFO][terraform-provider-windows]     notify_on_listen:        %#v
+                    [INFO][terraform-provider-windows]     log_file_name:           %#v
+                    [INFO][terraform-provider-windows]     log_max_size_kilobytes:  %#v
+                    [INFO][terraform-provider-windows]     log_allowed:             %#v
+                    [INFO][terraform-provider-windows]     log_blocked:             %#v
+`       ,
+        id,
+        d.Get("enabled"),
+        d.Get("default_inbound_action"),
+        d.Get("default_outbound_action"),
+        d.Get("notify_on_listen"),
+        d.Get("log_file_name"),
+        d.Get("log_max_size_kilobytes"),
+        d.Get("log_allowed"),
+        d.Get("log_blocked"),
+    )
+
+    // update
+    fpQuery := new(api.FirewallProfile)
+    fpQuery.Name = d.Get("name").(string)
+
+    fpProperties := new(api.FirewallProfile)
+    expandFirewallProfileProperties(fpProperties, d)
+
+    err := c.UpdateFirewallProfile(fpQuery, fpProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows] cannot update windows_firewall_profile %q\n", id)
+        return err
+    }
+
+    log.Printf("[INFO][terraform-provider-windows] updated windows_firewall_profile %q\n", id)
+    return resourceWindowsFirewallProfileRead(d, m)
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsFirewallProfileDelete(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    id := d.Id()
+
+    log.Printf("[INFO][terraform-provider-windows] deleting windows_firewall_profile %q from terraform state\n", id)
+    log.Printf("[INFO][terraform-provider-windows] restore original config for windows_firewall_profile %q\n", id)
+
+    // restore original config
+    fpQuery := new(api.FirewallProfile)
+    fpQuery.Name = d.Get("name").(string)
+
+    fpProperties := new(api.FirewallProfile)
+    expandOriginalFirewallProfileProperties(fpProperties, d)
+
+    err := c.UpdateFirewallProfile(fpQuery, fpProperties)
+    if err != nil {
+        log.Printf("[WARNING][terraform-provider-windows] cannot restore original config for windows_firewall_profile %q\n", id)
+    }
+
+    // set id
+    d.SetId("")
+
+    log.Printf("[INFO][terraform-provider-windows] deleted windows_firewall_profile %q from terraform state\n", id)
+    return nil
+}
+
+//------------------------------------------------------------------------------
+
+func setFirewallProfileProperties(d *schema.ResourceData, fpProperties *api.FirewallProfile) {
+    d.Set("name", fpProperties.Name)
+
+    d.Set("enabled", fpProperties.Enabled)
+    d.Set("default_inbound_action", fpProperties.DefaultInboundAction)
+    d.Set("default_outbound_action", fpProperties.DefaultOutboundAction)
+    d.Set("notify_on_listen", fpProperties.NotifyOnListen)
+
+    d.Set("log_file_name", fpProperties.LogFileName)
+    d.Set("log_max_size_kilobytes", int(fpProperties.LogMaxSizeKilobytes))
+    d.Set("log_allowed", fpProperties.LogAllowed)
+    d.Set("log_blocked", fpProperties.LogBlocked)
+}
+
+func setOriginalFirewallProfileProperties(d *schema.ResourceData, fpProperties *api.FirewallProfile) {
+    original := make(map[string]interface{})
+
+    original["enabled"]                 = fpProperties.Enabled
+    original["default_inbound_action"]  = fpProperties.DefaultInboundAction
+    original["default_outbound_action"] = fpProperties.DefaultOutboundAction
+    original["notify_on_listen"]        = fpProperties.NotifyOnListen
+    original["log_file_name"]           = fpProperties.LogFileName
+    original["log_max_size_kilobytes"]  = int(fpProperties.LogMaxSizeKilobytes)
+    original["log_allowed"]             = fpProperties.LogAllowed
+    original["log_blocked"]             = fpProperties.LogBlocked
+    original["not_configured"]          = fpProperties.NotConfigured
+
+    d.Set("original", []interface{}{ original })
+}
+
+//------------------------------------------------------------------------------
+
+func expandFirewallProfileProperties(fpProperties *api.FirewallProfile, d *schema.ResourceData) {
+    // when the resource is being created, the attributes that are not defined in config have no state yet
+    // we use the previously read original properties to get the current value (using the zero-value would overwrite the current value)
+    original := tfutil.GetResource(d, "original")
+
+    if v, ok := d.GetOkExists("enabled"); ok {
+        fpProperties.Enabled = v.(bool)
+    } else {
+        fpProperties.Enabled = original["enabled"].(bool)
+    }
+
+    if v, ok := d.GetOkExists("default_inbound_action"); ok {
+        fpProperties.DefaultInboundAction = v.(string)
+    } else {
+        fpProperties.DefaultInboundAction = original["default_inbound_action"].(string)
+    }
+
+    if v, ok := d.GetOkExists("default_outbound_action"); ok {
+        fpProperties.DefaultOutboundAction = v.(string)
+    } else {
+        fpProperties.DefaultOutboundAction = original["default_outbound_action"].(string)
+    }
+
+    if v, ok := d.GetOkExists("notify_on_listen"); ok {
+        fpProperties.NotifyOnListen = v.(bool)
+    } else {
+        fpProperties.NotifyOnListen = original["notify_on_listen"].(bool)
+    }
+
+    if v, ok := d.GetOkExists("log_file_name"); ok {
+        fpProperties.LogFileName = v.(string)
+    } else {
+        fpProperties.LogFileName = original["log_file_name"].(string)
+    }
+
+    if v, ok := d.GetOkExists("log_max_size_kilobytes"); ok {
+        fpProperties.LogMaxSizeKilobytes = uint32(v.(int))
+    } else {
+        fpProperties.LogMaxSizeKilobytes = uint32(original["log_max_size_kilobytes"].(int))
+    }
+
+    if v, ok := d.GetOkExists("log_allowed"); ok {
+        fpProperties.LogAllowed = v.(bool)
+    } else {
+        fpProperties.LogAllowed = original["log_allowed"].(bool)
+    }
+
+    if v, ok := d.GetOkExists("log_blocked"); ok {
+        fpProperties.LogBlocked = v.(bool)
+    } else {
+        fpProperties.LogBlocked = original["log_blocked"].(bool)
+    }
+}
+
+func expandOriginalFirewallProfileProperties(fpProperties *api.FirewallProfile, d *schema.ResourceData) {
+    original := tfutil.GetResource(d, "original")
+
+    fpProperties.Enabled               = original["enabled"].(bool)
+    fpProperties.DefaultInboundAction  = original["default_inbound_action"].(string)
+    fpProperties.DefaultOutboundAction = original["default_outbound_action"].(string)
+    fpProperties.NotifyOnListen        = original["notify_on_listen"].(bool)
+    fpProperties.LogFileName           = original["log_file_name"].(string)
+    fpProperties.LogMaxSizeKilobytes   = uint32(original["log_max_size_kilobytes"].(int))
+    fpProperties.LogAllowed            = original["log_allowed"].(bool)
+    fpProperties.LogBlocked            = original["log_blocked"].(bool)
+    fpProperties.NotConfigured         = tfutil.ExpandListOfStrings(original, "not_configured")
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,637 @@
+//
+// Copyright (c) 2019 Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-windows
+//
+package windows
+
+import (
+    "fmt"
+    "log"
+    "regexp"
+    "strconv"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+    "github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+
+    "github.com/stefaanc/terraform-provider-windows/api"
+    "github.com/stefaanc/terraform-provider-windows/windows/tfutil"
+)
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsFirewallRule() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "name": &schema.Schema{
+                Type:     schema.TypeString,
+                Required: true,
+                ForceNew: true,
+            },
+            "display_name": &schema.Schema{
+                Type:     schema.TypeString,
+                Required: true,
+            },
+            "description": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Default:  "",
+            },
+            "group": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Default:  "",
+                ForceNew: true,
+            },
+
+            "enabled": &schema.Schema{
+                Type:     schema.TypeBool,
+                Optional: true,
+                Default:  true,
+            },
+            "direction": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Default:  "Inbound",
+
+                ValidateFunc: validation.StringInSlice([]string{ "Inbound", "Outbound" }, false),
+            },
+            "action": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Default:  "Allow",
+
+                ValidateFunc: validation.StringInSlice([]string{ "Allow", "Block" }, false),
+            },
+            "profiles": &schema.Schema{
+                Type:     schema.TypeSet,
+                Optional: true,
+                Elem: &schema.Schema{
+                    Type: schema.TypeString,
+
+                    ValidateFunc: validation.StringInSlice([]string{ "Domain", "Private", "Public" }, false),
+                },
+            },
+
+            "protocol": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Default:  "Any",
+
+                ValidateFunc: validation.StringMatch(regexp.MustCompile(`^(Any|TCP|UDP|ICMPv4|ICMPv6|[0-9]{1,3})$`), "expected \"Any\", \"TCP\", \"UDP\", \"ICMPv4\", \"ICMPv6\" or a protocol number"),
+                StateFunc: stateFirewallRuleProtocol(),
+            },
+            "local_ports": &schema.Schema{
+                Type:     schema.TypeSet,
+                Optional: true,
+                Elem: &schema.Schema{
+                    Type: schema.TypeString,
+                },
+            },
+            "remote_ports": &schema.Schema{
+                Type:     schema.TypeSet,
+                Optional: true,
+                Elem: &schema.Schema{
+                    Type: schema.TypeString,
+                },
+            },
+            "local_addresses": &schema.Schema{
+                Type:     schema.TypeSet,
+                Optional: true,
+                Elem: &schema.Schema{
+                    Type: schema.TypeString,
+                },
+            },
+            "remote_addresses": &schema.Schema{
+                Type:     schema.TypeSet,
+                Optional: true,
+                Elem: &schema.Schema{
+                    Type: schema.TypeString,
+                },
+            },
+
+            "program": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Default:  "",
+            },
+            "service": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Default:  "",
+            },
+            "interface_aliases": &schema.Schema{
+                Type:     schema.TypeSet,
+                Optional: true,
+                Elem: &schema.Schema{
+                    Type: schema.TypeString,
+                },
+            },
+
+            "display_group": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+
+            // lifecycle customizations that are not supported by the 'lifecycle' meta-argument
+            "x_lifecycle": &tfutil.ResourceXLifecycleSchema,
+
+            // used to reset values on terraform destroy
+            "original": &schema.Schema{
+                Type:     schema.TypeList,
+                MaxItems: 1,
+                Computed: true,
+                Elem: resourceWindowsFirewallRuleOriginal(),
+            },
+        },
+
+        Create: resourceWindowsFirewallRuleCreate,
+        Read:   resourceWindowsFirewallRuleRead,
+        Update: resourceWindowsFirewallRuleUpdate,
+        Delete: resourceWindowsFirewallRuleDelete,
+    }
+}
+
+func resourceWindowsFirewallRuleOriginal() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "display_name": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "description": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "enabled": &schema.Schema{
+                Type:     schema.TypeBool,
+                Computed: true,
+            },
+            "direction": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "action": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "profiles": &schema.Schema{
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem: &schema.Schema{
+                    Type: schema.TypeString,
+                },
+            },
+            "protocol": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "local_ports": &schema.Schema{
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem: &schema.Schema{
+                    Type: schema.TypeString,
+                },
+            },
+            "remote_ports": &schema.Schema{
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem: &schema.Schema{
+                    Type: schema.TypeString,
+                },
+            },
+            "local_addresses": &schema.Schema{
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem: &schema.Schema{
+                    Type: schema.TypeString,
+                },
+            },
+            "remote_addresses": &schema.Schema{
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem: &schema.Schema{
+                    Type: schema.TypeString,
+                },
+            },
+            "program": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "service": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "interface_aliases": &schema.Schema{
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem: &schema.Schema{
+                    Type: schema.TypeString,
+                },
+            },
+        },
+    }
+}
+
+//------------------------------------------------------------------------------
+
+// Windows reports the protocols that have a name by their name
+var firewallRuleProtocolNames = map[int]string{
+    1:  "ICMPv4",
+    6:  "TCP",
+    17: "UDP",
+    58: "ICMPv6",
+}
+
+func stateFirewallRuleProtocol() schema.SchemaStateFunc {
+    return func(val interface{}) string {
+        v := val.(string)
+        if n, err := strconv.Atoi(v); err == nil {
+            if name, ok := firewallRuleProtocolNames[n]; ok {
+                return name
+            }
+            return strconv.Itoa(n)
+        }
+        return v
+    }
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsFirewallRuleCreate(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    name             := d.Get("name").(string)
+    displayName      := d.Get("display_name").(string)
+    description      := d.Get("description").(string)
+    group            := d.Get("group").(string)
+    enabled          := d.Get("enabled").(bool)
+    direction        := d.Get("direction").(string)
+    action           := d.Get("action").(string)
+    profiles         := tfutil.GetSetOfStrings(d, "profiles")
+    protocol         := d.Get("protocol").(string)
+    localPorts       := tfutil.GetSetOfStrings(d, "local_ports")
+    remotePorts      := tfutil.GetSetOfStrings(d, "remote_ports")
+    localAddresses   := tfutil.GetSetOfStrings(d, "local_addresses")
+    remoteAddresses  := tfutil.GetSetOfStrings(d, "remote_addresses")
+    program          := d.Get("program").(string)
+    service          := d.Get("service").(string)
+    interfaceAliases := tfutil.GetSetOfStrings(d, "interface_aliases")
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    id := fmt.Sprintf("//%s/firewall_rules/%s", host, name)
+
+    log.Printf(`[INFO][terraform-provider-windows] creating windows_firewall_rule %q
+                    [INFO][terraform-provider-windows]     name:              %#v
+                    [INFO][terraform-provider-windows]     display_name:      %#v
+                    [INFO][terraform-provider-windows]     description:       %#v
+                    [INFO][terraform-provider-windows]     group:             %#v
+                    [INFO][terraform-provider-windows]     enabled:           %#v
+                    [INFO][terraform-provider-windows]     direction:         %#v
+                    [INFO][terraform-provider-windows]     action:            %#v
+                    [INFO][terraform-provider-windows]     profiles:          %#v
+                    [INFO][terraform-provider-windows]     protocol:          %#v
+                    [INFO][terraform-provider-windows]     local_ports:       %#v
+                    [INFO][terraform-provider-windows]     remote_ports:      %#v
+                    [INFO][terraform-provider-windows]     local_addresses:   %#v
+                    [INFO][terraform-provider-windows]     remote_addresses:  %#v
+                    [INFO][terraform-provider-windows]     program:           %#v
+                    [INFO][terraform-provider-windows]     service:           %#v
+                    [INFO][terraform-provider-windows]     interface_aliases: %#v
+`       ,
+        id,
+        name,
+        displayName,
+        description,
+        group,
+        enabled,
+        direction,
+        action,
+        profiles,
+        protocol,
+        localPorts,
+        remotePorts,
+        localAddresses,
+        remoteAddresses,
+        program,
+        service,
+        interfaceAliases,
+    )
+
+    x_lifecycle := tfutil.GetResource(d, "x_lifecycle")
+
+    frQuery := new(api.FirewallRule)
+    frQuery.Name = name
+
+    firewallRule, err := c.ReadFirewallRule(frQuery)
+    if err == nil {
+        // lifecycle customizations: import_if_exists
+        v, ok := x_lifecycle["import_if_exists"]
+        if !ok || !v.(bool) {
+            log.Printf("[ERROR][terraform-provider-windows] cannot create windows_firewall_rule %q\n", id)
+            return fmt.Errorf("[ERROR][terraform-provider-windows] cannot create windows_firewall_rule %q, rule already exists", id)
+        }
+
+        log.Printf("[INFO][terraform-provider-windows] importing windows_firewall_rule %q into terraform state\n", id)
+
+        // set computed lifecycle properties
+        x_lifecycle["imported"] = true
+        d.Set("x_lifecycle", []interface{}{ x_lifecycle })
+
+        // save original config
+        setOriginalFirewallRuleProperties(d, firewallRule)
+
+        // update
+        frProperties := new(api.FirewallRule)
+        expandFirewallRuleProperties(frProperties, d)
+
+        err := c.UpdateFirewallRule(frQuery, frProperties)
+        if err != nil {
+            log.Printf("[ERROR][terraform-provider-windows] cannot update windows_firewall_rule %q\n", id)
+            return err
+        }
+
+        // set id
+        d.SetId(id)
+
+        log.Printf("[INFO][terraform-provider-windows] created windows_firewall_rule %q\n", id)
+        return resourceWindowsFirewallRuleRead(d, m)
+    }
+    if !strings.Contains(err.Error(), "cannot find firewall_rule") {
+        log.Printf("[ERROR][terraform-provider-windows] cannot create windows_firewall_rule %q\n", id)
+        return err
+    }
+
+    // set computed lifecycle properties
+    x_lifecycle["imported"] = false
+    d.Set("x_lifecycle", []interface{}{ x_lifecycle })
+
+    // create
+    frProperties := new(api.FirewallRule)
+    frProperties.Name  = name
+    frProperties.Group = group
+    expandFirewallRuleProperties(frProperties, d)
+
+    err = c.CreateFirewallRule(frProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows] cannot create windows_firewall_rule %q\n", id)
+        return err
+    }
+
+    // set id
+    d.SetId(id)
+
+    log.Printf("[INFO][terraform-provider-windows] created windows_firewall_rule %q\n", id)
+    return resourceWindowsFirewallRuleRead(d, m)
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsFirewallRuleRead(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    id := d.Id()
+
+    log.Printf("[INFO][terraform-provider-windows] reading windows_firewall_rule %q\n", id)
+
+    // read
+    frQuery := new(api.FirewallRule)
+    frQuery.Name = d.Get("name").(string)
+
+    firewallRule, err := c.ReadFirewallRule(frQuery)
+    if err != nil {
+        if strings.Contains(err.Error(), "cannot find firewall_rule") {
+            log.Printf("[INFO][terraform-provider-windows] cannot find windows_firewall_rule %q\n", id)
+
+            // set id
+            d.SetId("")
+
+            log.Printf("[INFO][terraform-provider-windows] deleted windows_firewall_rule %q from terraform state\n", id)
+            return nil   // don't return an error to allow terraform refresh to update state
+        }
+
+        log.Printf("[ERROR][terraform-provider-windows] cannot read windows_firewall_rule %q\n", id)
+        return err
+    }
+
+    // set properties
+    setFirewallRuleProperties(d, firewallRule)
+
+    log.Printf("[INFO][terraform-provider-windows] read windows_firewall_rule %q\n", id)
+    return nil
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsFirewallRuleUpdate(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    id               := d.Id()
+    displayName      := d.Get("display_name").(string)
+    description      := d.Get("description").(string)
+    enabled          := d.Get("enabled").(bool)
+    direction        := d.Get("direction").(string)
+    action           := d.Get("action").(string)
+    profiles         := tfutil.GetSetOfStrings(d, "profiles")
+    protocol         := d.Get("protocol").(string)
+    localPorts       := tfutil.GetSetOfStrings(d, "local_ports")
+    remotePorts      := tfutil.GetSetOfStrings(d, "remote_ports")
+    localAddresses   := tfutil.GetSetOfStrings(d, "local_addresses")
+    remoteAddresses  := tfutil.GetSetOfStrings(d, "remote_addresses")
+    program          := d.Get("program").(string)
+    service          := d.Get("service").(string)
+    interfaceAliases := tfutil.GetSetOfStrings(d, "interface_aliases")
+
+    log.Printf(`[INFO][terraform-provider-windows] updating windows_firewall_rule %q
+                    [INFO][terraform-provider-windows]     display_name:      %#v
+                    [INFO][terraform-provider-windows]     description:       %#v
+                    [INFO][terraform-provider-windows]     enabled:           %#v
+                    [INFO][terraform-provider-windows]     direction:         %#v
+                    [INFO][terraform-provider-windows]     action:            %#v
+                    [INFO][terraform-provider-windows]     profiles:          %#v
+                    [INFO][terraform-provider-windows]     protocol:          %#v
+                    [INFO][terraform-provider-windows]     local_ports:       %#v
+                    [INFO][terraform-provider-windows]     remote_ports:      %#v
+                    [INFO][terraform-provider-windows]     local_addresses:   %#v
+                    [INFO][terraform-provider-windows]     remote_addresses:  %#v
+                    [INFO][terraform-provider-windows]     program:           %#v
+                    [INFO][terraform-provider-windows]     service:           %#v
+                    [INFO][terraform-provider-windows]     interface_aliases: %#v
+`       ,
+        id,
+        displayName,
+        description,
+        enabled,
+        direction,
+        action,
+        profiles,
+        protocol,
+        localPorts,
+        remotePorts,
+        localAddresses,
+        remoteAddresses,
+        program,
+        service,
+        interfaceAliases,
+    )
+
+    // update
+    frQuery := new(api.FirewallRule)
+    frQuery.Name = d.Get("name").(string)
+
+    frProperties := new(api.FirewallRule)
+    expandFirewallRuleProperties(frProperties, d)
+
+    err := c.UpdateFirewallRule(frQuery, frProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows] cannot update windows_firewall_rule %q\n", id)
+        return err
+    }
+
+    log.Printf("[INFO][terraform-provider-windows] updated windows_firewall_rule %q\n", id)
+    return resourceWindowsFirewallRuleRead(d, m)
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsFirewallRuleDelete(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    id := d.Id()
+
+    x_lifecycle := tfutil.GetResource(d, "x_lifecycle")
+
+    frQuery := new(api.FirewallRule)
+    frQuery.Name = d.Get("name").(string)
+
+    // lifecycle customizations: destroy_if_imported
+    imported, _          := x_lifecycle["imported"].(bool)
+    destroyIfImported, _ := x_lifecycle["destroy_if_imported"].(bool)
+    if imported && !destroyIfImported {
+        log.Printf("[INFO][terraform-provider-windows] deleting windows_firewall_rule %q from terraform state\n", id)
+        log.Printf("[INFO][terraform-provider-windows] restore original config for windows_firewall_rule %q\n", id)
+
+        // restore original config
+        frProperties := new(api.FirewallRule)
+        expandOriginalFirewallRuleProperties(frProperties, d)
+
+        err := c.UpdateFirewallRule(frQuery, frProperties)
+        if err != nil {
+            log.Printf("[WARNING][terraform-provider-windows] cannot restore original config for windows_firewall_rule %q\n", id)
+        }
+
+        // set id
+        d.SetId("")
+
+        log.Printf("[INFO][terraform-provider-windows] deleted windows_firewall_rule %q from terraform state\n", id)
+        return nil
+    }
+
+    log.Printf("[INFO][terraform-provider-windows] deleting windows_firewall_rule %q\n", id)
+
+    // delete
+    err := c.DeleteFirewallRule(frQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows] cannot delete windows_firewall_rule %q\n", id)
+        return err
+    }
+
+    // set id
+    d.SetId("")
+
+    log.Printf("[INFO][terraform-provider-windows] deleted windows_firewall_rule %q\n", id)
+    return nil
+}
+
+//------------------------------------------------------------------------------
+
+func setFirewallRuleProperties(d *schema.ResourceData, frProperties *api.FirewallRule) {
+    d.Set("name", frProperties.Name)
+    d.Set("display_name", frProperties.DisplayName)
+    d.Set("description", frProperties.Description)
+    d.Set("group", frProperties.Group)
+
+    d.Set("enabled", frProperties.Enabled)
+    d.Set("direction", frProperties.Direction)
+    d.Set("action", frProperties.Action)
+    d.Set("profiles", frProperties.Profiles)
+
+    d.Set("protocol", frProperties.Protocol)
+    d.Set("local_ports", frProperties.LocalPorts)
+    d.Set("remote_ports", frProperties.RemotePorts)
+    d.Set("local_addresses", frProperties.LocalAddresses)
+    d.Set("remote_addresses", frProperties.RemoteAddresses)
+
+    d.Set("program", frProperties.Program)
+    d.Set("service", frProperties.Service)
+    d.Set("interface_aliases", frProperties.InterfaceAliases)
+
+    d.Set("display_group", frProperties.DisplayGroup)
+}
+
+func setOriginalFirewallRuleProperties(d *schema.ResourceData, frProperties *api.FirewallRule) {
+    original := make(map[string]interface{})
+
+    original["display_name"]      = frProperties.DisplayName
+    original["description"]       = frProperties.Description
+    original["enabled"]           = frProperties.Enabled
+    original["direction"]         = frProperties.Direction
+    original["action"]            = frProperties.Action
+    original["profiles"]          = frProperties.Profiles
+    original["protocol"]          = frProperties.Protocol
+    original["local_ports"]       = frProperties.LocalPorts
+    original["remote_ports"]      = frProperties.RemotePorts
+    original["local_addresses"]   = frProperties.LocalAddresses
+    original["remote_addresses"]  = frProperties.RemoteAddresses
+    original["program"]           = frProperties.Program
+    original["service"]           = frProperties.Service
+    original["interface_aliases"] = frProperties.InterfaceAliases
+
+    d.Set("original", []interface{}{ original })
+}
+
+//------------------------------------------------------------------------------
+
+func expandFirewallRuleProperties(frProperties *api.FirewallRule, d *schema.ResourceData) {
+    frProperties.DisplayName      = d.Get("display_name").(string)
+    frProperties.Description      = d.Get("description").(string)
+    frProperties.Enabled          = d.Get("enabled").(bool)
+    frProperties.Direction        = d.Get("direction").(string)
+    frProperties.Action           = d.Get("action").(string)
+    frProperties.Profiles         = tfutil.GetSetOfStrings(d, "profiles")
+    frProperties.Protocol         = stateFirewallRuleProtocol()(d.Get("protocol"))
+    frProperties.LocalPorts       = tfutil.GetSetOfStrings(d, "local_ports")
+    frProperties.RemotePorts      = tfutil.GetSetOfStrings(d, "remote_ports")
+    frProperties.LocalAddresses   = tfutil.GetSetOfStrings(d, "local_addresses")
+    frProperties.RemoteAddresses  = tfutil.GetSetOfStrings(d, "remote_addresses")
+    frProperties.Program          = d.Get("program").(string)
+    frProperties.Service          = d.Get("service").(string)
+    frProperties.InterfaceAliases = tfutil.GetSetOfStrings(d, "interface_aliases")
+}
+
+func expandOriginalFirewallRuleProperties(frProperties *api.FirewallRule, d *schema.ResourceData) {
+    original := tfutil.GetResource(d, "original")
+
+    frProperties.DisplayName      = original["display_name"].(string)
+    frProperties.Description      = original["description"].(string)
+    frProperties.Enabled          = original["enabled"].(bool)
+    frProperties.Direction        = original["direction"].(string)
+    frProperties.Action           = original["action"].(string)
+    frProperties.Profiles         = tfutil.ExpandListOfStrings(original, "profiles")
+    frProperties.Protocol         = original["protocol"].(string)
+    frProperties.LocalPorts       = tfutil.ExpandListOfStrings(original, "local_ports")
+    frProperties.RemotePorts      = tfutil.ExpandListOfStrings(original, "remote_ports")
+    frProperties.LocalAddresses   = tfutil.ExpandListOfStrings(original, "local_addresses")
+    frProperties.RemoteAddresses  = tfutil.ExpandListOfStrings(original, "remote_addresses")
+    frProperties.Program          = original["program"].(string)
+    frProperties.Service          = original["service"].(string)
+    frProperties.InterfaceAliases = tfutil.ExpandListOfStrings(original, "interface_aliases")
+}
+
+//------------------------------------------------------------------------------
@@ -7,11 +7,18 @@
 package windows
 
 import (
+    "crypto/sha256"
+    "encoding/binary"
     "fmt"
     "log"
+    "net"
+    "strconv"
     "strings"
+    "time"
 
+    "github.com/hashicorp/terraform-plugin-sdk/helper/hashcode"
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+    "github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 
     "github.com/stefaanc/terraform-provider-windows/api"
     "github.com/stefaanc/terraform-provider-windows/windows/tfutil"
@@ -54,6 +61,11 @@ func resourceWindowsNetworkAdapter() *schema.Resource {
                 Computed: true,
             },
 
+            "allow_disconnect": &schema.Schema{
+                Type:     schema.TypeBool,
+                Optional: true,
+            },
+
             "mac_address": &schema.Schema{
                 Type:     schema.TypeString,
                 Optional: true,
@@ -61,14 +73,33 @@ func resourceWindowsNetworkAdapter() *schema.Resource {
 
                 ValidateFunc: tfutil.ValidateSingleMAC(),
                 StateFunc: tfutil.StateAll(
-                    tfutil.StateToUpper(),
+                    tfutil.StateToMAC(),
                     tfutil.StateAcceptEmptyString(),   // workaround for ?terraform bug?, replaces "" with "<empty>"
                 ),
+                DiffSuppressFunc: tfutil.DiffSuppressMAC(),
             },
             "permanent_mac_address": &schema.Schema{
                 Type:     schema.TypeString,
                 Computed: true,
             },
+            "generate_mac": &schema.Schema{
+                Type:     schema.TypeBool,
+                Optional: true,
+
+                ConflictsWith: []string{ "mac_address", "mac_address_pool" },
+            },
+            "mac_address_pool": &schema.Schema{
+                Type:     schema.TypeList,
+                MaxItems: 1,
+                Optional: true,
+                Elem: resourceWindowsNetworkAdapterMACAddressPool(),
+
+                ConflictsWith: []string{ "mac_address", "generate_mac" },
+            },
+            "allocated_mac_address": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
 
             "dns_client": &schema.Schema{
                 Type:     schema.TypeList,
@@ -78,9 +109,33 @@ func resourceWindowsNetworkAdapter() *schema.Resource {
                 Elem: resourceWindowsNetworkAdapterDNSClient(),
             },
 
+            "advanced_property": &schema.Schema{
+                Type:     schema.TypeSet,
+                Optional: true,
+                Elem: resourceWindowsNetworkAdapterAdvancedProperty(),
+                Set: resourceWindowsNetworkAdapterAdvancedPropertyHash,
+            },
+
             "admin_status": &schema.Schema{
                 Type:     schema.TypeString,
+                Optional: true,
                 Computed: true,
+
+                ValidateFunc: validation.StringInSlice([]string{ "Up", "Down" }, false),
+            },
+
+            "wait_for_connectivity": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+
+                ValidateFunc: validation.StringInSlice(tfutil.ConnectivityLevels[1:], true),
+            },
+            "wait_for_connectivity_timeout": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Default:  "5m",
+
+                ValidateFunc: tfutil.ValidateDuration(),
             },
             "operational_status": &schema.Schema{
                 Type:     schema.TypeString,
@@ -94,6 +149,10 @@ func resourceWindowsNetworkAdapter() *schema.Resource {
                 Type:     schema.TypeString,
                 Computed: true,
             },
+            "last_restart": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
             "is_physical": &schema.Schema{
                 Type:     schema.TypeBool,
                 Computed: true,
@@ -102,6 +161,9 @@ func resourceWindowsNetworkAdapter() *schema.Resource {
             // lifecycle customizations that are not supported by the 'lifecycle' meta-argument for persistent resources (similar to data-sources)
             "x_lifecycle": &tfutil.DataSourceXLifecycleSchema,
 
+            // schedules a rollback to the original values before an update, that is cancelled when the windows-computer can be verified after the update
+            "rollback_protection": &tfutil.RollbackProtectionSchema,
+
             // used to reset values on terraform destroy
             "original": &schema.Schema{
                 Type:     schema.TypeList,
@@ -138,10 +200,126 @@ func resourceWindowsNetworkAdapterDNSClient() *schema.Resource {
                     tfutil.StateAcceptEmptyString(),   // workaround for ?terraform bug?, replaces "" with "<empty>"
                 ),
             },
+            "server_addresses": &schema.Schema{
+                Type:     schema.TypeList,
+                Optional: true,
+                Computed: true,
+                Elem: &schema.Schema{
+                    Type: schema.TypeString,
+
+                    ValidateFunc: validation.SingleIP(),
+                },
+
+                DiffSuppressFunc: resourceWindowsNetworkAdapterServerAddressesDiffSuppress,
+            },
+            "reset_to_dhcp": &schema.Schema{
+                Type:     schema.TypeBool,
+                Optional: true,
+                Default:  false,
+
+                ConflictsWith: []string{ "dns_client.0.server_addresses" },
+            },
         },
     }
 }
 
+func resourceWindowsNetworkAdapterMACAddressPool() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "prefix": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+
+                ConflictsWith: []string{ "mac_address_pool.0.first", "mac_address_pool.0.last" },
+                ValidateFunc: tfutil.ValidateMACPrefix(),
+                StateFunc: tfutil.StateToMACPrefix(),
+                DiffSuppressFunc: tfutil.DiffSuppressMACPrefix(),
+            },
+            "first": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+
+                ValidateFunc: tfutil.ValidateSingleMAC(),
+                StateFunc: tfutil.StateToMAC(),
+                DiffSuppressFunc: tfutil.DiffSuppressMAC(),
+            },
+            "last": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+
+                ValidateFunc: tfutil.ValidateSingleMAC(),
+                StateFunc: tfutil.StateToMAC(),
+                DiffSuppressFunc: tfutil.DiffSuppressMAC(),
+            },
+        },
+    }
+}
+
+func resourceWindowsNetworkAdapterAdvancedProperty() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "registry_keyword": &schema.Schema{
+                Type:     schema.TypeString,
+                Required: true,
+            },
+            "registry_value": &schema.Schema{
+                Type:     schema.TypeString,
+                Required: true,
+            },
+            "display_name": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "display_value": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+        },
+    }
+}
+
+// only hash the configurable attributes, the computed display attributes are unknown in config
+func resourceWindowsNetworkAdapterAdvancedPropertyHash(v interface{}) int {
+    m := v.(map[string]interface{})
+    return hashcode.String(fmt.Sprintf("%s=%s", m["registry_keyword"].(string), m["registry_value"].(string)))
+}
+
+// the static DNS server addresses are read back with IPv4 before IPv6, so don't show a diff when only the order of the address families is different
+func resourceWindowsNetworkAdapterServerAddressesDiffSuppress(k, old, new string, d *schema.ResourceData) bool {
+    o, n := d.GetChange("dns_client.0.server_addresses")
+    oldServerAddresses := make([]string, 0)
+    for _, v := range o.([]interface{}) {
+        oldServerAddresses = append(oldServerAddresses, v.(string))
+    }
+    newServerAddresses := make([]string, 0)
+    for _, v := range n.([]interface{}) {
+        newServerAddresses = append(newServerAddresses, v.(string))
+    }
+
+    return ( len(newServerAddresses) > 0 ) &&
+           ( strings.Join(sortServerAddresses(oldServerAddresses), ",") == strings.Join(sortServerAddresses(newServerAddresses), ",") )
+}
+
+// sortServerAddresses returns the DNS server addresses with IPv4 before IPv6, keeping the order of preference within each address family
+func sortServerAddresses(serverAddresses []string) []string {
+    sorted := make([]string, 0, len(serverAddresses))
+    for _, ipv4 := range []bool{ true, false } {
+        for _, serverAddress := range serverAddresses {
+            ip := net.ParseIP(serverAddress)
+            if ip == nil {
+                if !ipv4 {
+                    sorted = append(sorted, serverAddress)
+                }
+                continue
+            }
+            if ( ip.To4() != nil ) == ipv4 {
+                sorted = append(sorted, ip.String())
+            }
+        }
+    }
+    return sorted
+}
+
 func resourceWindowsNetworkAdapterOriginal() *schema.Resource {
     return &schema.Resource{
         Schema: map[string]*schema.Schema{
@@ -155,6 +333,11 @@ func resourceWindowsNetworkAdapterOriginal() *schema.Resource {
                 Computed: true,
             },
 
+            "admin_status": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+
             "dns_client": &schema.Schema{
                 Type:     schema.TypeList,
                 MaxItems: 1,
@@ -169,6 +352,34 @@ func resourceWindowsNetworkAdapterOriginal() *schema.Resource {
                             Type:     schema.TypeString,
                             Computed: true,
                         },
+                        "server_addresses": &schema.Schema{
+                            Type:     schema.TypeList,
+                            Computed: true,
+                            Elem: &schema.Schema{
+                                Type: schema.TypeString,
+                            },
+                        },
+                        "reset_to_dhcp": &schema.Schema{
+                            Type:     schema.TypeBool,
+                            Computed: true,
+                        },
+                    },
+                },
+            },
+
+            "advanced_property": &schema.Schema{
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem: &schema.Resource{
+                    Schema: map[string]*schema.Schema{
+                        "registry_keyword": &schema.Schema{
+                            Type:     schema.TypeString,
+                            Computed: true,
+                        },
+                        "registry_value": &schema.Schema{
+                            Type:     schema.TypeString,
+                            Computed: true,
+                        },
                     },
                 },
             },
@@ -192,6 +403,132 @@ func resourceWindowsNetworkAdapterCustomizeDiff(d *schema.ResourceDiff, m interf
         }
     }
 
+    // changing advanced properties restarts the network adapter
+    if d.HasChange("admin_status") || d.HasChange("advanced_property") {
+        d.SetNewComputed("operational_status")
+        d.SetNewComputed("connection_status")
+        d.SetNewComputed("connection_speed")
+    }
+    if d.HasChange("advanced_property") {
+        // show the restart in the plan, the time of the restart is set by the apply
+        d.SetNewComputed("last_restart")
+    }
+
+    // the mac address is allocated during apply when 'generate_mac' or 'mac_address_pool' is set
+    generateMAC := d.Get("generate_mac").(bool) || ( len(d.Get("mac_address_pool").([]interface{})) > 0 )
+    if ( d.Id() == "" ) || d.HasChange("generate_mac") || d.HasChange("mac_address_pool") {
+        if generateMAC {
+            if l := d.Get("mac_address_pool").([]interface{}); ( len(l) > 0 ) && d.NewValueKnown("mac_address_pool") {
+                macAddressPool, _ := l[0].(map[string]interface{})
+                if macAddressPool == nil {
+                    macAddressPool = map[string]interface{}{ "prefix": "" }   // empty block
+                }
+                _, _, err := expandNetworkAdapterMACAddressPool(macAddressPool)
+                if err != nil {
+                    return err
+                }
+            }
+
+            d.SetNewComputed("mac_address")
+        }
+        d.SetNewComputed("allocated_mac_address")
+    } else if v := d.Get("allocated_mac_address").(string); generateMAC && ( v != "" ) && ( d.Get("mac_address").(string) != v ) {
+        // restore the allocated mac address when it was changed outside terraform
+        d.SetNew("mac_address", v)
+    }
+
+    // refuse changes that could cut the ssh-connection of the provider, unless 'allow_disconnect' is set
+    if !d.Get("allow_disconnect").(bool) {
+        err := customizeDiffNetworkAdapterManagementConnection(d, m.(*api.WindowsClient))
+        if err != nil {
+            return err
+        }
+    }
+
+    return nil
+}
+
+func customizeDiffNetworkAdapterManagementConnection(d *schema.ResourceDiff, c *api.WindowsClient) error {
+    isNew := ( d.Id() == "" )
+
+    // only consider the changes that could cut the connection
+    var macAddress string
+    if isNew || d.HasChange("mac_address") {
+        macAddress = tfutil.NormalizeMAC(d.Get("mac_address").(string))
+    }
+    var generateMAC bool
+    if isNew || d.HasChange("generate_mac") || d.HasChange("mac_address_pool") {
+        generateMAC = d.Get("generate_mac").(bool) || ( len(d.Get("mac_address_pool").([]interface{})) > 0 )
+    }
+    var adminStatus string
+    if isNew || d.HasChange("admin_status") {
+        adminStatus = d.Get("admin_status").(string)
+    }
+    var advancedProperties []interface{}
+    if isNew || d.HasChange("advanced_property") {
+        advancedProperties = d.Get("advanced_property").(*schema.Set).List()
+    }
+    if ( macAddress == "" ) && !generateMAC && ( adminStatus != "Down" ) && ( len(advancedProperties) == 0 ) {
+        return nil
+    }
+
+    managementConnection, err := c.ReadManagementConnection()
+    if err != nil {
+        return err
+    }
+    if len(managementConnection.NetworkAdapterNames) == 0 {
+        return nil
+    }
+
+    naQuery := new(api.NetworkAdapter)
+    naQuery.GUID    = d.Get("guid").(string)
+    naQuery.Name    = d.Get("name").(string)
+    naQuery.OldName = d.Get("old_name").(string)
+
+    networkAdapter, err := c.ReadNetworkAdapter(naQuery)
+    if err != nil {
+        return nil   // leave it to create/update to handle a missing network adapter
+    }
+
+    isManagement := false
+    for _, name := range managementConnection.NetworkAdapterNames {
+        if name == networkAdapter.Name {
+            isManagement = true
+        }
+    }
+    if !isManagement {
+        return nil
+    }
+
+    var changes []string
+    if ( adminStatus == "Down" ) && ( networkAdapter.AdminStatus != "Down" ) {
+        changes = append(changes, "disable network adapter")
+    }
+    if ( ( macAddress == "<empty>" ) && ( networkAdapter.MACAddress != networkAdapter.PermanentMACAddress ) ) ||
+       ( ( macAddress != "<empty>" ) && ( macAddress != "" ) && !strings.EqualFold(networkAdapter.MACAddress, macAddress) ) {
+        changes = append(changes, "change 'mac_address'")
+    }
+    if generateMAC {
+        changes = append(changes, "generate 'mac_address'")
+    }
+    for _, ap := range advancedProperties {
+        registryKeyword := ap.(map[string]interface{})["registry_keyword"].(string)
+        registryValue   := ap.(map[string]interface{})["registry_value"].(string)
+        changed := true
+        for _, apProperties := range networkAdapter.AdvancedProperties {
+            if ( apProperties.RegistryKeyword == registryKeyword ) && ( apProperties.RegistryValue == registryValue ) {
+                changed = false
+            }
+        }
+        if changed {
+            changes = append(changes, "change 'advanced_property' (restarts network adapter)")
+            break
+        }
+    }
+    if len(changes) > 0 {
+        return fmt.Errorf("[ERROR][terraform-provider-windows] cannot %s for windows_network_adapter %q, network adapter carries the ssh-connection of the provider - set 'allow_disconnect' to force the change", strings.Join(changes, ", "), networkAdapter.Name)
+    }
+
     return nil
 }
 
@@ -205,6 +542,7 @@ func resourceWindowsNetworkAdapterCreate(d *schema.ResourceData, m interface{})
     oldName         := d.Get("old_name").(string)
     newName         := d.Get("new_name").(string)
     macAddress      := d.Get("mac_address").(string)
+    adminStatus     := d.Get("admin_status").(string)
     dnsClient       := tfutil.GetResource(d, "dns_client")
 
     host := "localhost"
@@ -219,14 +557,17 @@ func resourceWindowsNetworkAdapterCreate(d *schema.ResourceData, m interface{})
     id = fmt.Sprintf("//%s/network_adapters/%s", host, id)
 
     log.Printf(`[INFO][terraform-provider-windows] creating windows_network_adapter %q
-                    [INFO][terraform-provider-windows]     guid:        %#v
-                    [INFO][terraform-provider-windows]     name:        %#v
-                    [INFO][terraform-provider-windows]     old_name:    %#v
-                    [INFO][terraform-provider-windows]     new_name:    %#v
-                    [INFO][terraform-provider-windows]     mac_address: %#v
+                    [INFO][terraform-provider-windows]     guid:         %#v
+                    [INFO][terraform-provider-windows]     name:         %#v
+                    [INFO][terraform-provider-windows]     old_name:     %#v
+                    [INFO][terraform-provider-windows]     new_name:     %#v
+                    [INFO][terraform-provider-windows]     mac_address:  %#v
+                    [INFO][terraform-provider-windows]     admin_status: %#v
                     [INFO][terraform-provider-windows]     dns_client {
                     [INFO][terraform-provider-windows]         register_connection_address: %#v
                     [INFO][terraform-provider-windows]         register_connection_suffix:  %#v
+                    [INFO][terraform-provider-windows]         server_addresses:            %#v
+                    [INFO][terraform-provider-windows]         reset_to_dhcp:               %#v
                     [INFO][terraform-provider-windows]     }
 `       ,
         id,
@@ -235,8 +576,11 @@ func resourceWindowsNetworkAdapterCreate(d *schema.ResourceData, m interface{})
         oldName,
         newName,
         macAddress,
+        adminStatus,
         dnsClient["register_connection_address"],
         dnsClient["register_connection_suffix"],
+        dnsClient["server_addresses"],
+        dnsClient["reset_to_dhcp"],
     )
 
     // import
@@ -249,7 +593,12 @@ func resourceWindowsNetworkAdapterCreate(d *schema.ResourceData, m interface{})
     naQuery.Name    = name
     naQuery.OldName = oldName
 
-    networkAdapter, err := c.ReadNetworkAdapter(naQuery)
+    // lifecycle customizations: wait_until_exists
+    var networkAdapter *api.NetworkAdapter
+    err := tfutil.WaitUntilExists(x_lifecycle, "cannot find network_adapter", func() (err error) {
+        networkAdapter, err = c.ReadNetworkAdapter(naQuery)
+        return err
+    })
     if err != nil {
         // lifecycle customizations: ignore_error_if_not_exists
         v, ok := x_lifecycle["ignore_error_if_not_exists"]
@@ -263,11 +612,14 @@ func resourceWindowsNetworkAdapterCreate(d *schema.ResourceData, m interface{})
             d.Set("new_name", "")
             d.Set("mac_address", "")
             d.Set("permanent_mac_address", "")
+            d.Set("allocated_mac_address", "")
             d.Set("dns_client", nil)
+            d.Set("advanced_property", nil)
             d.Set("admin_status", "")
             d.Set("operational_status", "")
             d.Set("connection_status", "")
             d.Set("connection_speed", "")
+            d.Set("last_restart", "")
             d.Set("is_physical", false)
 
             // set computed lifecycle properties
@@ -293,6 +645,13 @@ func resourceWindowsNetworkAdapterCreate(d *schema.ResourceData, m interface{})
     // save original config
     setOriginalNetworkAdapterProperties(d, networkAdapter)
 
+    // allocate mac address
+    err = allocateNetworkAdapterMACAddress(c, d, networkAdapter)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows] cannot allocate mac address for windows_network_adapter %q\n", id)
+        return err
+    }
+
     // check diff
     if !diffNetworkAdapterProperties(d, networkAdapter) {
         // no update required
@@ -316,15 +675,22 @@ func resourceWindowsNetworkAdapterCreate(d *schema.ResourceData, m interface{})
         naProperties := new(api.NetworkAdapter)
         expandNetworkAdapterProperties(naProperties, d)
 
-        err := c.UpdateNetworkAdapter(networkAdapter, naProperties)
+        err := updateNetworkAdapterWithRollbackProtection(c, d, networkAdapter, naProperties)
         if err != nil {
             log.Printf("[ERROR][terraform-provider-windows] cannot update windows_network_adapter %q\n", id)
             return err
         }
+        setNetworkAdapterLastRestart(d)
 
         // set id
         d.SetId(id)
 
+        err = waitForNetworkAdapterConnectivity(c, d)
+        if err != nil {
+            log.Printf("[ERROR][terraform-provider-windows] cannot reach connectivity for windows_network_adapter %q\n", id)
+            return err
+        }
+
         log.Printf("[INFO][terraform-provider-windows] created windows_network_adapter %q\n", id)
         return resourceWindowsNetworkAdapterRead(d, m)
     }
@@ -361,11 +727,14 @@ func resourceWindowsNetworkAdapterRead(d *schema.ResourceData, m interface{}) er
             d.Set("new_name", "")
             d.Set("mac_address", "")
             d.Set("permanent_mac_address", "")
+            d.Set("allocated_mac_address", "")
             d.Set("dns_client", nil)
+            d.Set("advanced_property", nil)
             d.Set("admin_status", "")
             d.Set("operational_status", "")
             d.Set("connection_status", "")
             d.Set("connection_speed", "")
+            d.Set("last_restart", "")
             d.Set("is_physical", false)
 
             // set computed lifecycle properties
@@ -407,18 +776,22 @@ func resourceWindowsNetworkAdapterUpdate(d *schema.ResourceData, m interface{})
     name       := d.Get("name").(string)
     oldName    := d.Get("old_name").(string)
     newName    := d.Get("new_name").(string)
-    macAddress := d.Get("mac_address").(string)
-    dnsClient  := tfutil.GetResource(d, "dns_client")
+    macAddress  := d.Get("mac_address").(string)
+    adminStatus := d.Get("admin_status").(string)
+    dnsClient   := tfutil.GetResource(d, "dns_client")
 
     log.Printf(`[INFO][terraform-provider-windows] updating windows_network_adapter %q
-                    [INFO][terraform-provider-windows]     guid:        %#v
-                    [INFO][terraform-provider-windows]     name:        %#v
-                    [INFO][terraform-provider-windows]     old_name:    %#v
-                    [INFO][terraform-provider-windows]     new_name:    %#v
-                    [INFO][terraform-provider-windows]     mac_address: %#v
+                    [INFO][terraform-provider-windows]     guid:         %#v
+                    [INFO][terraform-provider-windows]     name:         %#v
+                    [INFO][terraform-provider-windows]     old_name:     %#v
+                    [INFO][terraform-provider-windows]     new_name:     %#v
+                    [INFO][terraform-provider-windows]     mac_address:  %#v
+                    [INFO][terraform-provider-windows]     admin_status: %#v
                     [INFO][terraform-provider-windows]     dns_client {
                     [INFO][terraform-provider-windows]         register_connection_address: %#v
                     [INFO][terraform-provider-windows]         register_connection_suffix:  %#v
+                    [INFO][terraform-provider-windows]         server_addresses:            %#v
+                    [INFO][terraform-provider-windows]         reset_to_dhcp:               %#v
                     [INFO][terraform-provider-windows]     }
 `       ,
         id,
@@ -427,22 +800,58 @@ func resourceWindowsNetworkAdapterUpdate(d *schema.ResourceData, m interface{})
         oldName,
         newName,
         macAddress,
+        adminStatus,
         dnsClient["register_connection_address"],
         dnsClient["register_connection_suffix"],
+        dnsClient["server_addresses"],
+        dnsClient["reset_to_dhcp"],
     )
 
     // update
     naQuery := new(api.NetworkAdapter)
     naQuery.GUID = guid
 
+    if d.HasChange("advanced_property") {
+        // save original config for advanced properties that are newly added to config
+        networkAdapter, err := c.ReadNetworkAdapter(naQuery)
+        if err != nil {
+            log.Printf("[ERROR][terraform-provider-windows] cannot read windows_network_adapter %q\n", id)
+            return err
+        }
+        addOriginalNetworkAdapterAdvancedProperties(d, networkAdapter)
+
+        log.Printf("[WARNING][terraform-provider-windows] changing advanced properties restarts windows_network_adapter %q\n", id)
+    }
+
+    if d.HasChange("generate_mac") || d.HasChange("mac_address_pool") {
+        networkAdapter, err := c.ReadNetworkAdapter(naQuery)
+        if err != nil {
+            log.Printf("[ERROR][terraform-provider-windows] cannot read windows_network_adapter %q\n", id)
+            return err
+        }
+
+        err = allocateNetworkAdapterMACAddress(c, d, networkAdapter)
+        if err != nil {
+            log.Printf("[ERROR][terraform-provider-windows] cannot allocate mac address for windows_network_adapter %q\n", id)
+            return err
+        }
+    }
+
     naProperties := new(api.NetworkAdapter)
     expandNetworkAdapterProperties(naProperties, d)
 
-    err := c.UpdateNetworkAdapter(naQuery, naProperties)
+    err := updateNetworkAdapterWithRollbackProtection(c, d, naQuery, naProperties)
     if err != nil {
         log.Printf("[ERROR][terraform-provider-windows] cannot update windows_network_adapter %q\n", id)
         return err
     }
+    setNetworkAdapterLastRestart(d)
+
+    err = waitForNetworkAdapterConnectivity(c, d)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows] cannot reach connectivity for windows_network_adapter %q\n", id)
+        return err
+    }
 
     log.Printf("[INFO][terraform-provider-windows] updated windows_network_adapter %q\n", id)
     return resourceWindowsNetworkAdapterRead(d, m)
@@ -479,6 +888,185 @@ func resourceWindowsNetworkAdapterDelete(d *schema.ResourceData, m interface{})
 
 //------------------------------------------------------------------------------
 
+func updateNetworkAdapterWithRollbackProtection(c *api.WindowsClient, d *schema.ResourceData, naQuery *api.NetworkAdapter, naProperties *api.NetworkAdapter) error {
+    rbQuery := new(api.NetworkAdapter)
+    rbQuery.GUID = naQuery.GUID
+
+    rollbackProtection := tfutil.GetResource(d, "rollback_protection")
+    return tfutil.WithRollbackProtection(rollbackProtection,
+        func(window time.Duration) error {
+            naOriginal := new(api.NetworkAdapter)
+            expandOriginalNetworkAdapterProperties(naOriginal, d)
+            return c.CreateNetworkAdapterRollback(rbQuery, naOriginal, uint32(window.Seconds()))
+        },
+        func() error {
+            return c.UpdateNetworkAdapter(naQuery, naProperties)
+        },
+        func() error {
+            // reconnects to the windows-computer
+            _, err := c.ReadNetworkAdapter(rbQuery)
+            return err
+        },
+        func() error {
+            return c.DeleteNetworkAdapterRollback(rbQuery)
+        },
+    )
+}
+
+//------------------------------------------------------------------------------
+
+func waitForNetworkAdapterConnectivity(c *api.WindowsClient, d *schema.ResourceData) error {
+    connectivity := d.Get("wait_for_connectivity").(string)
+    timeout, _   := time.ParseDuration(d.Get("wait_for_connectivity_timeout").(string))
+
+    // the network interface has the same GUID as the network adapter
+    niQuery := new(api.NetworkInterface)
+    niQuery.GUID = d.Get("guid").(string)
+
+    return tfutil.WaitForConnectivity(connectivity, timeout, func() (ipv4Connectivity string, ipv6Connectivity string, err error) {
+        networkInterface, err := c.ReadNetworkInterface(niQuery)
+        if err != nil {
+            return "", "", err
+        }
+
+        // use the best connectivity of the network connections for the network adapter
+        for _, name := range networkInterface.NetworkConnectionNames {
+            ncQuery := new(api.NetworkConnection)
+            ncQuery.Name = name
+
+            networkConnection, err := c.ReadNetworkConnection(ncQuery)
+            if err != nil {
+                return "", "", err
+            }
+            ipv4Connectivity = tfutil.MaxConnectivity(ipv4Connectivity, networkConnection.IPv4Connectivity)
+            ipv6Connectivity = tfutil.MaxConnectivity(ipv6Connectivity, networkConnection.IPv6Connectivity)
+        }
+        return ipv4Connectivity, ipv6Connectivity, nil
+    })
+}
+
+//------------------------------------------------------------------------------
+
+func allocateNetworkAdapterMACAddress(c *api.WindowsClient, d *schema.ResourceData, networkAdapter *api.NetworkAdapter) error {
+    macAddressPool := tfutil.GetResource(d, "mac_address_pool")
+    if !d.Get("generate_mac").(bool) && ( len(macAddressPool) == 0 ) {
+        d.Set("allocated_mac_address", "")
+        return nil
+    }
+
+    first, last, err := expandNetworkAdapterMACAddressPool(macAddressPool)
+    if err != nil {
+        return err
+    }
+
+    // collect the mac addresses that are used by other network adapters
+    machineGUID, macAddresses, err := c.ReadNetworkAdapterMACAddresses()
+    if err != nil {
+        return err
+    }
+    used := make(map[string]bool)
+    for _, macAddress := range macAddresses {
+        if !strings.EqualFold(macAddress, networkAdapter.MACAddress) {
+            used[strings.ToUpper(macAddress)] = true
+        }
+    }
+
+    // derive the mac address from the machine guid of the host and the network adapter, so it is repeatable across re-creates
+    seed := fmt.Sprintf("%s/%s", strings.ToUpper(machineGUID), strings.ToUpper(networkAdapter.GUID))
+
+    var macAddress string
+    if len(macAddressPool) == 0 {
+        // locally-administered unicast mac address, rehash on collision
+        for i := 0; i <= len(used); i++ {
+            sum := sha256.Sum256([]byte(fmt.Sprintf("%s/%d", seed, i)))
+            sum[0] = ( sum[0] | 0x02 ) &^ 0x01
+            candidate := formatNetworkAdapterMACAddress(binary.BigEndian.Uint64(append([]byte{ 0, 0 }, sum[:6]...)))
+            if !used[candidate] {
+                macAddress = candidate
+                break
+            }
+        }
+    } else {
+        // mac address in the pool, take the next one on collision
+        sum := sha256.Sum256([]byte(seed))
+        size := last - first + 1
+        offset := binary.BigEndian.Uint64(sum[:8]) % size
+        for i := uint64(0); ( i < size ) && ( i <= uint64(len(used)) ); i++ {
+            candidate := formatNetworkAdapterMACAddress(first + ( offset + i ) % size)
+            if !used[candidate] {
+                macAddress = candidate
+                break
+            }
+        }
+    }
+    if macAddress == "" {
+        return fmt.Errorf("[ERROR][terraform-provider-windows] cannot allocate mac address for network adapter %q, all mac addresses are in use", networkAdapter.Name)
+    }
+
+    log.Printf("[INFO][terraform-provider-windows] allocated mac address %q for network adapter %q\n", macAddress, networkAdapter.Name)
+
+    d.Set("allocated_mac_address", macAddress)
+    d.Set("mac_address", macAddress)
+
+    return nil
+}
+
+func expandNetworkAdapterMACAddressPool(macAddressPool map[string]interface{}) (first uint64, last uint64, err error) {
+    if len(macAddressPool) == 0 {
+        return 0, 0, nil
+    }
+
+    prefix, _ := macAddressPool["prefix"].(string)
+    firstMAC, _ := macAddressPool["first"].(string)
+    lastMAC, _ := macAddressPool["last"].(string)
+
+    if prefix != "" {
+        prefix = tfutil.NormalizeMACPrefix(prefix)
+
+        p := strings.Replace(prefix, "-", "", -1)
+        bits := uint(8 * ( 6 - len(p) / 2 ))
+        v, err := strconv.ParseUint(p, 16, 64)
+        if err != nil {
+            return 0, 0, fmt.Errorf("[ERROR][terraform-provider-windows] invalid 'mac_address_pool.0.prefix' %q", prefix)
+        }
+        first = v << bits
+        last  = first | ( ( 1 << bits ) - 1 )
+    } else if ( firstMAC != "" ) && ( lastMAC != "" ) {
+        firstMAC = tfutil.NormalizeMAC(firstMAC)
+        lastMAC  = tfutil.NormalizeMAC(lastMAC)
+
+        first, err = strconv.ParseUint(strings.Replace(firstMAC, "-", "", -1), 16, 64)
+        if err != nil {
+            return 0, 0, fmt.Errorf("[ERROR][terraform-provider-windows] invalid 'mac_address_pool.0.first' %q", firstMAC)
+        }
+        last, err = strconv.ParseUint(strings.Replace(lastMAC, "-", "", -1), 16, 64)
+        if err != nil {
+            return 0, 0, fmt.Errorf("[ERROR][terraform-provider-windows] invalid 'mac_address_pool.0.last' %q", lastMAC)
+        }
+        if first > last {
+            return 0, 0, fmt.Errorf("[ERROR][terraform-provider-windows] 'mac_address_pool.0.first' %q is higher than 'mac_address_pool.0.last' %q", firstMAC, lastMAC)
+        }
+    } else {
+        return 0, 0, fmt.Errorf("[ERROR][terraform-provider-windows] missing 'mac_address_pool.0.prefix' or 'mac_address_pool.0.first' and 'mac_address_pool.0.last'")
+    }
+
+    // a pool within a single first octet never needs to skip multicast mac addresses
+    if ( first >> 40 ) != ( last >> 40 ) {
+        return 0, 0, fmt.Errorf("[ERROR][terraform-provider-windows] 'mac_address_pool' must not span multiple first octets")
+    }
+    if ( ( first >> 40 ) & 0x01 ) != 0 {
+        return 0, 0, fmt.Errorf("[ERROR][terraform-provider-windows] 'mac_address_pool' must contain unicast mac addresses")
+    }
+
+    return first, last, nil
+}
+
+func formatNetworkAdapterMACAddress(v uint64) string {
+    return fmt.Sprintf("%02X-%02X-%02X-%02X-%02X-%02X", byte(v >> 40), byte(v >> 32), byte(v >> 24), byte(v >> 16), byte(v >> 8), byte(v))
+}
+
+//------------------------------------------------------------------------------
+
 func setNetworkAdapterProperties(d *schema.ResourceData, naProperties *api.NetworkAdapter) {
     d.Set("guid", naProperties.GUID)
 
@@ -498,11 +1086,30 @@ func setNetworkAdapterProperties(d *schema.ResourceData, naProperties *api.Netwo
         } else {
             dnsClient["register_connection_suffix"] = naProperties.DNSClient[0].RegisterConnectionSuffix
         }
+        dnsClient["server_addresses"] = naProperties.DNSClient[0].ServerAddresses
+        // 'reset_to_dhcp' is only kept when it is in config, and is cleared when static DNS servers were set outside of terraform
+        dnsClient["reset_to_dhcp"] = d.Get("dns_client.0.reset_to_dhcp").(bool) && naProperties.DNSClient[0].ResetToDHCP
         d.Set("dns_client", []interface{}{ dnsClient })
     } else {
         d.Set("dns_client", []interface{}{ })
     }
 
+    // only keep the advanced properties that are in config
+    advancedProperties := make([]interface{}, 0)
+    for _, ap := range tfutil.GetSetOfResources(d, "advanced_property") {
+        for _, apProperties := range naProperties.AdvancedProperties {
+            if apProperties.RegistryKeyword == ap["registry_keyword"].(string) {
+                advancedProperty := make(map[string]interface{})
+                advancedProperty["registry_keyword"] = apProperties.RegistryKeyword
+                advancedProperty["registry_value"]   = apProperties.RegistryValue
+                advancedProperty["display_name"]     = apProperties.DisplayName
+                advancedProperty["display_value"]    = apProperties.DisplayValue
+                advancedProperties = append(advancedProperties, advancedProperty)
+            }
+        }
+    }
+    d.Set("advanced_property", advancedProperties)
+
     d.Set("admin_status", naProperties.AdminStatus)
     d.Set("operational_status", naProperties.OperationalStatus)
     d.Set("connection_status", naProperties.ConnectionStatus)
@@ -510,22 +1117,72 @@ func setNetworkAdapterProperties(d *schema.ResourceData, naProperties *api.Netwo
     d.Set("is_physical", naProperties.IsPhysical)
 }
 
+// setNetworkAdapterLastRestart sets the time of the restart that is caused by changing the advanced properties
+func setNetworkAdapterLastRestart(d *schema.ResourceData) {
+    if d.HasChange("advanced_property") {
+        d.Set("last_restart", time.Now().UTC().Format(time.RFC3339))
+    }
+}
+
 func setOriginalNetworkAdapterProperties(d *schema.ResourceData, naProperties *api.NetworkAdapter) {
     original := make(map[string]interface{})
 
     original["old_name"]    = naProperties.Name
     original["mac_address"] = naProperties.MACAddress
 
+    original["admin_status"] = naProperties.AdminStatus
+
     if len(naProperties.DNSClient) > 0 {
         original_dnsClient := make(map[string]interface{})
         original_dnsClient["register_connection_address"] = naProperties.DNSClient[0].RegisterConnectionAddress
         original_dnsClient["register_connection_suffix"]  = naProperties.DNSClient[0].RegisterConnectionSuffix
+        original_dnsClient["server_addresses"]            = naProperties.DNSClient[0].ServerAddresses
+        original_dnsClient["reset_to_dhcp"]               = naProperties.DNSClient[0].ResetToDHCP
         original["dns_client"] = []interface{}{ original_dnsClient }
     } else {
         original["dns_client"] = []interface{}{ }
     }
 
+    original["advanced_property"] = []interface{}{ }
+
     d.Set("original", []interface{}{ original })
+
+    addOriginalNetworkAdapterAdvancedProperties(d, naProperties)
+}
+
+func addOriginalNetworkAdapterAdvancedProperties(d *schema.ResourceData, naProperties *api.NetworkAdapter) {
+    original := tfutil.GetResource(d, "original")
+
+    original_advancedProperties := make([]interface{}, 0)
+    for _, original_ap := range tfutil.ExpandListOfResources(original, "advanced_property") {
+        original_advancedProperties = append(original_advancedProperties, original_ap)
+    }
+
+    for _, ap := range tfutil.GetSetOfResources(d, "advanced_property") {
+        if findOriginalNetworkAdapterAdvancedProperty(original, ap["registry_keyword"].(string)) != nil {
+            continue
+        }
+        for _, apProperties := range naProperties.AdvancedProperties {
+            if apProperties.RegistryKeyword == ap["registry_keyword"].(string) {
+                original_ap := make(map[string]interface{})
+                original_ap["registry_keyword"] = apProperties.RegistryKeyword
+                original_ap["registry_value"]   = apProperties.RegistryValue
+                original_advancedProperties = append(original_advancedProperties, original_ap)
+            }
+        }
+    }
+
+    original["advanced_property"] = original_advancedProperties
+    d.Set("original", []interface{}{ original })
+}
+
+func findOriginalNetworkAdapterAdvancedProperty(original map[string]interface{}, registryKeyword string) map[string]interface{} {
+    for _, original_ap := range tfutil.ExpandListOfResources(original, "advanced_property") {
+        if original_ap["registry_keyword"].(string) == registryKeyword {
+            return original_ap
+        }
+    }
+    return nil
 }
 
 //------------------------------------------------------------------------------
@@ -535,10 +1192,29 @@ func diffNetworkAdapterProperties(d *schema.ResourceData, naProperties *api.Netw
         return true
     }
 
-    if v, ok := d.GetOk("mac_address"); ok && ( naProperties.MACAddress != v.(string) ) {
+    if v, ok := d.GetOk("mac_address"); ok && ( naProperties.MACAddress != tfutil.NormalizeMAC(v.(string)) ) {
         return true
     }
 
+    if v, ok := d.GetOk("admin_status"); ok && ( naProperties.AdminStatus != v.(string) ) {
+        return true
+    }
+
+    for _, ap := range tfutil.GetSetOfResources(d, "advanced_property") {
+        found := false
+        for _, apProperties := range naProperties.AdvancedProperties {
+            if apProperties.RegistryKeyword == ap["registry_keyword"].(string) {
+                found = true
+                if apProperties.RegistryValue != ap["registry_value"].(string) {
+                    return true
+                }
+            }
+        }
+        if !found {
+            return true   // let the update throw an error
+        }
+    }
+
     if v, ok := d.GetOk("dns_client"); ok && ( len(v.([]interface{})) > 0 ) {
         if len(naProperties.DNSClient) > 0 {
             if v, ok := d.GetOkExists("dns_client.0.register_connection_address"); ok && ( naProperties.DNSClient[0].RegisterConnectionAddress != v.(bool) ) {
@@ -549,6 +1225,13 @@ func diffNetworkAdapterProperties(d *schema.ResourceData, naProperties *api.Netw
                  ( ( v.(string) == "<empty>" ) && ( naProperties.DNSClient[0].RegisterConnectionSuffix != ""         ) ) ) {
                 return true
             }
+            if v := tfutil.GetListOfStrings(d, "dns_client.0.server_addresses"); ( len(v) > 0 ) &&
+               ( strings.Join(sortServerAddresses(v), ",") != strings.Join(sortServerAddresses(naProperties.DNSClient[0].ServerAddresses), ",") ) {
+                return true
+            }
+            if v, ok := d.GetOk("dns_client.0.reset_to_dhcp"); ok && v.(bool) && !naProperties.DNSClient[0].ResetToDHCP {
+                return true
+            }
         } else {
             return true
         }
@@ -558,8 +1241,41 @@ func diffNetworkAdapterProperties(d *schema.ResourceData, naProperties *api.Netw
 }
 
 func expandNetworkAdapterProperties(naProperties *api.NetworkAdapter, d *schema.ResourceData) {
-    naProperties.NewName    = d.Get("new_name").(string)
-    naProperties.MACAddress = d.Get("mac_address").(string)
+    naProperties.NewName         = d.Get("new_name").(string)
+    naProperties.AllowDisconnect = d.Get("allow_disconnect").(bool)
+    naProperties.MACAddress      = tfutil.NormalizeMAC(d.Get("mac_address").(string))
+
+    naProperties.AdminStatus = d.Get("admin_status").(string)
+
+    for _, ap := range tfutil.GetSetOfResources(d, "advanced_property") {
+        naProperties.AdvancedProperties = append(naProperties.AdvancedProperties, api.NetworkAdapterAdvancedProperty{
+            RegistryKeyword: ap["registry_keyword"].(string),
+            RegistryValue:   ap["registry_value"].(string),
+        })
+    }
+
+    // restore the original values of the advanced properties that are removed from config
+    if d.HasChange("advanced_property") {
+        o, n := d.GetChange("advanced_property")
+        original := tfutil.GetResource(d, "original")
+        for _, old_ap := range o.(*schema.Set).List() {
+            registryKeyword := old_ap.(map[string]interface{})["registry_keyword"].(string)
+
+            removed := true
+            for _, new_ap := range n.(*schema.Set).List() {
+                if new_ap.(map[string]interface{})["registry_keyword"].(string) == registryKeyword {
+                    removed = false
+                }
+            }
+
+            if original_ap := findOriginalNetworkAdapterAdvancedProperty(original, registryKeyword); removed && ( original_ap != nil ) {
+                naProperties.AdvancedProperties = append(naProperties.AdvancedProperties, api.NetworkAdapterAdvancedProperty{
+                    RegistryKeyword: registryKeyword,
+                    RegistryValue:   original_ap["registry_value"].(string),
+                })
+            }
+        }
+    }
 
     dnsClientList := tfutil.GetListOfResources(d, "dns_client")
     if len(dnsClientList) > 0 {
@@ -591,6 +1307,10 @@ func expandNetworkAdapterProperties(naProperties *api.NetworkAdapter, d *schema.
                 naProperties.DNSClient[0].RegisterConnectionSuffix = original_dnsClientList[0]["register_connection_suffix"].(string)
             }
         }
+
+        // an empty list leaves the DNS servers unchanged, use 'reset_to_dhcp' to remove the static DNS servers
+        naProperties.DNSClient[0].ServerAddresses = tfutil.GetListOfStrings(d, "dns_client.0.server_addresses")
+        naProperties.DNSClient[0].ResetToDHCP     = d.Get("dns_client.0.reset_to_dhcp").(bool)
     }
 }
 
@@ -600,6 +1320,15 @@ func expandOriginalNetworkAdapterProperties(naProperties *api.NetworkAdapter, d
     naProperties.NewName    = original["old_name"].(string)
     naProperties.MACAddress = original["mac_address"].(string)
 
+    naProperties.AdminStatus = original["admin_status"].(string)
+
+    for _, original_ap := range tfutil.ExpandListOfResources(original, "advanced_property") {
+        naProperties.AdvancedProperties = append(naProperties.AdvancedProperties, api.NetworkAdapterAdvancedProperty{
+            RegistryKeyword: original_ap["registry_keyword"].(string),
+            RegistryValue:   original_ap["registry_value"].(string),
+        })
+    }
+
     original_dnsClientList := tfutil.ExpandListOfResources(original, "dns_client")
     if len(original_dnsClientList) > 0 {
         original_dnsClient := original_dnsClientList[0]
@@ -607,6 +1336,8 @@ func expandOriginalNetworkAdapterProperties(naProperties *api.NetworkAdapter, d
         naProperties.DNSClient = make([]api.NetworkAdapterDNSClient, 1, 1)
         naProperties.DNSClient[0].RegisterConnectionAddress = original_dnsClient["register_connection_address"].(bool)
         naProperties.DNSClient[0].RegisterConnectionSuffix  = original_dnsClient["register_connection_suffix"].(string)
+        naProperties.DNSClient[0].ServerAddresses           = tfutil.ExpandListOfStrings(original_dnsClient, "server_addresses")
+        naProperties.DNSClient[0].ResetToDHCP               = original_dnsClient["reset_to_dhcp"].(bool)
     }
 }
 
@@ -0,0 +1,432 @@
+//
+// Copyright (c) 2019 Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-windows
+//
+package windows
+
+import (
+    "fmt"
+    "log"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+    "github.com/stefaanc/terraform-provider-windows/api"
+    "github.com/stefaanc/terraform-provider-windows/windows/tfutil"
+)
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsNetworkAdapterBinding() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "network_adapter_guid": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Computed: true,
+                ForceNew: true,
+
+                ValidateFunc: tfutil.ValidateUUID(),
+                StateFunc: tfutil.StateToUpper(),
+            },
+            "network_adapter_name": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Computed: true,
+                ForceNew: true,
+
+                ConflictsWith: []string{ "network_adapter_guid" },
+            },
+            "component_id": &schema.Schema{
+                Type:     schema.TypeString,
+                Required: true,
+                ForceNew: true,
+
+                StateFunc: tfutil.StateToLower(),
+            },
+
+            "enabled": &schema.Schema{
+                Type:     schema.TypeBool,
+                Required: true,
+            },
+
+            "allow_disconnect": &schema.Schema{
+                Type:     schema.TypeBool,
+                Optional: true,
+            },
+
+            "display_name": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+
+            // lifecycle customizations that are not supported by the 'lifecycle' meta-argument for persistent resources (similar to data-sources)
+            "x_lifecycle": &tfutil.DataSourceXLifecycleSchema,
+
+            // used to reset values on terraform destroy
+            "original": &schema.Schema{
+                Type:     schema.TypeList,
+                MaxItems: 1,
+                Computed: true,
+                Elem: &schema.Resource{
+                    Schema: map[string]*schema.Schema{
+                        "enabled": &schema.Schema{
+                            Type:     schema.TypeBool,
+                            Computed: true,
+                        },
+                    },
+                },
+            },
+        },
+
+        CustomizeDiff: resourceWindowsNetworkAdapterBindingCustomizeDiff,
+
+        Create: resourceWindowsNetworkAdapterBindingCreate,
+        Read:   resourceWindowsNetworkAdapterBindingRead,
+        Update: resourceWindowsNetworkAdapterBindingUpdate,
+        Delete: resourceWindowsNetworkAdapterBindingDelete,
+    }
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsNetworkAdapterBindingCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+    // refuse changes that could cut the ssh-connection of the provider, unless 'allow_disconnect' is set
+    if !d.Get("allow_disconnect").(bool) {
+        err := customizeDiffNetworkAdapterBindingManagementConnection(d, m.(*api.WindowsClient))
+        if err != nil {
+            return err
+        }
+    }
+
+    return nil
+}
+
+func customizeDiffNetworkAdapterBindingManagementConnection(d *schema.ResourceDiff, c *api.WindowsClient) error {
+    // only consider the changes that could cut the connection
+    if ( ( d.Id() != "" ) && !d.HasChange("enabled") ) || d.Get("enabled").(bool) {
+        return nil
+    }
+
+    managementConnection, err := c.ReadManagementConnection()
+    if err != nil {
+        return err
+    }
+    if len(managementConnection.NetworkAdapterNames) == 0 {
+        return nil
+    }
+
+    // the bindings carrying the connection
+    componentID := strings.ToLower(d.Get("component_id").(string))
+    switch {
+    case componentID == "vms_pp":
+    case ( componentID == "ms_tcpip"  ) && !strings.Contains(managementConnection.IPAddress, ":"):
+    case ( componentID == "ms_tcpip6" ) && strings.Contains(managementConnection.IPAddress, ":"):
+    default:
+        return nil
+    }
+
+    nabQuery := new(api.NetworkAdapterBinding)
+    nabQuery.NetworkAdapterGUID = d.Get("network_adapter_guid").(string)
+    nabQuery.NetworkAdapterName = d.Get("network_adapter_name").(string)
+    nabQuery.ComponentID        = componentID
+
+    networkAdapterBinding, err := c.ReadNetworkAdapterBinding(nabQuery)
+    if err != nil {
+        return nil   // leave it to create/update to handle a missing network adapter binding
+    }
+    if !networkAdapterBinding.Enabled {
+        return nil
+    }
+
+    for _, name := range managementConnection.NetworkAdapterNames {
+        if name == networkAdapterBinding.NetworkAdapterName {
+            return fmt.Errorf("[ERROR][terraform-provider-windows] cannot disable binding %q for windows_network_adapter_binding %q, network adapter carries the ssh-connection of the provider - set 'allow_disconnect' to force the change", componentID, networkAdapterBinding.NetworkAdapterName)
+        }
+    }
+
+    return nil
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsNetworkAdapterBindingCreate(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    networkAdapterGUID := d.Get("network_adapter_guid").(string)
+    networkAdapterName := d.Get("network_adapter_name").(string)
+    componentID        := d.Get("component_id").(string)
+    enabled            := d.Get("enabled").(bool)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    var networkAdapterId string
+    if networkAdapterGUID != "" { networkAdapterId = networkAdapterGUID } else
+    if networkAdapterName != "" { networkAdapterId = networkAdapterName }
+    id := fmt.Sprintf("//%s/network_adapters/%s/bindings/%s", host, networkAdapterId, strings.ToLower(componentID))
+
+    log.Printf(`[INFO][terraform-provider-windows] creating windows_network_adapter_binding %q
+                    [INFO][terraform-provider-windows]     network_adapter_guid: %#v
+                    [INFO][terraform-provider-windows]     network_adapter_name: %#v
+                    [INFO][terraform-provider-windows]     component_id:         %#v
+                    [INFO][terraform-provider-windows]     enabled:              %#v
+`       ,
+        id,
+        networkAdapterGUID,
+        networkAdapterName,
+        componentID,
+        enabled,
+    )
+
+    // import
+    log.Printf("[INFO][terraform-provider-windows] importing windows_network_adapter_binding %q into terraform state\n", id)
+
+    x_lifecycle := tfutil.GetResource(d, "x_lifecycle")
+
+    nabQuery := new(api.NetworkAdapterBinding)
+    nabQuery.NetworkAdapterGUID = networkAdapterGUID
+    nabQuery.NetworkAdapterName = networkAdapterName
+    nabQuery.ComponentID        = componentID
+
+    // lifecycle customizations: wait_until_exists
+    var networkAdapterBinding *api.NetworkAdapterBinding
+    err := tfutil.WaitUntilExists(x_lifecycle, "cannot find network_adapter", func() (err error) {
+        networkAdapterBinding, err = c.ReadNetworkAdapterBinding(nabQuery)
+        return err
+    })
+    if err != nil {
+        // lifecycle customizations: ignore_error_if_not_exists
+        v, ok := x_lifecycle["ignore_error_if_not_exists"]
+        if ok && v.(bool) && strings.Contains(err.Error(), "cannot find network_adapter") {
+            log.Printf("[INFO][terraform-provider-windows] cannot import windows_network_adapter_binding %q into terraform state\n", id)
+
+            // set zeroed properties
+            d.Set("network_adapter_guid", "")
+            d.Set("network_adapter_name", "")
+            d.Set("component_id", "")
+            d.Set("enabled", false)
+            d.Set("display_name", "")
+
+            // set computed lifecycle properties
+            x_lifecycle["exists"] = false
+            d.Set("x_lifecycle", []interface{}{ x_lifecycle })
+
+            // set id
+            d.SetId(id)
+
+            log.Printf("[INFO][terraform-provider-windows] ignored error and added zeroed windows_network_adapter_binding %q to terraform state\n", id)
+            return nil
+        }
+
+        // no lifecycle customizations
+        log.Printf("[ERROR][terraform-provider-windows] cannot import windows_network_adapter_binding %q into terraform state\n", id)
+        return err
+    }
+
+    // set computed lifecycle properties
+    x_lifecycle["exists"] = true
+    d.Set("x_lifecycle", []interface{}{ x_lifecycle })
+
+    // save original config
+    setOriginalNetworkAdapterBindingProperties(d, networkAdapterBinding)
+
+    // set principal identifying property, so it can be found after a rename of the network adapter
+    d.Set("network_adapter_guid", networkAdapterBinding.NetworkAdapterGUID)
+
+    // check diff
+    if networkAdapterBinding.Enabled == enabled {
+        // no update required
+
+        // set properties
+        setNetworkAdapterBindingProperties(d, networkAdapterBinding)
+
+        // set id
+        d.SetId(id)
+
+        log.Printf("[INFO][terraform-provider-windows] created windows_network_adapter_binding %q\n", id)
+        return nil
+
+    } else {
+        // update
+        log.Printf("[INFO][terraform-provider-windows] updating windows_network_adapter_binding %q\n", id)
+
+        nabQuery.NetworkAdapterGUID = networkAdapterBinding.NetworkAdapterGUID
+
+        nabProperties := new(api.NetworkAdapterBinding)
+        expandNetworkAdapterBindingProperties(nabProperties, d)
+
+        err := c.UpdateNetworkAdapterBinding(nabQuery, nabProperties)
+        if err != nil {
+            log.Printf("[ERROR][terraform-provider-windows] cannot update windows_network_adapter_binding %q\n", id)
+            return err
+        }
+
+        // set id
+        d.SetId(id)
+
+        log.Printf("[INFO][terraform-provider-windows] created windows_network_adapter_binding %q\n", id)
+        return resourceWindowsNetworkAdapterBindingRead(d, m)
+    }
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsNetworkAdapterBindingRead(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    id := d.Id()
+
+    log.Printf("[INFO][terraform-provider-windows] reading windows_network_adapter_binding %q\n", id)
+
+    x_lifecycle := tfutil.GetResource(d, "x_lifecycle")
+
+    // read
+    nabQuery := new(api.NetworkAdapterBinding)
+    nabQuery.NetworkAdapterGUID = d.Get("network_adapter_guid").(string)
+    nabQuery.NetworkAdapterName = d.Get("network_adapter_name").(string)
+    nabQuery.ComponentID        = d.Get("component_id").(string)
+
+    networkAdapterBinding, err := c.ReadNetworkAdapterBinding(nabQuery)
+    if err != nil {
+        // lifecycle customizations: ignore_error_if_not_exists
+        v, ok := x_lifecycle["ignore_error_if_not_exists"]
+        if ok && v.(bool) && strings.Contains(err.Error(), "cannot find network_adapter") {
+            log.Printf("[INFO][terraform-provider-windows] cannot import windows_network_adapter_binding %q into terraform state\n", id)
+
+            // set zeroed properties
+            d.Set("network_adapter_guid", "")
+            d.Set("network_adapter_name", "")
+            d.Set("component_id", "")
+            d.Set("enabled", false)
+            d.Set("display_name", "")
+
+            // set computed lifecycle properties
+            x_lifecycle["exists"] = false
+            d.Set("x_lifecycle", []interface{}{ x_lifecycle })
+
+            // set id
+            d.SetId(id)
+
+            log.Printf("[INFO][terraform-provider-windows] ignored error and added zeroed windows_network_adapter_binding %q to terraform state\n", id)
+            return nil
+        }
+
+        // no lifecycle customizations
+        log.Printf("[INFO][terraform-provider-windows] cannot read windows_network_adapter_binding %q\n", id)
+
+        // set id
+        d.SetId("")
+
+        log.Printf("[ERROR][terraform-provider-windows] deleted windows_network_adapter_binding %q from terraform state\n", id)
+        return nil   // don't return an error to allow terraform refresh to update state
+    }
+
+    // set properties
+    setNetworkAdapterBindingProperties(d, networkAdapterBinding)
+
+    log.Printf("[INFO][terraform-provider-windows] read windows_network_adapter_binding %q\n", id)
+    return nil
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsNetworkAdapterBindingUpdate(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    id      := d.Id()
+    enabled := d.Get("enabled").(bool)
+
+    log.Printf(`[INFO][terraform-provider-windows] updating windows_network_adapter_binding %q
+                    [INFO][terraform-provider-windows]     enabled: %#v
+`       ,
+        id,
+        enabled,
+    )
+
+    // update
+    nabQuery := new(api.NetworkAdapterBinding)
+    nabQuery.NetworkAdapterGUID = d.Get("network_adapter_guid").(string)
+    nabQuery.ComponentID        = d.Get("component_id").(string)
+
+    nabProperties := new(api.NetworkAdapterBinding)
+    expandNetworkAdapterBindingProperties(nabProperties, d)
+
+    err := c.UpdateNetworkAdapterBinding(nabQuery, nabProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows] cannot update windows_network_adapter_binding %q\n", id)
+        return err
+    }
+
+    log.Printf("[INFO][terraform-provider-windows] updated windows_network_adapter_binding %q\n", id)
+    return resourceWindowsNetworkAdapterBindingRead(d, m)
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsNetworkAdapterBindingDelete(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    id := d.Id()
+
+    log.Printf("[INFO][terraform-provider-windows] deleting windows_network_adapter_binding %q from terraform state\n", id)
+    log.Printf("[INFO][terraform-provider-windows] restore original config for windows_network_adapter_binding %q\n", id)
+
+    // restore original config
+    nabQuery := new(api.NetworkAdapterBinding)
+    nabQuery.NetworkAdapterGUID = d.Get("network_adapter_guid").(string)
+    nabQuery.ComponentID        = d.Get("component_id").(string)
+
+    nabProperties := new(api.NetworkAdapterBinding)
+    expandOriginalNetworkAdapterBindingProperties(nabProperties, d)
+
+    err := c.UpdateNetworkAdapterBinding(nabQuery, nabProperties)
+    if err != nil {
+        log.Printf("[WARNING][terraform-provider-windows] cannot restore original config for windows_network_adapter_binding %q\n", id)
+    }
+
+    // set id
+    d.SetId("")
+
+    log.Printf("[INFO][terraform-provider-windows] deleted windows_network_adapter_binding %q from terraform state\n", id)
+    return nil
+}
+
+//------------------------------------------------------------------------------
+
+func setNetworkAdapterBindingProperties(d *schema.ResourceData, nabProperties *api.NetworkAdapterBinding) {
+    d.Set("network_adapter_guid", nabProperties.NetworkAdapterGUID)
+    d.Set("network_adapter_name", nabProperties.NetworkAdapterName)
+    d.Set("component_id", nabProperties.ComponentID)
+
+    d.Set("enabled", nabProperties.Enabled)
+
+    d.Set("display_name", nabProperties.DisplayName)
+}
+
+func setOriginalNetworkAdapterBindingProperties(d *schema.ResourceData, nabProperties *api.NetworkAdapterBinding) {
+    original := make(map[string]interface{})
+
+    original["enabled"] = nabProperties.Enabled
+
+    d.Set("original", []interface{}{ original })
+}
+
+//------------------------------------------------------------------------------
+
+func expandNetworkAdapterBindingProperties(nabProperties *api.NetworkAdapterBinding, d *schema.ResourceData) {
+    nabProperties.Enabled         = d.Get("enabled").(bool)
+    nabProperties.AllowDisconnect = d.Get("allow_disconnect").(bool)
+}
+
+func expandOriginalNetworkAdapterBindingProperties(nabProperties *api.NetworkAdapterBinding, d *schema.ResourceData) {
+    original := tfutil.GetResource(d, "original")
+
+    nabProperties.Enabled = original["enabled"].(bool)
+}
+
+//------------------------------------------------------------------------------
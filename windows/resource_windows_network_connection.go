@@ -10,6 +10,7 @@ import (
     "fmt"
     "log"
     "strings"
+    "time"
 
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
     "github.com/hashicorp/terraform-plugin-sdk/helper/validation"
@@ -87,6 +88,20 @@ func resourceWindowsNetworkConnection() *schema.Resource {
                 StateFunc:    tfutil.StateToCamel(),
             },
 
+            "wait_for_connectivity": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+
+                ValidateFunc: validation.StringInSlice(tfutil.ConnectivityLevels[1:], true),
+            },
+            "wait_for_connectivity_timeout": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Default:  "5m",
+
+                ValidateFunc: tfutil.ValidateDuration(),
+            },
+
             "ipv4_connectivity": &schema.Schema{
                 Type:     schema.TypeString,
                 Computed: true,
@@ -105,6 +120,9 @@ func resourceWindowsNetworkConnection() *schema.Resource {
             // lifecycle customizations that are not supported by the 'lifecycle' meta-argument for persistent resources (similar to data-sources)
             "x_lifecycle": &tfutil.DataSourceXLifecycleSchema,
 
+            // schedules a rollback to the original values before an update, that is cancelled when the windows-computer can be verified after the update
+            "rollback_protection": &tfutil.RollbackProtectionSchema,
+
             // used to reset values on terraform destroy
             "original": &schema.Schema{
                 Type:     schema.TypeList,
@@ -155,9 +173,67 @@ func resourceWindowsNetworkConnectionCustomizeDiff(d *schema.ResourceDiff, m int
         }
     }
 
+    // refuse changes that could cut the ssh-connection of the provider, unless 'allow_disconnect' is set
+    if !d.Get("allow_disconnect").(bool) {
+        err := customizeDiffNetworkConnectionManagementConnection(d, m.(*api.WindowsClient))
+        if err != nil {
+            return err
+        }
+    }
+
     return nil
 }
 
+func customizeDiffNetworkConnectionManagementConnection(d *schema.ResourceDiff, c *api.WindowsClient) error {
+    // only consider the changes that could cut the connection
+    if ( d.Id() != "" ) && !d.HasChange("connection_profile") {
+        return nil
+    }
+    connectionProfile := d.Get("connection_profile").(string)
+    if connectionProfile == "" {
+        return nil
+    }
+
+    managementConnection, err := c.ReadManagementConnection()
+    if err != nil {
+        return err
+    }
+    if len(managementConnection.NetworkConnectionNames) == 0 {
+        return nil
+    }
+
+    ncQuery := new(api.NetworkConnection)
+    ncQuery.GUID               = d.Get("guid").(string)
+    ncQuery.IPv4GatewayAddress = d.Get("ipv4_gateway_address").(string)
+    ncQuery.IPv6GatewayAddress = d.Get("ipv6_gateway_address").(string)
+    ncQuery.Name               = d.Get("name").(string)
+    ncQuery.OldName            = d.Get("old_name").(string)
+
+    networkConnection, err := c.ReadNetworkConnection(ncQuery)
+    if err != nil {
+        return nil   // leave it to create/update to handle a missing network connection
+    }
+
+    isManagement := false
+    for _, name := range managementConnection.NetworkConnectionNames {
+        if name == networkConnection.Name {
+            isManagement = true
+        }
+    }
+    if !isManagement || strings.EqualFold(networkConnection.ConnectionProfile, connectionProfile) {
+        return nil
+    }
+
+    // the connection profile selects the firewall profile
+    for _, allowedProfile := range managementConnection.AllowedProfiles {
+        if strings.EqualFold(allowedProfile, connectionProfile) {
+            return nil
+        }
+    }
+
+    return fmt.Errorf("[ERROR][terraform-provider-windows] cannot change 'connection_profile' to %q for windows_network_connection %q, network connection carries the ssh-connection of the provider and the %q firewall profile doesn't allow it - set 'allow_disconnect' to force the change", connectionProfile, networkConnection.Name, connectionProfile)
+}
+
 //------------------------------------------------------------------------------
 
 func resourceWindowsNetworkConnectionCreate(d *schema.ResourceData, m interface{}) error {
@@ -216,7 +292,12 @@ func resourceWindowsNetworkConnectionCreate(d *schema.ResourceData, m interface{
     ncQuery.OldName            = oldName
     ncQuery.AllowDisconnect    = d.Get("allow_disconnect").(bool)
 
-    networkConnection, err := c.ReadNetworkConnection(ncQuery)
+    // lifecycle customizations: wait_until_exists
+    var networkConnection *api.NetworkConnection
+    err := tfutil.WaitUntilExists(x_lifecycle, "cannot find network_connection", func() (err error) {
+        networkConnection, err = c.ReadNetworkConnection(ncQuery)
+        return err
+    })
     if err != nil {
         // lifecycle customizations: ignore_error_if_not_exists
         v, ok := x_lifecycle["ignore_error_if_not_exists"]
@@ -281,7 +362,7 @@ func resourceWindowsNetworkConnectionCreate(d *schema.ResourceData, m interface{
         ncProperties := new(api.NetworkConnection)
         expandNetworkConnectionProperties(ncProperties, d)
 
-        err := c.UpdateNetworkConnection(networkConnection, ncProperties)
+        err := updateNetworkConnectionWithRollbackProtection(c, d, networkConnection, ncProperties)
         if err != nil {
             log.Printf("[ERROR][terraform-provider-windows] cannot update windows_network_connection %q\n", id)
             return err
@@ -290,6 +371,12 @@ func resourceWindowsNetworkConnectionCreate(d *schema.ResourceData, m interface{
         // set id
         d.SetId(id)
 
+        err = waitForNetworkConnectionConnectivity(c, d)
+        if err != nil {
+            log.Printf("[ERROR][terraform-provider-windows] cannot reach connectivity for windows_network_connection %q\n", id)
+            return err
+        }
+
         log.Printf("[INFO][terraform-provider-windows] created windows_network_connection %q\n", id)
         return resourceWindowsNetworkConnectionRead(d, m)
     }
@@ -403,12 +490,18 @@ func resourceWindowsNetworkConnectionUpdate(d *schema.ResourceData, m interface{
     ncProperties := new(api.NetworkConnection)
     expandNetworkConnectionProperties(ncProperties, d)
 
-    err := c.UpdateNetworkConnection(ncQuery, ncProperties)
+    err := updateNetworkConnectionWithRollbackProtection(c, d, ncQuery, ncProperties)
     if err != nil {
         log.Printf("[ERROR][terraform-provider-windows] cannot update windows_network_connection %q\n", id)
         return err
     }
 
+    err = waitForNetworkConnectionConnectivity(c, d)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows] cannot reach connectivity for windows_network_connection %q\n", id)
+        return err
+    }
+
     log.Printf("[INFO][terraform-provider-windows] updated windows_network_connection %q\n", id)
     return resourceWindowsNetworkConnectionRead(d, m)
 }
@@ -444,6 +537,51 @@ func resourceWindowsNetworkConnectionDelete(d *schema.ResourceData, m interface{
 
 //------------------------------------------------------------------------------
 
+func updateNetworkConnectionWithRollbackProtection(c *api.WindowsClient, d *schema.ResourceData, ncQuery *api.NetworkConnection, ncProperties *api.NetworkConnection) error {
+    rbQuery := new(api.NetworkConnection)
+    rbQuery.GUID = ncQuery.GUID
+
+    rollbackProtection := tfutil.GetResource(d, "rollback_protection")
+    return tfutil.WithRollbackProtection(rollbackProtection,
+        func(window time.Duration) error {
+            ncOriginal := new(api.NetworkConnection)
+            expandOriginalNetworkConnectionProperties(ncOriginal, d)
+            return c.CreateNetworkConnectionRollback(rbQuery, ncOriginal, uint32(window.Seconds()))
+        },
+        func() error {
+            return c.UpdateNetworkConnection(ncQuery, ncProperties)
+        },
+        func() error {
+            // reconnects to the windows-computer
+            _, err := c.ReadNetworkConnection(rbQuery)
+            return err
+        },
+        func() error {
+            return c.DeleteNetworkConnectionRollback(rbQuery)
+        },
+    )
+}
+
+//------------------------------------------------------------------------------
+
+func waitForNetworkConnectionConnectivity(c *api.WindowsClient, d *schema.ResourceData) error {
+    connectivity := d.Get("wait_for_connectivity").(string)
+    timeout, _   := time.ParseDuration(d.Get("wait_for_connectivity_timeout").(string))
+
+    ncQuery := new(api.NetworkConnection)
+    ncQuery.GUID = d.Get("guid").(string)
+
+    return tfutil.WaitForConnectivity(connectivity, timeout, func() (ipv4Connectivity string, ipv6Connectivity string, err error) {
+        networkConnection, err := c.ReadNetworkConnection(ncQuery)
+        if err != nil {
+            return "", "", err
+        }
+        return networkConnection.IPv4Connectivity, networkConnection.IPv6Connectivity, nil
+    })
+}
+
+//------------------------------------------------------------------------------
+
 func setNetworkConnectionProperties(d *schema.ResourceData, ncProperties *api.NetworkConnection) {
     d.Set("guid", ncProperties.GUID)
 
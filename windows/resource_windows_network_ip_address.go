@@ -0,0 +1,534 @@
+//
+// Copyright (c) 2019 Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-windows
+//
+package windows
+
+import (
+    "fmt"
+    "log"
+    "net"
+    "strings"
+    "time"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+    "github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+
+    "github.com/stefaanc/terraform-provider-windows/api"
+    "github.com/stefaanc/terraform-provider-windows/windows/tfutil"
+)
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsNetworkIPAddress() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "network_adapter_guid": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Computed: true,
+                ForceNew: true,
+
+                ValidateFunc: tfutil.ValidateUUID(),
+                StateFunc: tfutil.StateToUpper(),
+            },
+            "network_adapter_name": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Computed: true,
+                ForceNew: true,
+
+                ConflictsWith: []string{ "network_adapter_guid" },
+            },
+
+            "ip_address": &schema.Schema{
+                Type:     schema.TypeString,
+                Required: true,
+                ForceNew: true,
+
+                ValidateFunc: validation.SingleIP(),
+                StateFunc: tfutil.StateToIP(),
+            },
+            "prefix_length": &schema.Schema{
+                Type:     schema.TypeInt,   // uint8
+                Required: true,
+
+                ValidateFunc: validation.IntBetween(0, 128),
+            },
+            "address_family": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Computed: true,
+                ForceNew: true,
+
+                ValidateFunc: validation.StringInSlice([]string{ "IPv4", "IPv6" }, false),
+            },
+            "skip_as_source": &schema.Schema{
+                Type:     schema.TypeBool,
+                Optional: true,
+                Default:  false,
+            },
+            "valid_lifetime": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Default:  "infinite",
+
+                ValidateFunc: validation.Any(validation.StringInSlice([]string{ "infinite" }, false), tfutil.ValidateDuration()),
+                DiffSuppressFunc: diffSuppressNetworkIPAddressLifetime,
+            },
+            "preferred_lifetime": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Default:  "infinite",
+
+                ValidateFunc: validation.Any(validation.StringInSlice([]string{ "infinite" }, false), tfutil.ValidateDuration()),
+                DiffSuppressFunc: diffSuppressNetworkIPAddressLifetime,
+            },
+
+            "prefix_origin": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "suffix_origin": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "address_state": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+
+            // lifecycle customizations that are not supported by the 'lifecycle' meta-argument
+            "x_lifecycle": &tfutil.ResourceXLifecycleSchema,
+
+            // used to reset values on terraform destroy
+            "original": &schema.Schema{
+                Type:     schema.TypeList,
+                MaxItems: 1,
+                Computed: true,
+                Elem: resourceWindowsNetworkIPAddressOriginal(),
+            },
+        },
+
+        CustomizeDiff: resourceWindowsNetworkIPAddressCustomizeDiff,
+
+        Create: resourceWindowsNetworkIPAddressCreate,
+        Read:   resourceWindowsNetworkIPAddressRead,
+        Update: resourceWindowsNetworkIPAddressUpdate,
+        Delete: resourceWindowsNetworkIPAddressDelete,
+    }
+}
+
+func resourceWindowsNetworkIPAddressOriginal() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "dhcp": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+
+            // only set when the ip address was imported
+            "prefix_length": &schema.Schema{
+                Type:     schema.TypeInt,
+                Computed: true,
+            },
+            "skip_as_source": &schema.Schema{
+                Type:     schema.TypeBool,
+                Computed: true,
+            },
+            "valid_lifetime": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "preferred_lifetime": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+        },
+    }
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsNetworkIPAddressCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+    // check 'address_family' against 'ip_address' when planning, not only when creating the resource
+    if d.NewValueKnown("ip_address") && d.NewValueKnown("address_family") {
+        ipAddress := d.Get("ip_address").(string)
+        if v, ok := d.GetOk("address_family"); ok && ( v.(string) != networkIPAddressFamily(ipAddress) ) {
+            return fmt.Errorf("[ERROR][terraform-provider-windows] 'address_family' %q doesn't match 'ip_address' %q", v.(string), ipAddress)
+        }
+    }
+
+    return nil
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsNetworkIPAddressCreate(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    networkAdapterGUID := d.Get("network_adapter_guid").(string)
+    networkAdapterName := d.Get("network_adapter_name").(string)
+    ipAddress          := d.Get("ip_address").(string)
+    prefixLength       := d.Get("prefix_length").(int)
+    addressFamily      := d.Get("address_family").(string)
+    skipAsSource       := d.Get("skip_as_source").(bool)
+    validLifetime      := d.Get("valid_lifetime").(string)
+    preferredLifetime  := d.Get("preferred_lifetime").(string)
+
+    if addressFamily == "" {
+        addressFamily = networkIPAddressFamily(ipAddress)
+    } else if addressFamily != networkIPAddressFamily(ipAddress) {
+        return fmt.Errorf("[ERROR][terraform-provider-windows] 'address_family' %q doesn't match 'ip_address' %q", addressFamily, ipAddress)
+    }
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    var networkAdapterId string
+    if networkAdapterGUID != "" { networkAdapterId = networkAdapterGUID } else
+    if networkAdapterName != "" { networkAdapterId = networkAdapterName }
+    id := fmt.Sprintf("//%s/network_adapters/%s/ip_addresses/%s", host, networkAdapterId, strings.ToLower(ipAddress))
+
+    log.Printf(`[INFO][terraform-provider-windows] creating windows_network_ip_address %q
+                    [INFO][terraform-provider-windows]     network_adapter_guid: %#v
+                    [INFO][terraform-provider-windows]     network_adapter_name: %#v
+                    [INFO][terraform-provider-windows]     ip_address:           %#v
+                    [INFO][terraform-provider-windows]     prefix_length:        %#v
+                    [INFO][terraform-provider-windows]     address_family:       %#v
+                    [INFO][terraform-provider-windows]     skip_as_source:       %#v
+                    [INFO][terraform-provider-windows]     valid_lifetime:       %#v
+                    [INFO][terraform-provider-windows]     preferred_lifetime:   %#v
+`       ,
+        id,
+        networkAdapterGUID,
+        networkAdapterName,
+        ipAddress,
+        prefixLength,
+        addressFamily,
+        skipAsSource,
+        validLifetime,
+        preferredLifetime,
+    )
+
+    x_lifecycle := tfutil.GetResource(d, "x_lifecycle")
+
+    ipQuery := new(api.NetworkIPAddress)
+    ipQuery.NetworkAdapterGUID = networkAdapterGUID
+    ipQuery.NetworkAdapterName = networkAdapterName
+    ipQuery.IPAddress          = ipAddress
+
+    networkIPAddress, err := c.ReadNetworkIPAddress(ipQuery)
+    if err == nil {
+        // lifecycle customizations: import_if_exists
+        v, ok := x_lifecycle["import_if_exists"]
+        if !ok || !v.(bool) {
+            log.Printf("[ERROR][terraform-provider-windows] cannot create windows_network_ip_address %q\n", id)
+            return fmt.Errorf("[ERROR][terraform-provider-windows] cannot create windows_network_ip_address %q, ip address already exists", id)
+        }
+
+        log.Printf("[INFO][terraform-provider-windows] importing windows_network_ip_address %q into terraform state\n", id)
+
+        // set computed lifecycle properties
+        x_lifecycle["imported"] = true
+        d.Set("x_lifecycle", []interface{}{ x_lifecycle })
+
+        // save original config
+        setOriginalNetworkIPAddressProperties(d, networkIPAddress, true)
+
+        // set principal identifying property
+        d.Set("network_adapter_guid", networkIPAddress.NetworkAdapterGUID)
+        ipQuery.NetworkAdapterGUID = networkIPAddress.NetworkAdapterGUID
+
+        // update
+        ipProperties := new(api.NetworkIPAddress)
+        expandNetworkIPAddressProperties(ipProperties, d)
+        ipProperties.DHCP = "Disabled"
+
+        err := c.UpdateNetworkIPAddress(ipQuery, ipProperties)
+        if err != nil {
+            log.Printf("[ERROR][terraform-provider-windows] cannot update windows_network_ip_address %q\n", id)
+            return err
+        }
+
+        // set id
+        d.SetId(id)
+
+        log.Printf("[INFO][terraform-provider-windows] created windows_network_ip_address %q\n", id)
+        return resourceWindowsNetworkIPAddressRead(d, m)
+    }
+    if !strings.Contains(err.Error(), "cannot find network_ip_address") {
+        log.Printf("[ERROR][terraform-provider-windows] cannot create windows_network_ip_address %q\n", id)
+        return err
+    }
+
+    // set computed lifecycle properties
+    x_lifecycle["imported"] = false
+    d.Set("x_lifecycle", []interface{}{ x_lifecycle })
+
+    // create
+    ipProperties := new(api.NetworkIPAddress)
+    ipProperties.NetworkAdapterGUID = networkAdapterGUID
+    ipProperties.NetworkAdapterName = networkAdapterName
+    ipProperties.IPAddress          = ipAddress
+    ipProperties.AddressFamily      = addressFamily
+    expandNetworkIPAddressProperties(ipProperties, d)
+    ipProperties.DHCP = "Disabled"
+
+    ipOriginal, err := c.CreateNetworkIPAddress(ipProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows] cannot create windows_network_ip_address %q\n", id)
+        return err
+    }
+
+    // save original config
+    setOriginalNetworkIPAddressProperties(d, ipOriginal, false)
+
+    // set id
+    d.SetId(id)
+
+    // read principal identifying property, so it can be found after a rename of the network adapter
+    networkIPAddress, err = c.ReadNetworkIPAddress(ipQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows] cannot read windows_network_ip_address %q\n", id)
+        return err
+    }
+    d.Set("network_adapter_guid", networkIPAddress.NetworkAdapterGUID)
+
+    log.Printf("[INFO][terraform-provider-windows] created windows_network_ip_address %q\n", id)
+    return resourceWindowsNetworkIPAddressRead(d, m)
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsNetworkIPAddressRead(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    id := d.Id()
+
+    log.Printf("[INFO][terraform-provider-windows] reading windows_network_ip_address %q\n", id)
+
+    // read
+    ipQuery := new(api.NetworkIPAddress)
+    ipQuery.NetworkAdapterGUID = d.Get("network_adapter_guid").(string)
+    ipQuery.NetworkAdapterName = d.Get("network_adapter_name").(string)
+    ipQuery.IPAddress          = d.Get("ip_address").(string)
+
+    networkIPAddress, err := c.ReadNetworkIPAddress(ipQuery)
+    if err != nil {
+        // the ip address is gone when its network adapter is gone
+        if strings.Contains(err.Error(), "cannot find network_ip_address") || strings.Contains(err.Error(), "cannot find network_adapter") {
+            log.Printf("[INFO][terraform-provider-windows] cannot find windows_network_ip_address %q\n", id)
+
+            // set id
+            d.SetId("")
+
+            log.Printf("[INFO][terraform-provider-windows] deleted windows_network_ip_address %q from terraform state\n", id)
+            return nil   // don't return an error to allow terraform refresh to update state
+        }
+
+        log.Printf("[ERROR][terraform-provider-windows] cannot read windows_network_ip_address %q\n", id)
+        return err
+    }
+
+    // set properties
+    setNetworkIPAddressProperties(d, networkIPAddress)
+
+    log.Printf("[INFO][terraform-provider-windows] read windows_network_ip_address %q\n", id)
+    return nil
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsNetworkIPAddressUpdate(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    id                := d.Id()
+    prefixLength      := d.Get("prefix_length").(int)
+    skipAsSource      := d.Get("skip_as_source").(bool)
+    validLifetime     := d.Get("valid_lifetime").(string)
+    preferredLifetime := d.Get("preferred_lifetime").(string)
+
+    log.Printf(`[INFO][terraform-provider-windows] updating windows_network_ip_address %q
+                    [INFO][terraform-provider-windows]     prefix_length:      %#v
+                    [INFO][terraform-provider-windows]     skip_as_source:     %#v
+                    [INFO][terraform-provider-windows]     valid_lifetime:     %#v
+                    [INFO][terraform-provider-windows]     preferred_lifetime: %#v
+`       ,
+        id,
+        prefixLength,
+        skipAsSource,
+        validLifetime,
+        preferredLifetime,
+    )
+
+    // update
+    ipQuery := new(api.NetworkIPAddress)
+    ipQuery.NetworkAdapterGUID = d.Get("network_adapter_guid").(string)
+    ipQuery.IPAddress          = d.Get("ip_address").(string)
+
+    ipProperties := new(api.NetworkIPAddress)
+    expandNetworkIPAddressProperties(ipProperties, d)
+
+    err := c.UpdateNetworkIPAddress(ipQuery, ipProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows] cannot update windows_network_ip_address %q\n", id)
+        return err
+    }
+
+    log.Printf("[INFO][terraform-provider-windows] updated windows_network_ip_address %q\n", id)
+    return resourceWindowsNetworkIPAddressRead(d, m)
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsNetworkIPAddressDelete(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    id := d.Id()
+
+    x_lifecycle := tfutil.GetResource(d, "x_lifecycle")
+
+    ipQuery := new(api.NetworkIPAddress)
+    ipQuery.NetworkAdapterGUID = d.Get("network_adapter_guid").(string)
+    ipQuery.IPAddress          = d.Get("ip_address").(string)
+
+    ipProperties := new(api.NetworkIPAddress)
+    expandOriginalNetworkIPAddressProperties(ipProperties, d)
+
+    // lifecycle customizations: destroy_if_imported
+    imported, _          := x_lifecycle["imported"].(bool)
+    destroyIfImported, _ := x_lifecycle["destroy_if_imported"].(bool)
+    if imported && !destroyIfImported {
+        log.Printf("[INFO][terraform-provider-windows] deleting windows_network_ip_address %q from terraform state\n", id)
+        log.Printf("[INFO][terraform-provider-windows] restore original config for windows_network_ip_address %q\n", id)
+
+        // restore original config
+        err := c.UpdateNetworkIPAddress(ipQuery, ipProperties)
+        if err != nil {
+            log.Printf("[WARNING][terraform-provider-windows] cannot restore original config for windows_network_ip_address %q\n", id)
+        }
+
+        // set id
+        d.SetId("")
+
+        log.Printf("[INFO][terraform-provider-windows] deleted windows_network_ip_address %q from terraform state\n", id)
+        return nil
+    }
+
+    log.Printf("[INFO][terraform-provider-windows] deleting windows_network_ip_address %q\n", id)
+
+    // delete, and restore original dhcp config
+    err := c.DeleteNetworkIPAddress(ipQuery, ipProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows] cannot delete windows_network_ip_address %q\n", id)
+        return err
+    }
+
+    // set id
+    d.SetId("")
+
+    log.Printf("[INFO][terraform-provider-windows] deleted windows_network_ip_address %q\n", id)
+    return nil
+}
+
+//------------------------------------------------------------------------------
+
+func setNetworkIPAddressProperties(d *schema.ResourceData, ipProperties *api.NetworkIPAddress) {
+    d.Set("network_adapter_guid", ipProperties.NetworkAdapterGUID)
+    d.Set("network_adapter_name", ipProperties.NetworkAdapterName)
+
+    d.Set("ip_address", ipProperties.IPAddress)
+    d.Set("prefix_length", ipProperties.PrefixLength)
+    d.Set("address_family", ipProperties.AddressFamily)
+    d.Set("skip_as_source", ipProperties.SkipAsSource)
+
+    // finite lifetimes count down, so only refresh the lifetimes when they changed from finite to infinite or vice-versa
+    if ( d.Get("valid_lifetime").(string) == "infinite" ) != ( ipProperties.ValidLifetime == api.InfiniteLifetime ) {
+        d.Set("valid_lifetime", flattenNetworkIPAddressLifetime(ipProperties.ValidLifetime))
+    }
+    if ( d.Get("preferred_lifetime").(string) == "infinite" ) != ( ipProperties.PreferredLifetime == api.InfiniteLifetime ) {
+        d.Set("preferred_lifetime", flattenNetworkIPAddressLifetime(ipProperties.PreferredLifetime))
+    }
+
+    d.Set("prefix_origin", ipProperties.PrefixOrigin)
+    d.Set("suffix_origin", ipProperties.SuffixOrigin)
+    d.Set("address_state", ipProperties.AddressState)
+}
+
+func setOriginalNetworkIPAddressProperties(d *schema.ResourceData, ipProperties *api.NetworkIPAddress, imported bool) {
+    original := make(map[string]interface{})
+
+    original["dhcp"] = ipProperties.DHCP
+
+    if imported {
+        original["prefix_length"]      = ipProperties.PrefixLength
+        original["skip_as_source"]     = ipProperties.SkipAsSource
+        original["valid_lifetime"]     = flattenNetworkIPAddressLifetime(ipProperties.ValidLifetime)
+        original["preferred_lifetime"] = flattenNetworkIPAddressLifetime(ipProperties.PreferredLifetime)
+    }
+
+    d.Set("original", []interface{}{ original })
+}
+
+//------------------------------------------------------------------------------
+
+func expandNetworkIPAddressProperties(ipProperties *api.NetworkIPAddress, d *schema.ResourceData) {
+    ipProperties.PrefixLength      = uint8(d.Get("prefix_length").(int))
+    ipProperties.SkipAsSource      = d.Get("skip_as_source").(bool)
+    ipProperties.ValidLifetime     = expandNetworkIPAddressLifetime(d.Get("valid_lifetime").(string))
+    ipProperties.PreferredLifetime = expandNetworkIPAddressLifetime(d.Get("preferred_lifetime").(string))
+}
+
+func expandOriginalNetworkIPAddressProperties(ipProperties *api.NetworkIPAddress, d *schema.ResourceData) {
+    original := tfutil.GetResource(d, "original")
+
+    ipProperties.AddressFamily = d.Get("address_family").(string)
+    ipProperties.DHCP, _       = original["dhcp"].(string)
+
+    if v, ok := original["valid_lifetime"].(string); ok && ( v != "" ) {
+        ipProperties.PrefixLength      = uint8(original["prefix_length"].(int))
+        ipProperties.SkipAsSource      = original["skip_as_source"].(bool)
+        ipProperties.ValidLifetime     = expandNetworkIPAddressLifetime(original["valid_lifetime"].(string))
+        ipProperties.PreferredLifetime = expandNetworkIPAddressLifetime(original["preferred_lifetime"].(string))
+    }
+}
+
+//------------------------------------------------------------------------------
+
+func networkIPAddressFamily(ipAddress string) string {
+    if ip := net.ParseIP(ipAddress); ( ip != nil ) && ( ip.To4() != nil ) {
+        return "IPv4"
+    }
+    return "IPv6"
+}
+
+func flattenNetworkIPAddressLifetime(seconds uint32) string {
+    if seconds == api.InfiniteLifetime {
+        return "infinite"
+    }
+    return ( time.Duration(seconds) * time.Second ).String()
+}
+
+func expandNetworkIPAddressLifetime(lifetime string) uint32 {
+    if lifetime == "infinite" {
+        return api.InfiniteLifetime
+    }
+    duration, _ := time.ParseDuration(lifetime)
+    return uint32(duration / time.Second)
+}
+
+func diffSuppressNetworkIPAddressLifetime(k, old, new string, d *schema.ResourceData) bool {
+    if old == new {
+        return true
+    }
+    oldDuration, err1 := time.ParseDuration(old)
+    newDuration, err2 := time.ParseDuration(new)
+    return ( err1 == nil ) && ( err2 == nil ) && ( oldDuration == newDuration )
+}
+
+//------------------------------------------------------------------------------
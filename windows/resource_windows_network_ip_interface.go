@@ -0,0 +1,546 @@
+//
+// Copyright (c) 2019 Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-windows
+//
+package windows
+
+import (
+    "fmt"
+    "log"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+    "github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+
+    "github.com/stefaanc/terraform-provider-windows/api"
+    "github.com/stefaanc/terraform-provider-windows/windows/tfutil"
+)
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsNetworkIPInterface() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "network_adapter_guid": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Computed: true,
+                ForceNew: true,
+
+                ValidateFunc: tfutil.ValidateUUID(),
+                StateFunc: tfutil.StateToUpper(),
+            },
+            "network_adapter_name": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Computed: true,
+                ForceNew: true,
+
+                ConflictsWith: []string{ "network_adapter_guid" },
+            },
+            "address_family": &schema.Schema{
+                Type:     schema.TypeString,
+                Required: true,
+                ForceNew: true,
+
+                ValidateFunc: validation.StringInSlice([]string{ "IPv4", "IPv6" }, false),
+            },
+
+            "dhcp": &schema.Schema{
+                Type:     schema.TypeBool,
+                Optional: true,
+                Computed: true,
+            },
+            "automatic_metric": &schema.Schema{
+                Type:     schema.TypeBool,
+                Optional: true,
+                Computed: true,
+
+                ConflictsWith: []string{ "interface_metric" },   // setting 'interface_metric' disables the automatic metric
+            },
+            "interface_metric": &schema.Schema{
+                Type:     schema.TypeInt,   // uint32
+                Optional: true,
+                Computed: true,
+
+                ConflictsWith: []string{ "automatic_metric" },
+                ValidateFunc: validation.IntBetween(1, 9999),
+            },
+            "nl_mtu": &schema.Schema{
+                Type:     schema.TypeInt,   // uint32
+                Optional: true,
+                Computed: true,
+
+                ValidateFunc: validation.IntBetween(576, 65535),
+            },
+            "forwarding": &schema.Schema{
+                Type:     schema.TypeBool,
+                Optional: true,
+                Computed: true,
+            },
+            "router_discovery": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Computed: true,
+
+                ValidateFunc: validation.StringInSlice([]string{ "Enabled", "Disabled", "ControlledByDHCP" }, false),
+            },
+            "weak_host_send": &schema.Schema{
+                Type:     schema.TypeBool,
+                Optional: true,
+                Computed: true,
+            },
+            "weak_host_receive": &schema.Schema{
+                Type:     schema.TypeBool,
+                Optional: true,
+                Computed: true,
+            },
+
+            "interface_index": &schema.Schema{
+                Type:     schema.TypeInt,   // uint32
+                Computed: true,
+            },
+            "interface_alias": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "connection_state": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+
+            // lifecycle customizations that are not supported by the 'lifecycle' meta-argument for persistent resources (similar to data-sources)
+            "x_lifecycle": &tfutil.DataSourceXLifecycleSchema,
+
+            // used to reset values on terraform destroy
+            "original": &schema.Schema{
+                Type:     schema.TypeList,
+                MaxItems: 1,
+                Computed: true,
+                Elem: resourceWindowsNetworkIPInterfaceOriginal(),
+            },
+        },
+
+        Create: resourceWindowsNetworkIPInterfaceCreate,
+        Read:   resourceWindowsNetworkIPInterfaceRead,
+        Update: resourceWindowsNetworkIPInterfaceUpdate,
+        Delete: resourceWindowsNetworkIPInterfaceDelete,
+    }
+}
+
+func resourceWindowsNetworkIPInterfaceOriginal() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "dhcp": &schema.Schema{
+                Type:     schema.TypeBool,
+                Computed: true,
+            },
+            "automatic_metric": &schema.Schema{
+                Type:     schema.TypeBool,
+                Computed: true,
+            },
+            "interface_metric": &schema.Schema{
+                Type:     schema.TypeInt,
+                Computed: true,
+            },
+            "nl_mtu": &schema.Schema{
+                Type:     schema.TypeInt,
+                Computed: true,
+            },
+            "forwarding": &schema.Schema{
+                Type:     schema.TypeBool,
+                Computed: true,
+            },
+            "router_discovery": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "weak_host_send": &schema.Schema{
+                Type:     schema.TypeBool,
+                Computed: true,
+            },
+            "weak_host_receive": &schema.Schema{
+                Type:     schema.TypeBool,
+                Computed: true,
+            },
+        },
+    }
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsNetworkIPInterfaceCreate(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    networkAdapterGUID := d.Get("network_adapter_guid").(string)
+    networkAdapterName := d.Get("network_adapter_name").(string)
+    addressFamily      := d.Get("address_family").(string)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    var networkAdapterId string
+    if networkAdapterGUID != "" { networkAdapterId = networkAdapterGUID } else
+    if networkAdapterName != "" { networkAdapterId = networkAdapterName }
+    id := fmt.Sprintf("//%s/network_adapters/%s/ip_interfaces/%s", host, networkAdapterId, strings.ToLower(addressFamily))
+
+    log.Printf(`[INFO][terraform-provider-windows] creating windows_network_ip_interface %q
+                    [INFO][terraform-provider-windows]     network_adapter_guid: %#v
+                    [INFO][terraform-provider-windows]     network_adapter_name: %#v
+                    [INFO][terraform-provider-windows]     address_family:       %#v
+                    [INFO][terraform-provider-windows]     dhcp:                 %#v
+                    [INFO][terraform-provider-windows]     automatic_metric:     %#v
+                    [INFO][terraform-provider-windows]     interface_metric:     %#v
+                    [INFO][terraform-provider-windows]     nl_mtu:               %#v
+                    [INFO][terraform-provider-windows]     forwarding:           %#v
+                    [INFO][terraform-provider-windows]     router_discovery:     %#v
+                    [INFO][terraform-provider-windows]     weak_host_send:       %#v
+                    [INFO][terraform-provider-windows]     weak_host_receive:    %#v
+`       ,
+        id,
+        networkAdapterGUID,
+        networkAdapterName,
+        addressFamily,
+        d.Get("dhcp"),
+        d.Get("automatic_metric"),
+        d.Get("interface_metric"),
+        d.Get("nl_mtu"),
+        d.Get("forwarding"),
+        d.Get("router_discovery"),
+        d.Get("weak_host_send"),
+        d.Get("weak_host_receive"),
+    )
+
+    // import
+    log.Printf("[INFO][terraform-provider-windows] importing windows_network_ip_interface %q into terraform state\n", id)
+
+    x_lifecycle := tfutil.GetResource(d, "x_lifecycle")
+
+    niiQuery := new(api.NetworkIPInterface)
+    niiQuery.NetworkAdapterGUID = networkAdapterGUID
+    niiQuery.NetworkAdapterName = networkAdapterName
+    niiQuery.AddressFamily      = addressFamily
+
+    // lifecycle customizations: wait_until_exists
+    var networkIPInterface *api.NetworkIPInterface
+    err := tfutil.WaitUntilExists(x_lifecycle, "cannot find network_", func() (err error) {
+        networkIPInterface, err = c.ReadNetworkIPInterface(niiQuery)
+        return err
+    })
+    if err != nil {
+        // lifecycle customizations: ignore_error_if_not_exists
+        v, ok := x_lifecycle["ignore_error_if_not_exists"]
+        if ok && v.(bool) && strings.Contains(err.Error(), "cannot find network_") {
+            log.Printf("[INFO][terraform-provider-windows] cannot import windows_network_ip_interface %q into terraform state\n", id)
+
+            // set zeroed properties
+            setZeroedNetworkIPInterfaceProperties(d)
+
+            // set computed lifecycle properties
+            x_lifecycle["exists"] = false
+            d.Set("x_lifecycle", []interface{}{ x_lifecycle })
+
+            // set id
+            d.SetId(id)
+
+            log.Printf("[INFO][terraform-provider-windows] ignored error and added zeroed windows_network_ip_interface %q to terraform state\n", id)
+            return nil
+        }
+
+        // no lifecycle customizations
+        log.Printf("[ERROR][terraform-provider-windows] cannot import windows_network_ip_interface %q into terraform state\n", id)
+        return err
+    }
+
+    // set computed lifecycle properties
+    x_lifecycle["exists"] = true
+    d.Set("x_lifecycle", []interface{}{ x_lifecycle })
+
+    // save original config
+    setOriginalNetworkIPInterfaceProperties(d, networkIPInterface)
+
+    // set principal identifying property, so it can be found after a rename of the network adapter
+    d.Set("network_adapter_guid", networkIPInterface.NetworkAdapterGUID)
+    niiQuery.NetworkAdapterGUID = networkIPInterface.NetworkAdapterGUID
+
+    // update
+    niiProperties := new(api.NetworkIPInterface)
+    expandNetworkIPInterfaceProperties(niiProperties, d)
+
+    err = c.UpdateNetworkIPInterface(niiQuery, niiProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows] cannot update windows_network_ip_interface %q\n", id)
+        return err
+    }
+
+    // set id
+    d.SetId(id)
+
+    log.Printf("[INFO][terraform-provider-windows] created windows_network_ip_interface %q\n", id)
+    return resourceWindowsNetworkIPInterfaceRead(d, m)
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsNetworkIPInterfaceRead(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    id := d.Id()
+
+    log.Printf("[INFO][terraform-provider-windows] reading windows_network_ip_interface %q\n", id)
+
+    x_lifecycle := tfutil.GetResource(d, "x_lifecycle")
+
+    // read
+    niiQuery := new(api.NetworkIPInterface)
+    niiQuery.NetworkAdapterGUID = d.Get("network_adapter_guid").(string)
+    niiQuery.NetworkAdapterName = d.Get("network_adapter_name").(string)
+    niiQuery.AddressFamily      = d.Get("address_family").(string)
+
+    networkIPInterface, err := c.ReadNetworkIPInterface(niiQuery)
+    if err != nil {
+        // lifecycle customizations: ignore_error_if_not_exists
+        v, ok := x_lifecycle["ignore_error_if_not_exists"]
+        if ok && v.(bool) && strings.Contains(err.Error(), "cannot find network_") {
+            log.Printf("[INFO][terraform-provider-windows] cannot import windows_network_ip_interface %q into terraform state\n", id)
+
+            // set zeroed properties
+            setZeroedNetworkIPInterfaceProperties(d)
+
+            // set computed lifecycle properties
+            x_lifecycle["exists"] = false
+            d.Set("x_lifecycle", []interface{}{ x_lifecycle })
+
+            // set id
+            d.SetId(id)
+
+            log.Printf("[INFO][terraform-provider-windows] ignored error and added zeroed windows_network_ip_interface %q to terraform state\n", id)
+            return nil
+        }
+
+        // no lifecycle customizations
+        log.Printf("[INFO][terraform-provider-windows] cannot read windows_network_ip_interface %q\n", id)
+
+        // set id
+        d.SetId("")
+
+        log.Printf("[ERROR][terraform-provider-windows] deleted windows_network_ip_interface %q from terraform state\n", id)
+        return nil   // don't return an error to allow terraform refresh to update state
+    }
+
+    // set properties
+    setNetworkIPInterfaceProperties(d, networkIPInterface)
+
+    log.Printf("[INFO][terraform-provider-windows] read windows_network_ip_interface %q\n", id)
+    return nil
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsNetworkIPInterfaceUpdate(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    id := d.Id()
+
+    log.Printf(`[INFO][terraform-provider-windows] updating windows_network_ip_interface %q
+                    [INFO][terraform-provider-windows]     dhcp:              %#v
+                    [INFO][terraform-provider-windows]     automatic_metric:  %#v
+                    [INFO][terraform-provider-windows]     interface_metric:  %#v
+                    [INFO][terraform-provider-windows]     nl_mtu:            %#v
+                    [INFO][terraform-provider-windows]     forwarding:        %#v
+                    [INFO][terraform-provider-windows]     router_discovery:  %#v
+                    [INFO][terraform-provider-windows]     weak_host_send:    %#v
+                    [INFO][terraform-provider-windows]     weak_host_receive: %#v
+`       ,
+        id,
+        d.Get("dhcp"),
+        d.Get("automatic_metric"),
+        d.Get("interface_metric"),
+        d.Get("nl_mtu"),
+        d.Get("forwarding"),
+        d.Get("router_discovery"),
+        d.Get("weak_host_send"),
+        d.Get("weak_host_receive"),
+    )
+
+    // update
+    niiQuery := new(api.NetworkIPInterface)
+    niiQuery.NetworkAdapterGUID = d.Get("network_adapter_guid").(string)
+    niiQuery.AddressFamily      = d.Get("address_family").(string)
+
+    niiProperties := new(api.NetworkIPInterface)
+    expandNetworkIPInterfaceProperties(niiProperties, d)
+
+    err := c.UpdateNetworkIPInterface(niiQuery, niiProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows] cannot update windows_network_ip_interface %q\n", id)
+        return err
+    }
+
+    log.Printf("[INFO][terraform-provider-windows] updated windows_network_ip_interface %q\n", id)
+    return resourceWindowsNetworkIPInterfaceRead(d, m)
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsNetworkIPInterfaceDelete(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    id := d.Id()
+
+    log.Printf("[INFO][terraform-provider-windows] deleting windows_network_ip_interface %q from terraform state\n", id)
+    log.Printf("[INFO][terraform-provider-windows] restore original config for windows_network_ip_interface %q\n", id)
+
+    // restore original config
+    niiQuery := new(api.NetworkIPInterface)
+    niiQuery.NetworkAdapterGUID = d.Get("network_adapter_guid").(string)
+    niiQuery.AddressFamily      = d.Get("address_family").(string)
+
+    niiProperties := new(api.NetworkIPInterface)
+    expandOriginalNetworkIPInterfaceProperties(niiProperties, d)
+
+    err := c.UpdateNetworkIPInterface(niiQuery, niiProperties)
+    if err != nil {
+        log.Printf("[WARNING][terraform-provider-windows] cannot restore original config for windows_network_ip_interface %q\n", id)
+    }
+
+    // set id
+    d.SetId("")
+
+    log.Printf("[INFO][terraform-provider-windows] deleted windows_network_ip_interface %q from terraform state\n", id)
+    return nil
+}
+
+//------------------------------------------------------------------------------
+
+func setNetworkIPInterfaceProperties(d *schema.ResourceData, niiProperties *api.NetworkIPInterface) {
+    d.Set("network_adapter_guid", niiProperties.NetworkAdapterGUID)
+    d.Set("network_adapter_name", niiProperties.NetworkAdapterName)
+    d.Set("address_family", niiProperties.AddressFamily)
+
+    d.Set("dhcp", niiProperties.DHCP)
+    d.Set("automatic_metric", niiProperties.AutomaticMetric)
+    d.Set("interface_metric", niiProperties.InterfaceMetric)
+    d.Set("nl_mtu", niiProperties.NlMTU)
+    d.Set("forwarding", niiProperties.Forwarding)
+    d.Set("router_discovery", niiProperties.RouterDiscovery)
+    d.Set("weak_host_send", niiProperties.WeakHostSend)
+    d.Set("weak_host_receive", niiProperties.WeakHostReceive)
+
+    d.Set("interface_index", niiProperties.InterfaceIndex)
+    d.Set("interface_alias", niiProperties.InterfaceAlias)
+    d.Set("connection_state", niiProperties.ConnectionState)
+}
+
+func setZeroedNetworkIPInterfaceProperties(d *schema.ResourceData) {
+    d.Set("network_adapter_guid", "")
+    d.Set("network_adapter_name", "")
+    d.Set("address_family", "")
+
+    d.Set("dhcp", false)
+    d.Set("automatic_metric", false)
+    d.Set("interface_metric", 0)
+    d.Set("nl_mtu", 0)
+    d.Set("forwarding", false)
+    d.Set("router_discovery", "")
+    d.Set("weak_host_send", false)
+    d.Set("weak_host_receive", false)
+
+    d.Set("interface_index", 0)
+    d.Set("interface_alias", "")
+    d.Set("connection_state", "")
+}
+
+func setOriginalNetworkIPInterfaceProperties(d *schema.ResourceData, niiProperties *api.NetworkIPInterface) {
+    original := make(map[string]interface{})
+
+    original["dhcp"]              = niiProperties.DHCP
+    original["automatic_metric"]  = niiProperties.AutomaticMetric
+    original["interface_metric"]  = niiProperties.InterfaceMetric
+    original["nl_mtu"]            = niiProperties.NlMTU
+    original["forwarding"]        = niiProperties.Forwarding
+    original["router_discovery"]  = niiProperties.RouterDiscovery
+    original["weak_host_send"]    = niiProperties.WeakHostSend
+    original["weak_host_receive"] = niiProperties.WeakHostReceive
+
+    d.Set("original", []interface{}{ original })
+}
+
+//------------------------------------------------------------------------------
+
+func expandNetworkIPInterfaceProperties(niiProperties *api.NetworkIPInterface, d *schema.ResourceData) {
+    // when the resource is being created, the attributes that are not defined in config have no state yet
+    // we use the previously read original properties to get the current value (using the zero-value would overwrite the current value)
+    original := tfutil.GetResource(d, "original")
+
+    if v, ok := d.GetOkExists("dhcp"); ok {
+        niiProperties.DHCP = v.(bool)
+    } else {
+        niiProperties.DHCP = original["dhcp"].(bool)
+    }
+
+    if v, ok := d.GetOkExists("automatic_metric"); ok {
+        niiProperties.AutomaticMetric = v.(bool)
+    } else {
+        niiProperties.AutomaticMetric = original["automatic_metric"].(bool)
+    }
+    if d.HasChange("interface_metric") && !d.HasChange("automatic_metric") {
+        // setting an explicit interface metric disables the automatic metric
+        niiProperties.AutomaticMetric = false
+    }
+
+    if v, ok := d.GetOkExists("interface_metric"); ok {
+        niiProperties.InterfaceMetric = uint32(v.(int))
+    } else {
+        niiProperties.InterfaceMetric = uint32(original["interface_metric"].(int))
+    }
+
+    if v, ok := d.GetOkExists("nl_mtu"); ok {
+        niiProperties.NlMTU = uint32(v.(int))
+    } else {
+        niiProperties.NlMTU = uint32(original["nl_mtu"].(int))
+    }
+
+    if v, ok := d.GetOkExists("forwarding"); ok {
+        niiProperties.Forwarding = v.(bool)
+    } else {
+        niiProperties.Forwarding = original["forwarding"].(bool)
+    }
+
+    if v, ok := d.GetOkExists("router_discovery"); ok {
+        niiProperties.RouterDiscovery = v.(string)
+    } else {
+        niiProperties.RouterDiscovery = original["router_discovery"].(string)
+    }
+
+    if v, ok := d.GetOkExists("weak_host_send"); ok {
+        niiProperties.WeakHostSend = v.(bool)
+    } else {
+        niiProperties.WeakHostSend = original["weak_host_send"].(bool)
+    }
+
+    if v, ok := d.GetOkExists("weak_host_receive"); ok {
+        niiProperties.WeakHostReceive = v.(bool)
+    } else {
+        niiProperties.WeakHostReceive = original["weak_host_receive"].(bool)
+    }
+}
+
+func expandOriginalNetworkIPInterfaceProperties(niiProperties *api.NetworkIPInterface, d *schema.ResourceData) {
+    original := tfutil.GetResource(d, "original")
+
+    niiProperties.DHCP            = original["dhcp"].(bool)
+    niiProperties.AutomaticMetric = original["automatic_metric"].(bool)
+    niiProperties.InterfaceMetric = uint32(original["interface_metric"].(int))
+    niiProperties.NlMTU           = uint32(original["nl_mtu"].(int))
+    niiProperties.Forwarding      = original["forwarding"].(bool)
+    niiProperties.RouterDiscovery = original["router_discovery"].(string)
+    niiProperties.WeakHostSend    = original["weak_host_send"].(bool)
+    niiProperties.WeakHostReceive = original["weak_host_receive"].(bool)
+}
+
+//------------------------------------------------------------------------------
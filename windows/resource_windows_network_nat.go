@@ -0,0 +1,418 @@
+//
+// Copyright (c) 2019 Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-windows
+//
+package windows
+
+import (
+    "fmt"
+    "log"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+    "github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+
+    "github.com/stefaanc/terraform-provider-windows/api"
+    "github.com/stefaanc/terraform-provider-windows/windows/tfutil"
+)
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsNetworkNAT() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "name": &schema.Schema{
+                Type:     schema.TypeString,
+                Required: true,
+                ForceNew: true,
+            },
+            "internal_ip_interface_address_prefix": &schema.Schema{
+                Type:     schema.TypeString,
+                Required: true,
+                ForceNew: true,
+
+                ValidateFunc: validation.CIDRNetwork(0, 32),
+            },
+
+            "static_mapping": &schema.Schema{
+                Type:     schema.TypeSet,
+                Optional: true,
+                Elem: resourceWindowsNetworkNATStaticMapping(),
+            },
+
+            "active": &schema.Schema{
+                Type:     schema.TypeBool,
+                Computed: true,
+            },
+
+            // lifecycle customizations that are not supported by the 'lifecycle' meta-argument
+            "x_lifecycle": &tfutil.ResourceXLifecycleSchema,
+
+            // used to reset values on terraform destroy
+            "original": &schema.Schema{
+                Type:     schema.TypeList,
+                MaxItems: 1,
+                Computed: true,
+                Elem: resourceWindowsNetworkNATOriginal(),
+            },
+        },
+
+        Create: resourceWindowsNetworkNATCreate,
+        Read:   resourceWindowsNetworkNATRead,
+        Update: resourceWindowsNetworkNATUpdate,
+        Delete: resourceWindowsNetworkNATDelete,
+    }
+}
+
+func resourceWindowsNetworkNATStaticMapping() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "protocol": &schema.Schema{
+                Type:     schema.TypeString,
+                Required: true,
+
+                ValidateFunc: validation.StringInSlice([]string{ "TCP", "UDP" }, false),
+            },
+            "external_ip_address": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Default:  "0.0.0.0",
+
+                ValidateFunc: validation.SingleIP(),
+            },
+            "external_port": &schema.Schema{
+                Type:     schema.TypeInt,   // uint16
+                Required: true,
+
+                ValidateFunc: validation.IntBetween(1, 65535),
+            },
+            "internal_ip_address": &schema.Schema{
+                Type:     schema.TypeString,
+                Required: true,
+
+                ValidateFunc: validation.SingleIP(),
+            },
+            "internal_port": &schema.Schema{
+                Type:     schema.TypeInt,   // uint16
+                Required: true,
+
+                ValidateFunc: validation.IntBetween(1, 65535),
+            },
+        },
+    }
+}
+
+func resourceWindowsNetworkNATOriginal() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "static_mapping": &schema.Schema{
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem: &schema.Resource{
+                    Schema: map[string]*schema.Schema{
+                        "protocol": &schema.Schema{
+                            Type:     schema.TypeString,
+                            Computed: true,
+                        },
+                        "external_ip_address": &schema.Schema{
+                            Type:     schema.TypeString,
+                            Computed: true,
+                        },
+                        "external_port": &schema.Schema{
+                            Type:     schema.TypeInt,
+                            Computed: true,
+                        },
+                        "internal_ip_address": &schema.Schema{
+                            Type:     schema.TypeString,
+                            Computed: true,
+                        },
+                        "internal_port": &schema.Schema{
+                            Type:     schema.TypeInt,
+                            Computed: true,
+                        },
+                    },
+                },
+            },
+        },
+    }
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsNetworkNATCreate(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    name                             := d.Get("name").(string)
+    internalIPInterfaceAddressPrefix := d.Get("internal_ip_interface_address_prefix").(string)
+    staticMappings                   := tfutil.GetSetOfResources(d, "static_mapping")
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    id := fmt.Sprintf("//%s/network_nats/%s", host, name)
+
+    log.Printf(`[INFO][terraform-provider-windows] creating windows_network_nat %q
+                    [INFO][terraform-provider-windows]     name:                                 %#v
+                    [INFO][terraform-provider-windows]     internal_ip_interface_address_prefix: %#v
+                    [INFO][terraform-provider-windows]     static_mapping:                       %#v
+`       ,
+        id,
+        name,
+        internalIPInterfaceAddressPrefix,
+        staticMappings,
+    )
+
+    x_lifecycle := tfutil.GetResource(d, "x_lifecycle")
+
+    nnQuery := new(api.NetworkNAT)
+    nnQuery.Name = name
+
+    networkNAT, err := c.ReadNetworkNAT(nnQuery)
+    if err == nil {
+        // lifecycle customizations: import_if_exists
+        v, ok := x_lifecycle["import_if_exists"]
+        if !ok || !v.(bool) {
+            log.Printf("[ERROR][terraform-provider-windows] cannot create windows_network_nat %q\n", id)
+            return fmt.Errorf("[ERROR][terraform-provider-windows] cannot create windows_network_nat %q, nat already exists", id)
+        }
+        if networkNAT.InternalIPInterfaceAddressPrefix != internalIPInterfaceAddressPrefix {
+            log.Printf("[ERROR][terraform-provider-windows] cannot import windows_network_nat %q\n", id)
+            return fmt.Errorf("[ERROR][terraform-provider-windows] cannot import windows_network_nat %q, existing nat has internal_ip_interface_address_prefix %q", id, networkNAT.InternalIPInterfaceAddressPrefix)
+        }
+
+        log.Printf("[INFO][terraform-provider-windows] importing windows_network_nat %q into terraform state\n", id)
+
+        // set computed lifecycle properties
+        x_lifecycle["imported"] = true
+        d.Set("x_lifecycle", []interface{}{ x_lifecycle })
+
+        // save original config
+        setOriginalNetworkNATProperties(d, networkNAT)
+
+        // update
+        nnProperties := new(api.NetworkNAT)
+        expandNetworkNATProperties(nnProperties, d)
+
+        err := c.UpdateNetworkNAT(nnQuery, nnProperties)
+        if err != nil {
+            log.Printf("[ERROR][terraform-provider-windows] cannot update windows_network_nat %q\n", id)
+            return err
+        }
+
+        // set id
+        d.SetId(id)
+
+        log.Printf("[INFO][terraform-provider-windows] created windows_network_nat %q\n", id)
+        return resourceWindowsNetworkNATRead(d, m)
+    }
+    if !strings.Contains(err.Error(), "cannot find network_nat") {
+        log.Printf("[ERROR][terraform-provider-windows] cannot create windows_network_nat %q\n", id)
+        return err
+    }
+
+    // set computed lifecycle properties
+    x_lifecycle["imported"] = false
+    d.Set("x_lifecycle", []interface{}{ x_lifecycle })
+
+    // create
+    nnProperties := new(api.NetworkNAT)
+    nnProperties.Name                             = name
+    nnProperties.InternalIPInterfaceAddressPrefix = internalIPInterfaceAddressPrefix
+    expandNetworkNATProperties(nnProperties, d)
+
+    err = c.CreateNetworkNAT(nnProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows] cannot create windows_network_nat %q\n", id)
+        return err
+    }
+
+    // set id
+    d.SetId(id)
+
+    log.Printf("[INFO][terraform-provider-windows] created windows_network_nat %q\n", id)
+    return resourceWindowsNetworkNATRead(d, m)
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsNetworkNATRead(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    id := d.Id()
+
+    log.Printf("[INFO][terraform-provider-windows] reading windows_network_nat %q\n", id)
+
+    // read
+    nnQuery := new(api.NetworkNAT)
+    nnQuery.Name = d.Get("name").(string)
+
+    networkNAT, err := c.ReadNetworkNAT(nnQuery)
+    if err != nil {
+        if strings.Contains(err.Error(), "cannot find network_nat") {
+            log.Printf("[INFO][terraform-provider-windows] cannot find windows_network_nat %q\n", id)
+
+            // set id
+            d.SetId("")
+
+            log.Printf("[INFO][terraform-provider-windows] deleted windows_network_nat %q from terraform state\n", id)
+            return nil   // don't return an error to allow terraform refresh to update state
+        }
+
+        log.Printf("[ERROR][terraform-provider-windows] cannot read windows_network_nat %q\n", id)
+        return err
+    }
+
+    // set properties
+    setNetworkNATProperties(d, networkNAT)
+
+    log.Printf("[INFO][terraform-provider-windows] read windows_network_nat %q\n", id)
+    return nil
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsNetworkNATUpdate(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    id             := d.Id()
+    staticMappings := tfutil.GetSetOfResources(d, "static_mapping")
+
+    log.Printf(`[INFO][terraform-provider-windows] updating windows_network_nat %q
+                    [INFO][terraform-provider-windows]     static_mapping: %#v
+`       ,
+        id,
+        staticMappings,
+    )
+
+    // update
+    nnQuery := new(api.NetworkNAT)
+    nnQuery.Name = d.Get("name").(string)
+
+    nnProperties := new(api.NetworkNAT)
+    expandNetworkNATProperties(nnProperties, d)
+
+    err := c.UpdateNetworkNAT(nnQuery, nnProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows] cannot update windows_network_nat %q\n", id)
+        return err
+    }
+
+    log.Printf("[INFO][terraform-provider-windows] updated windows_network_nat %q\n", id)
+    return resourceWindowsNetworkNATRead(d, m)
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsNetworkNATDelete(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    id := d.Id()
+
+    x_lifecycle := tfutil.GetResource(d, "x_lifecycle")
+
+    nnQuery := new(api.NetworkNAT)
+    nnQuery.Name = d.Get("name").(string)
+
+    // lifecycle customizations: destroy_if_imported
+    imported, _          := x_lifecycle["imported"].(bool)
+    destroyIfImported, _ := x_lifecycle["destroy_if_imported"].(bool)
+    if imported && !destroyIfImported {
+        log.Printf("[INFO][terraform-provider-windows] deleting windows_network_nat %q from terraform state\n", id)
+        log.Printf("[INFO][terraform-provider-windows] restore original config for windows_network_nat %q\n", id)
+
+        // restore original config
+        nnProperties := new(api.NetworkNAT)
+        expandOriginalNetworkNATProperties(nnProperties, d)
+
+        err := c.UpdateNetworkNAT(nnQuery, nnProperties)
+        if err != nil {
+            log.Printf("[WARNING][terraform-provider-windows] cannot restore original config for windows_network_nat %q\n", id)
+        }
+
+        // set id
+        d.SetId("")
+
+        log.Printf("[INFO][terraform-provider-windows] deleted windows_network_nat %q from terraform state\n", id)
+        return nil
+    }
+
+    log.Printf("[INFO][terraform-provider-windows] deleting windows_network_nat %q\n", id)
+
+    // delete
+    err := c.DeleteNetworkNAT(nnQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows] cannot delete windows_network_nat %q\n", id)
+        return err
+    }
+
+    // set id
+    d.SetId("")
+
+    log.Printf("[INFO][terraform-provider-windows] deleted windows_network_nat %q\n", id)
+    return nil
+}
+
+//------------------------------------------------------------------------------
+
+func setNetworkNATProperties(d *schema.ResourceData, nnProperties *api.NetworkNAT) {
+    d.Set("name", nnProperties.Name)
+    d.Set("internal_ip_interface_address_prefix", nnProperties.InternalIPInterfaceAddressPrefix)
+
+    d.Set("static_mapping", flattenNetworkNATStaticMappings(nnProperties.StaticMappings))
+
+    d.Set("active", nnProperties.Active)
+}
+
+func setOriginalNetworkNATProperties(d *schema.ResourceData, nnProperties *api.NetworkNAT) {
+    original := make(map[string]interface{})
+
+    original["static_mapping"] = flattenNetworkNATStaticMappings(nnProperties.StaticMappings)
+
+    d.Set("original", []interface{}{ original })
+}
+
+func flattenNetworkNATStaticMappings(smPropertiesList []api.NetworkNATStaticMapping) []interface{} {
+    staticMappings := make([]interface{}, 0, len(smPropertiesList))
+    for _, smProperties := range smPropertiesList {
+        staticMapping := make(map[string]interface{})
+        staticMapping["protocol"]            = smProperties.Protocol
+        staticMapping["external_ip_address"] = smProperties.ExternalIPAddress
+        staticMapping["external_port"]       = int(smProperties.ExternalPort)
+        staticMapping["internal_ip_address"] = smProperties.InternalIPAddress
+        staticMapping["internal_port"]       = int(smProperties.InternalPort)
+        staticMappings = append(staticMappings, staticMapping)
+    }
+    return staticMappings
+}
+
+//------------------------------------------------------------------------------
+
+func expandNetworkNATProperties(nnProperties *api.NetworkNAT, d *schema.ResourceData) {
+    nnProperties.StaticMappings = expandNetworkNATStaticMappings(tfutil.GetSetOfResources(d, "static_mapping"))
+}
+
+func expandOriginalNetworkNATProperties(nnProperties *api.NetworkNAT, d *schema.ResourceData) {
+    original := tfutil.GetResource(d, "original")
+
+    nnProperties.StaticMappings = expandNetworkNATStaticMappings(tfutil.ExpandListOfResources(original, "static_mapping"))
+}
+
+func expandNetworkNATStaticMappings(staticMappings []map[string]interface{}) []api.NetworkNATStaticMapping {
+    smPropertiesList := make([]api.NetworkNATStaticMapping, 0, len(staticMappings))
+    for _, staticMapping := range staticMappings {
+        smPropertiesList = append(smPropertiesList, api.NetworkNATStaticMapping{
+            Protocol:          staticMapping["protocol"].(string),
+            ExternalIPAddress: staticMapping["external_ip_address"].(string),
+            ExternalPort:      uint16(staticMapping["external_port"].(int)),
+            InternalIPAddress: staticMapping["internal_ip_address"].(string),
+            InternalPort:      uint16(staticMapping["internal_port"].(int)),
+        })
+    }
+    return smPropertiesList
+}
+
+//------------------------------------------------------------------------------
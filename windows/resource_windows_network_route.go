@@ -0,0 +1,415 @@
+//
+// Copyright (c) 2019 Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-windows
+//
+package windows
+
+import (
+    "fmt"
+    "log"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+    "github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+
+    "github.com/stefaanc/terraform-provider-windows/api"
+    "github.com/stefaanc/terraform-provider-windows/windows/tfutil"
+)
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsNetworkRoute() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "destination_prefix": &schema.Schema{
+                Type:     schema.TypeString,
+                Required: true,
+                ForceNew: true,
+
+                ValidateFunc: validation.CIDRNetwork(0, 128),
+                StateFunc: tfutil.StateToLower(),
+            },
+            "next_hop": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Computed: true,
+                ForceNew: true,
+
+                ValidateFunc: validation.SingleIP(),
+                StateFunc: tfutil.StateToLower(),
+            },
+
+            "network_adapter_guid": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Computed: true,
+                ForceNew: true,
+
+                ValidateFunc: tfutil.ValidateUUID(),
+                StateFunc: tfutil.StateToUpper(),
+            },
+            "network_adapter_name": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Computed: true,
+                ForceNew: true,
+
+                ConflictsWith: []string{ "network_adapter_guid" },
+            },
+
+            "route_metric": &schema.Schema{
+                Type:     schema.TypeInt,   // uint16
+                Optional: true,
+                Default:  256,
+
+                ValidateFunc: validation.IntBetween(0, 9999),
+            },
+            "policy_store": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Default:  "PersistentStore",
+                ForceNew: true,
+
+                ValidateFunc: validation.StringInSlice([]string{ "PersistentStore", "ActiveStore" }, false),
+            },
+
+            "interface_index": &schema.Schema{
+                Type:     schema.TypeInt,   // uint32
+                Computed: true,
+            },
+            "address_family": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "protocol": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+
+            // lifecycle customizations that are not supported by the 'lifecycle' meta-argument
+            "x_lifecycle": &tfutil.ResourceXLifecycleSchema,
+
+            // used to reset values on terraform destroy
+            "original": &schema.Schema{
+                Type:     schema.TypeList,
+                MaxItems: 1,
+                Computed: true,
+                Elem: &schema.Resource{
+                    Schema: map[string]*schema.Schema{
+                        "route_metric": &schema.Schema{
+                            Type:     schema.TypeInt,
+                            Computed: true,
+                        },
+                    },
+                },
+            },
+        },
+
+        Create: resourceWindowsNetworkRouteCreate,
+        Read:   resourceWindowsNetworkRouteRead,
+        Update: resourceWindowsNetworkRouteUpdate,
+        Delete: resourceWindowsNetworkRouteDelete,
+    }
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsNetworkRouteCreate(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    destinationPrefix  := d.Get("destination_prefix").(string)
+    nextHop            := d.Get("next_hop").(string)
+    networkAdapterGUID := d.Get("network_adapter_guid").(string)
+    networkAdapterName := d.Get("network_adapter_name").(string)
+    routeMetric        := d.Get("route_metric").(int)
+    policyStore        := d.Get("policy_store").(string)
+
+    if nextHop == "" {
+        // on-link route
+        if networkIPAddressFamily(strings.Split(destinationPrefix, "/")[0]) == "IPv4" {
+            nextHop = "0.0.0.0"
+        } else {
+            nextHop = "::"
+        }
+        d.Set("next_hop", nextHop)
+    }
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    var networkAdapterId string
+    if networkAdapterGUID != "" { networkAdapterId = networkAdapterGUID } else
+    if networkAdapterName != "" { networkAdapterId = networkAdapterName }
+    id := fmt.Sprintf("//%s/network_adapters/%s/routes/%s/%s", host, networkAdapterId, strings.ToLower(destinationPrefix), strings.ToLower(nextHop))
+
+    log.Printf(`[INFO][terraform-provider-windows] creating windows_network_route %q
+                    [INFO][terraform-provider-windows]     destination_prefix:   %#v
+                    [INFO][terraform-provider-windows]     next_hop:             %#v
+                    [INFO][terraform-provider-windows]     network_adapter_guid: %#v
+                    [INFO][terraform-provider-windows]     network_adapter_name: %#v
+                    [INFO][terraform-provider-windows]     route_metric:         %#v
+                    [INFO][terraform-provider-windows]     policy_store:         %#v
+`       ,
+        id,
+        destinationPrefix,
+        nextHop,
+        networkAdapterGUID,
+        networkAdapterName,
+        routeMetric,
+        policyStore,
+    )
+
+    x_lifecycle := tfutil.GetResource(d, "x_lifecycle")
+
+    nrQuery := new(api.NetworkRoute)
+    nrQuery.DestinationPrefix  = destinationPrefix
+    nrQuery.NextHop            = nextHop
+    nrQuery.NetworkAdapterGUID = networkAdapterGUID
+    nrQuery.NetworkAdapterName = networkAdapterName
+
+    networkRoute, err := c.ReadNetworkRoute(nrQuery)
+    if err == nil {
+        // lifecycle customizations: import_if_exists
+        v, ok := x_lifecycle["import_if_exists"]
+        if !ok || !v.(bool) {
+            log.Printf("[ERROR][terraform-provider-windows] cannot create windows_network_route %q\n", id)
+            return fmt.Errorf("[ERROR][terraform-provider-windows] cannot create windows_network_route %q, route already exists", id)
+        }
+
+        log.Printf("[INFO][terraform-provider-windows] importing windows_network_route %q into terraform state\n", id)
+
+        // set computed lifecycle properties
+        x_lifecycle["imported"] = true
+        d.Set("x_lifecycle", []interface{}{ x_lifecycle })
+
+        // save original config
+        setOriginalNetworkRouteProperties(d, networkRoute)
+
+        // set principal identifying property
+        d.Set("network_adapter_guid", networkRoute.NetworkAdapterGUID)
+        nrQuery.NetworkAdapterGUID = networkRoute.NetworkAdapterGUID
+
+        // update
+        nrProperties := new(api.NetworkRoute)
+        expandNetworkRouteProperties(nrProperties, d)
+
+        err := c.UpdateNetworkRoute(nrQuery, nrProperties)
+        if err != nil {
+            log.Printf("[ERROR][terraform-provider-windows] cannot update windows_network_route %q\n", id)
+            return err
+        }
+
+        // set id
+        d.SetId(id)
+
+        log.Printf("[INFO][terraform-provider-windows] created windows_network_route %q\n", id)
+        return resourceWindowsNetworkRouteRead(d, m)
+    }
+    if !strings.Contains(err.Error(), "cannot find network_route") {
+        log.Printf("[ERROR][terraform-provider-windows] cannot create windows_network_route %q\n", id)
+        return err
+    }
+
+    // set computed lifecycle properties
+    x_lifecycle["imported"] = false
+    d.Set("x_lifecycle", []interface{}{ x_lifecycle })
+
+    // create
+    nrProperties := new(api.NetworkRoute)
+    nrProperties.DestinationPrefix  = destinationPrefix
+    nrProperties.NextHop            = nextHop
+    nrProperties.NetworkAdapterGUID = networkAdapterGUID
+    nrProperties.NetworkAdapterName = networkAdapterName
+    nrProperties.PolicyStore        = policyStore
+    expandNetworkRouteProperties(nrProperties, d)
+
+    err = c.CreateNetworkRoute(nrProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows] cannot create windows_network_route %q\n", id)
+        return err
+    }
+
+    // set id
+    d.SetId(id)
+
+    // read principal identifying property, so it can be found after a rename of the network adapter
+    networkRoute, err = c.ReadNetworkRoute(nrQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows] cannot read windows_network_route %q\n", id)
+        return err
+    }
+    d.Set("network_adapter_guid", networkRoute.NetworkAdapterGUID)
+
+    log.Printf("[INFO][terraform-provider-windows] created windows_network_route %q\n", id)
+    return resourceWindowsNetworkRouteRead(d, m)
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsNetworkRouteRead(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    id := d.Id()
+
+    log.Printf("[INFO][terraform-provider-windows] reading windows_network_route %q\n", id)
+
+    // read
+    nrQuery := new(api.NetworkRoute)
+    nrQuery.DestinationPrefix  = d.Get("destination_prefix").(string)
+    nrQuery.NextHop            = d.Get("next_hop").(string)
+    nrQuery.NetworkAdapterGUID = d.Get("network_adapter_guid").(string)
+    nrQuery.NetworkAdapterName = d.Get("network_adapter_name").(string)
+
+    networkRoute, err := c.ReadNetworkRoute(nrQuery)
+    if err != nil {
+        // the route is gone when its network adapter is gone
+        if strings.Contains(err.Error(), "cannot find network_route") || strings.Contains(err.Error(), "cannot find network_adapter") {
+            log.Printf("[INFO][terraform-provider-windows] cannot find windows_network_route %q\n", id)
+
+            // set id
+            d.SetId("")
+
+            log.Printf("[INFO][terraform-provider-windows] deleted windows_network_route %q from terraform state\n", id)
+            return nil   // don't return an error to allow terraform refresh to update state
+        }
+
+        log.Printf("[ERROR][terraform-provider-windows] cannot read windows_network_route %q\n", id)
+        return err
+    }
+
+    // set properties
+    setNetworkRouteProperties(d, networkRoute)
+
+    log.Printf("[INFO][terraform-provider-windows] read windows_network_route %q\n", id)
+    return nil
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsNetworkRouteUpdate(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    id          := d.Id()
+    routeMetric := d.Get("route_metric").(int)
+
+    log.Printf(`[INFO][terraform-provider-windows] updating windows_network_route %q
+                    [INFO][terraform-provider-windows]     route_metric: %#v
+`       ,
+        id,
+        routeMetric,
+    )
+
+    // update
+    nrQuery := new(api.NetworkRoute)
+    nrQuery.DestinationPrefix  = d.Get("destination_prefix").(string)
+    nrQuery.NextHop            = d.Get("next_hop").(string)
+    nrQuery.NetworkAdapterGUID = d.Get("network_adapter_guid").(string)
+
+    nrProperties := new(api.NetworkRoute)
+    expandNetworkRouteProperties(nrProperties, d)
+
+    err := c.UpdateNetworkRoute(nrQuery, nrProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows] cannot update windows_network_route %q\n", id)
+        return err
+    }
+
+    log.Printf("[INFO][terraform-provider-windows] updated windows_network_route %q\n", id)
+    return resourceWindowsNetworkRouteRead(d, m)
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsNetworkRouteDelete(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    id := d.Id()
+
+    x_lifecycle := tfutil.GetResource(d, "x_lifecycle")
+
+    nrQuery := new(api.NetworkRoute)
+    nrQuery.DestinationPrefix  = d.Get("destination_prefix").(string)
+    nrQuery.NextHop            = d.Get("next_hop").(string)
+    nrQuery.NetworkAdapterGUID = d.Get("network_adapter_guid").(string)
+
+    // lifecycle customizations: destroy_if_imported
+    imported, _          := x_lifecycle["imported"].(bool)
+    destroyIfImported, _ := x_lifecycle["destroy_if_imported"].(bool)
+    if imported && !destroyIfImported {
+        log.Printf("[INFO][terraform-provider-windows] deleting windows_network_route %q from terraform state\n", id)
+        log.Printf("[INFO][terraform-provider-windows] restore original config for windows_network_route %q\n", id)
+
+        // restore original config
+        nrProperties := new(api.NetworkRoute)
+        expandOriginalNetworkRouteProperties(nrProperties, d)
+
+        err := c.UpdateNetworkRoute(nrQuery, nrProperties)
+        if err != nil {
+            log.Printf("[WARNING][terraform-provider-windows] cannot restore original config for windows_network_route %q\n", id)
+        }
+
+        // set id
+        d.SetId("")
+
+        log.Printf("[INFO][terraform-provider-windows] deleted windows_network_route %q from terraform state\n", id)
+        return nil
+    }
+
+    log.Printf("[INFO][terraform-provider-windows] deleting windows_network_route %q\n", id)
+
+    // delete
+    err := c.DeleteNetworkRoute(nrQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows] cannot delete windows_network_route %q\n", id)
+        return err
+    }
+
+    // set id
+    d.SetId("")
+
+    log.Printf("[INFO][terraform-provider-windows] deleted windows_network_route %q\n", id)
+    return nil
+}
+
+//------------------------------------------------------------------------------
+
+func setNetworkRouteProperties(d *schema.ResourceData, nrProperties *api.NetworkRoute) {
+    d.Set("destination_prefix", nrProperties.DestinationPrefix)
+    d.Set("next_hop", nrProperties.NextHop)
+
+    d.Set("network_adapter_guid", nrProperties.NetworkAdapterGUID)
+    d.Set("network_adapter_name", nrProperties.NetworkAdapterName)
+
+    d.Set("route_metric", nrProperties.RouteMetric)
+    d.Set("policy_store", nrProperties.PolicyStore)
+
+    d.Set("interface_index", nrProperties.InterfaceIndex)
+    d.Set("address_family", nrProperties.AddressFamily)
+    d.Set("protocol", nrProperties.Protocol)
+}
+
+func setOriginalNetworkRouteProperties(d *schema.ResourceData, nrProperties *api.NetworkRoute) {
+    original := make(map[string]interface{})
+
+    original["route_metric"] = nrProperties.RouteMetric
+
+    d.Set("original", []interface{}{ original })
+}
+
+//------------------------------------------------------------------------------
+
+func expandNetworkRouteProperties(nrProperties *api.NetworkRoute, d *schema.ResourceData) {
+    nrProperties.RouteMetric = uint16(d.Get("route_metric").(int))
+}
+
+func expandOriginalNetworkRouteProperties(nrProperties *api.NetworkRoute, d *schema.ResourceData) {
+    original := tfutil.GetResource(d, "original")
+
+    nrProperties.RouteMetric = uint16(original["route_metric"].(int))
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,455 @@
+//
+// Copyright (c) 2019 Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-windows
+//
+package windows
+
+import (
+    "fmt"
+    "log"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+    "github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+
+    "github.com/stefaanc/terraform-provider-windows/api"
+    "github.com/stefaanc/terraform-provider-windows/windows/tfutil"
+)
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsNetworkTeam() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "name": &schema.Schema{
+                Type:     schema.TypeString,
+                Required: true,
+                ForceNew: true,
+            },
+
+            "members": &schema.Schema{
+                Type:     schema.TypeSet,
+                Required: true,
+                MinItems: 1,
+                Elem: &schema.Schema{
+                    Type: schema.TypeString,
+                },
+            },
+            "teaming_mode": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Default:  "SwitchIndependent",
+
+                ValidateFunc: validation.StringInSlice([]string{ "SwitchIndependent", "LACP", "Static" }, false),
+            },
+            "load_balancing_algorithm": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Default:  "Dynamic",
+
+                ValidateFunc: validation.StringInSlice([]string{ "Dynamic", "TransportPorts", "IPAddresses", "MacAddresses", "HyperVPort" }, false),
+            },
+            "standby_member": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Default:  "",
+            },
+            "team_nic": &schema.Schema{
+                Type:     schema.TypeSet,
+                Optional: true,
+                Elem: resourceWindowsNetworkTeamTeamNIC(),
+            },
+
+            "primary_team_nic_name": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "status": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+
+            // lifecycle customizations that are not supported by the 'lifecycle' meta-argument
+            "x_lifecycle": &tfutil.ResourceXLifecycleSchema,
+
+            // used to reset values on terraform destroy
+            "original": &schema.Schema{
+                Type:     schema.TypeList,
+                MaxItems: 1,
+                Computed: true,
+                Elem: resourceWindowsNetworkTeamOriginal(),
+            },
+        },
+
+        Create: resourceWindowsNetworkTeamCreate,
+        Read:   resourceWindowsNetworkTeamRead,
+        Update: resourceWindowsNetworkTeamUpdate,
+        Delete: resourceWindowsNetworkTeamDelete,
+    }
+}
+
+func resourceWindowsNetworkTeamTeamNIC() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "name": &schema.Schema{
+                Type:     schema.TypeString,
+                Required: true,
+            },
+            "vlan_id": &schema.Schema{
+                Type:     schema.TypeInt,   // uint16
+                Required: true,
+
+                ValidateFunc: validation.IntBetween(1, 4094),
+            },
+        },
+    }
+}
+
+func resourceWindowsNetworkTeamOriginal() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "members": &schema.Schema{
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem: &schema.Schema{
+                    Type: schema.TypeString,
+                },
+            },
+            "teaming_mode": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "load_balancing_algorithm": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "standby_member": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "team_nic": &schema.Schema{
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem: &schema.Resource{
+                    Schema: map[string]*schema.Schema{
+                        "name": &schema.Schema{
+                            Type:     schema.TypeString,
+                            Computed: true,
+                        },
+                        "vlan_id": &schema.Schema{
+                            Type:     schema.TypeInt,
+                            Computed: true,
+                        },
+                    },
+                },
+            },
+        },
+    }
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsNetworkTeamCreate(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    name                   := d.Get("name").(string)
+    members                := tfutil.GetSetOfStrings(d, "members")
+    teamingMode            := d.Get("teaming_mode").(string)
+    loadBalancingAlgorithm := d.Get("load_balancing_algorithm").(string)
+    standbyMember          := d.Get("standby_member").(string)
+    teamNICs               := tfutil.GetSetOfResources(d, "team_nic")
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    id := fmt.Sprintf("//%s/network_teams/%s", host, name)
+
+    log.Printf(`[INFO][terraform-provider-windows] creating windows_network_team %q
+                    [INFO][terraform-provider-windows]     name:                     %#v
+                    [INFO][terraform-provider-windows]     members:                  %#v
+                    [INFO][terraform-provider-windows]     teaming_mode:             %#v
+                    [INFO][terraform-provider-windows]     load_balancing_algorithm: %#v
+                    [INFO][terraform-provider-windows]     standby_member:           %#v
+                    [INFO][terraform-provider-windows]     team_nic:                 %#v
+`       ,
+        id,
+        name,
+        members,
+        teamingMode,
+        loadBalancingAlgorithm,
+        standbyMember,
+        teamNICs,
+    )
+
+    x_lifecycle := tfutil.GetResource(d, "x_lifecycle")
+
+    ntQuery := new(api.NetworkTeam)
+    ntQuery.Name = name
+
+    networkTeam, err := c.ReadNetworkTeam(ntQuery)
+    if err == nil {
+        // lifecycle customizations: import_if_exists
+        v, ok := x_lifecycle["import_if_exists"]
+        if !ok || !v.(bool) {
+            log.Printf("[ERROR][terraform-provider-windows] cannot create windows_network_team %q\n", id)
+            return fmt.Errorf("[ERROR][terraform-provider-windows] cannot create windows_network_team %q, team already exists", id)
+        }
+
+        log.Printf("[INFO][terraform-provider-windows] importing windows_network_team %q into terraform state\n", id)
+
+        // set computed lifecycle properties
+        x_lifecycle["imported"] = true
+        d.Set("x_lifecycle", []interface{}{ x_lifecycle })
+
+        // save original config
+        setOriginalNetworkTeamProperties(d, networkTeam)
+
+        // update
+        ntProperties := new(api.NetworkTeam)
+        expandNetworkTeamProperties(ntProperties, d)
+
+        err := c.UpdateNetworkTeam(ntQuery, ntProperties)
+        if err != nil {
+            log.Printf("[ERROR][terraform-provider-windows] cannot update windows_network_team %q\n", id)
+            return err
+        }
+
+        // set id
+        d.SetId(id)
+
+        log.Printf("[INFO][terraform-provider-windows] created windows_network_team %q\n", id)
+        return resourceWindowsNetworkTeamRead(d, m)
+    }
+    if !strings.Contains(err.Error(), "cannot find network_team") {
+        log.Printf("[ERROR][terraform-provider-windows] cannot create windows_network_team %q\n", id)
+        return err
+    }
+
+    // set computed lifecycle properties
+    x_lifecycle["imported"] = false
+    d.Set("x_lifecycle", []interface{}{ x_lifecycle })
+
+    // create
+    ntProperties := new(api.NetworkTeam)
+    ntProperties.Name = name
+    expandNetworkTeamProperties(ntProperties, d)
+
+    err = c.CreateNetworkTeam(ntProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows] cannot create windows_network_team %q\n", id)
+        return err
+    }
+
+    // set id
+    d.SetId(id)
+
+    log.Printf("[INFO][terraform-provider-windows] created windows_network_team %q\n", id)
+    return resourceWindowsNetworkTeamRead(d, m)
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsNetworkTeamRead(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    id := d.Id()
+
+    log.Printf("[INFO][terraform-provider-windows] reading windows_network_team %q\n", id)
+
+    // read
+    ntQuery := new(api.NetworkTeam)
+    ntQuery.Name = d.Get("name").(string)
+
+    networkTeam, err := c.ReadNetworkTeam(ntQuery)
+    if err != nil {
+        if strings.Contains(err.Error(), "cannot find network_team") {
+            log.Printf("[INFO][terraform-provider-windows] cannot find windows_network_team %q\n", id)
+
+            // set id
+            d.SetId("")
+
+            log.Printf("[INFO][terraform-provider-windows] deleted windows_network_team %q from terraform state\n", id)
+            return nil   // don't return an error to allow terraform refresh to update state
+        }
+
+        log.Printf("[ERROR][terraform-provider-windows] cannot read windows_network_team %q\n", id)
+        return err
+    }
+
+    // set properties
+    setNetworkTeamProperties(d, networkTeam)
+
+    log.Printf("[INFO][terraform-provider-windows] read windows_network_team %q\n", id)
+    return nil
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsNetworkTeamUpdate(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    id                     := d.Id()
+    members                := tfutil.GetSetOfStrings(d, "members")
+    teamingMode            := d.Get("teaming_mode").(string)
+    loadBalancingAlgorithm := d.Get("load_balancing_algorithm").(string)
+    standbyMember          := d.Get("standby_member").(string)
+    teamNICs               := tfutil.GetSetOfResources(d, "team_nic")
+
+    log.Printf(`[INFO][terraform-provider-windows] updating windows_network_team %q
+                    [INFO][terraform-provider-windows]     members:                  %#v
+                    [INFO][terraform-provider-windows]     teaming_mode:             %#v
+                    [INFO][terraform-provider-windows]     load_balancing_algorithm: %#v
+                    [INFO][terraform-provider-windows]     standby_member:           %#v
+                    [INFO][terraform-provider-windows]     team_nic:                 %#v
+`       ,
+        id,
+        members,
+        teamingMode,
+        loadBalancingAlgorithm,
+        standbyMember,
+        teamNICs,
+    )
+
+    // update
+    ntQuery := new(api.NetworkTeam)
+    ntQuery.Name = d.Get("name").(string)
+
+    ntProperties := new(api.NetworkTeam)
+    expandNetworkTeamProperties(ntProperties, d)
+
+    err := c.UpdateNetworkTeam(ntQuery, ntProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows] cannot update windows_network_team %q\n", id)
+        return err
+    }
+
+    log.Printf("[INFO][terraform-provider-windows] updated windows_network_team %q\n", id)
+    return resourceWindowsNetworkTeamRead(d, m)
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsNetworkTeamDelete(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    id := d.Id()
+
+    x_lifecycle := tfutil.GetResource(d, "x_lifecycle")
+
+    ntQuery := new(api.NetworkTeam)
+    ntQuery.Name = d.Get("name").(string)
+
+    // lifecycle customizations: destroy_if_imported
+    imported, _          := x_lifecycle["imported"].(bool)
+    destroyIfImported, _ := x_lifecycle["destroy_if_imported"].(bool)
+    if imported && !destroyIfImported {
+        log.Printf("[INFO][terraform-provider-windows] deleting windows_network_team %q from terraform state\n", id)
+        log.Printf("[INFO][terraform-provider-windows] restore original config for windows_network_team %q\n", id)
+
+        // restore original config
+        ntProperties := new(api.NetworkTeam)
+        expandOriginalNetworkTeamProperties(ntProperties, d)
+
+        err := c.UpdateNetworkTeam(ntQuery, ntProperties)
+        if err != nil {
+            log.Printf("[WARNING][terraform-provider-windows] cannot restore original config for windows_network_team %q\n", id)
+        }
+
+        // set id
+        d.SetId("")
+
+        log.Printf("[INFO][terraform-provider-windows] deleted windows_network_team %q from terraform state\n", id)
+        return nil
+    }
+
+    log.Printf("[INFO][terraform-provider-windows] deleting windows_network_team %q\n", id)
+
+    // delete
+    err := c.DeleteNetworkTeam(ntQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows] cannot delete windows_network_team %q\n", id)
+        return err
+    }
+
+    // set id
+    d.SetId("")
+
+    log.Printf("[INFO][terraform-provider-windows] deleted windows_network_team %q\n", id)
+    return nil
+}
+
+//------------------------------------------------------------------------------
+
+func setNetworkTeamProperties(d *schema.ResourceData, ntProperties *api.NetworkTeam) {
+    d.Set("name", ntProperties.Name)
+
+    d.Set("members", ntProperties.Members)
+    d.Set("teaming_mode", ntProperties.TeamingMode)
+    d.Set("load_balancing_algorithm", ntProperties.LoadBalancingAlgorithm)
+    d.Set("standby_member", ntProperties.StandbyMember)
+    d.Set("team_nic", flattenNetworkTeamTeamNICs(ntProperties.TeamNICs))
+
+    d.Set("primary_team_nic_name", ntProperties.PrimaryTeamNICName)
+    d.Set("status", ntProperties.Status)
+}
+
+func setOriginalNetworkTeamProperties(d *schema.ResourceData, ntProperties *api.NetworkTeam) {
+    original := make(map[string]interface{})
+
+    original["members"]                  = ntProperties.Members
+    original["teaming_mode"]             = ntProperties.TeamingMode
+    original["load_balancing_algorithm"] = ntProperties.LoadBalancingAlgorithm
+    original["standby_member"]           = ntProperties.StandbyMember
+    original["team_nic"]                 = flattenNetworkTeamTeamNICs(ntProperties.TeamNICs)
+
+    d.Set("original", []interface{}{ original })
+}
+
+func flattenNetworkTeamTeamNICs(tnPropertiesList []api.NetworkTeamNIC) []interface{} {
+    teamNICs := make([]interface{}, 0, len(tnPropertiesList))
+    for _, tnProperties := range tnPropertiesList {
+        teamNIC := make(map[string]interface{})
+        teamNIC["name"]    = tnProperties.Name
+        teamNIC["vlan_id"] = tnProperties.VlanID
+        teamNICs = append(teamNICs, teamNIC)
+    }
+    return teamNICs
+}
+
+//------------------------------------------------------------------------------
+
+func expandNetworkTeamProperties(ntProperties *api.NetworkTeam, d *schema.ResourceData) {
+    ntProperties.Members                = tfutil.GetSetOfStrings(d, "members")
+    ntProperties.TeamingMode            = d.Get("teaming_mode").(string)
+    ntProperties.LoadBalancingAlgorithm = d.Get("load_balancing_algorithm").(string)
+    ntProperties.StandbyMember          = d.Get("standby_member").(string)
+
+    for _, teamNIC := range tfutil.GetSetOfResources(d, "team_nic") {
+        ntProperties.TeamNICs = append(ntProperties.TeamNICs, api.NetworkTeamNIC{
+            Name:   teamNIC["name"].(string),
+            VlanID: uint16(teamNIC["vlan_id"].(int)),
+        })
+    }
+}
+
+func expandOriginalNetworkTeamProperties(ntProperties *api.NetworkTeam, d *schema.ResourceData) {
+    original := tfutil.GetResource(d, "original")
+
+    ntProperties.Members                = tfutil.ExpandListOfStrings(original, "members")
+    ntProperties.TeamingMode            = original["teaming_mode"].(string)
+    ntProperties.LoadBalancingAlgorithm = original["load_balancing_algorithm"].(string)
+    ntProperties.StandbyMember          = original["standby_member"].(string)
+
+    for _, original_teamNIC := range tfutil.ExpandListOfResources(original, "team_nic") {
+        ntProperties.TeamNICs = append(ntProperties.TeamNICs, api.NetworkTeamNIC{
+            Name:   original_teamNIC["name"].(string),
+            VlanID: uint16(original_teamNIC["vlan_id"].(int)),
+        })
+    }
+}
+
+//------------------------------------------------------------------------------
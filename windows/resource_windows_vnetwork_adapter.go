@@ -0,0 +1,385 @@
+//
+// Copyright (c) 2019 Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-windows
+//
+package windows
+
+import (
+    "fmt"
+    "log"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+    "github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+
+    "github.com/stefaanc/terraform-provider-windows/api"
+    "github.com/stefaanc/terraform-provider-windows/windows/tfutil"
+)
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsVNetworkAdapter() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "name": &schema.Schema{
+                Type:     schema.TypeString,
+                Required: true,
+                ForceNew: true,
+            },
+
+            "vswitch_name": &schema.Schema{
+                Type:     schema.TypeString,
+                Required: true,
+            },
+            "static_mac_address": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+
+                ValidateFunc: tfutil.ValidateSingleMAC(),
+                StateFunc: tfutil.StateToMAC(),
+                DiffSuppressFunc: tfutil.DiffSuppressMAC(),
+            },
+            "vlan_id": &schema.Schema{
+                Type:     schema.TypeInt,   // uint16
+                Optional: true,
+                Default:  0,
+
+                ValidateFunc: validation.IntBetween(0, 4094),
+            },
+            "minimum_bandwidth_weight": &schema.Schema{
+                Type:     schema.TypeInt,   // uint32
+                Optional: true,
+                Computed: true,
+
+                ValidateFunc: validation.IntBetween(1, 100),
+            },
+
+            "mac_address": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "network_adapter_name": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "network_adapter_guid": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+
+            // lifecycle customizations that are not supported by the 'lifecycle' meta-argument
+            "x_lifecycle": &tfutil.ResourceXLifecycleSchema,
+
+            // used to reset values on terraform destroy
+            "original": &schema.Schema{
+                Type:     schema.TypeList,
+                MaxItems: 1,
+                Computed: true,
+                Elem: resourceWindowsVNetworkAdapterOriginal(),
+            },
+        },
+
+        Create: resourceWindowsVNetworkAdapterCreate,
+        Read:   resourceWindowsVNetworkAdapterRead,
+        Update: resourceWindowsVNetworkAdapterUpdate,
+        Delete: resourceWindowsVNetworkAdapterDelete,
+    }
+}
+
+func resourceWindowsVNetworkAdapterOriginal() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "vswitch_name": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "static_mac_address": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+            "vlan_id": &schema.Schema{
+                Type:     schema.TypeInt,
+                Computed: true,
+            },
+            "minimum_bandwidth_weight": &schema.Schema{
+                Type:     schema.TypeInt,
+                Computed: true,
+            },
+        },
+    }
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsVNetworkAdapterCreate(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    name                   := d.Get("name").(string)
+    vswitchName            := d.Get("vswitch_name").(string)
+    staticMACAddress       := d.Get("static_mac_address").(string)
+    vlanID                 := d.Get("vlan_id").(int)
+    minimumBandwidthWeight := d.Get("minimum_bandwidth_weight").(int)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    id := fmt.Sprintf("//%s/vnetwork_adapters/%s", host, name)
+
+    log.Printf(`[INFO][terraform-provider-windows] creating windows_vnetwork_adapter %q
+                    [INFO][terraform-provider-windows]     name:                     %#v
+                    [INFO][terraform-provider-windows]     vswitch_name:             %#v
+                    [INFO][terraform-provider-windows]     static_mac_address:       %#v
+                    [INFO][terraform-provider-windows]     vlan_id:                  %#v
+                    [INFO][terraform-provider-windows]     minimum_bandwidth_weight: %#v
+`       ,
+        id,
+        name,
+        vswitchName,
+        staticMACAddress,
+        vlanID,
+        minimumBandwidthWeight,
+    )
+
+    x_lifecycle := tfutil.GetResource(d, "x_lifecycle")
+
+    vnaQuery := new(api.VNetworkAdapter)
+    vnaQuery.Name = name
+
+    vnetworkAdapter, err := c.ReadVNetworkAdapter(vnaQuery)
+    if err == nil {
+        // lifecycle customizations: import_if_exists
+        v, ok := x_lifecycle["import_if_exists"]
+        if !ok || !v.(bool) {
+            log.Printf("[ERROR][terraform-provider-windows] cannot create windows_vnetwork_adapter %q\n", id)
+            return fmt.Errorf("[ERROR][terraform-provider-windows] cannot create windows_vnetwork_adapter %q, vnetwork_adapter already exists", id)
+        }
+
+        log.Printf("[INFO][terraform-provider-windows] importing windows_vnetwork_adapter %q into terraform state\n", id)
+
+        // set computed lifecycle properties
+        x_lifecycle["imported"] = true
+        d.Set("x_lifecycle", []interface{}{ x_lifecycle })
+
+        // save original config
+        setOriginalVNetworkAdapterProperties(d, vnetworkAdapter)
+
+        // update
+        vnaProperties := new(api.VNetworkAdapter)
+        expandVNetworkAdapterProperties(vnaProperties, d)
+
+        err := c.UpdateVNetworkAdapter(vnaQuery, vnaProperties)
+        if err != nil {
+            log.Printf("[ERROR][terraform-provider-windows] cannot update windows_vnetwork_adapter %q\n", id)
+            return err
+        }
+
+        // set id
+        d.SetId(id)
+
+        log.Printf("[INFO][terraform-provider-windows] created windows_vnetwork_adapter %q\n", id)
+        return resourceWindowsVNetworkAdapterRead(d, m)
+    }
+    if !strings.Contains(err.Error(), "cannot find vnetwork_adapter") {
+        log.Printf("[ERROR][terraform-provider-windows] cannot create windows_vnetwork_adapter %q\n", id)
+        return err
+    }
+
+    // set computed lifecycle properties
+    x_lifecycle["imported"] = false
+    d.Set("x_lifecycle", []interface{}{ x_lifecycle })
+
+    // create
+    vnaProperties := new(api.VNetworkAdapter)
+    vnaProperties.Name = name
+    expandVNetworkAdapterProperties(vnaProperties, d)
+
+    err = c.CreateVNetworkAdapter(vnaProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows] cannot create windows_vnetwork_adapter %q\n", id)
+        return err
+    }
+
+    // set id
+    d.SetId(id)
+
+    log.Printf("[INFO][terraform-provider-windows] created windows_vnetwork_adapter %q\n", id)
+    return resourceWindowsVNetworkAdapterRead(d, m)
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsVNetworkAdapterRead(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    id := d.Id()
+
+    log.Printf("[INFO][terraform-provider-windows] reading windows_vnetwork_adapter %q\n", id)
+
+    // read
+    vnaQuery := new(api.VNetworkAdapter)
+    vnaQuery.Name = d.Get("name").(string)
+
+    vnetworkAdapter, err := c.ReadVNetworkAdapter(vnaQuery)
+    if err != nil {
+        if strings.Contains(err.Error(), "cannot find vnetwork_adapter") {
+            log.Printf("[INFO][terraform-provider-windows] cannot find windows_vnetwork_adapter %q\n", id)
+
+            // set id
+            d.SetId("")
+
+            log.Printf("[INFO][terraform-provider-windows] deleted windows_vnetwork_adapter %q from terraform state\n", id)
+            return nil   // don't return an error to allow terraform refresh to update state
+        }
+
+        log.Printf("[ERROR][terraform-provider-windows] cannot read windows_vnetwork_adapter %q\n", id)
+        return err
+    }
+
+    // set properties
+    setVNetworkAdapterProperties(d, vnetworkAdapter)
+
+    log.Printf("[INFO][terraform-provider-windows] read windows_vnetwork_adapter %q\n", id)
+    return nil
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsVNetworkAdapterUpdate(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    id                     := d.Id()
+    vswitchName            := d.Get("vswitch_name").(string)
+    staticMACAddress       := d.Get("static_mac_address").(string)
+    vlanID                 := d.Get("vlan_id").(int)
+    minimumBandwidthWeight := d.Get("minimum_bandwidth_weight").(int)
+
+    log.Printf(`[INFO][terraform-provider-windows] updating windows_vnetwork_adapter %q
+                    [INFO][terraform-provider-windows]     vswitch_name:             %#v
+                    [INFO][terraform-provider-windows]     static_mac_address:       %#v
+                    [INFO][terraform-provider-windows]     vlan_id:                  %#v
+                    [INFO][terraform-provider-windows]     minimum_bandwidth_weight: %#v
+`       ,
+        id,
+        vswitchName,
+        staticMACAddress,
+        vlanID,
+        minimumBandwidthWeight,
+    )
+
+    // update
+    vnaQuery := new(api.VNetworkAdapter)
+    vnaQuery.Name = d.Get("name").(string)
+
+    vnaProperties := new(api.VNetworkAdapter)
+    expandVNetworkAdapterProperties(vnaProperties, d)
+
+    err := c.UpdateVNetworkAdapter(vnaQuery, vnaProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows] cannot update windows_vnetwork_adapter %q\n", id)
+        return err
+    }
+
+    log.Printf("[INFO][terraform-provider-windows] updated windows_vnetwork_adapter %q\n", id)
+    return resourceWindowsVNetworkAdapterRead(d, m)
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsVNetworkAdapterDelete(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    id := d.Id()
+
+    x_lifecycle := tfutil.GetResource(d, "x_lifecycle")
+
+    vnaQuery := new(api.VNetworkAdapter)
+    vnaQuery.Name = d.Get("name").(string)
+
+    // lifecycle customizations: destroy_if_imported
+    imported, _          := x_lifecycle["imported"].(bool)
+    destroyIfImported, _ := x_lifecycle["destroy_if_imported"].(bool)
+    if imported && !destroyIfImported {
+        log.Printf("[INFO][terraform-provider-windows] deleting windows_vnetwork_adapter %q from terraform state\n", id)
+        log.Printf("[INFO][terraform-provider-windows] restore original config for windows_vnetwork_adapter %q\n", id)
+
+        // restore original config
+        vnaProperties := new(api.VNetworkAdapter)
+        expandOriginalVNetworkAdapterProperties(vnaProperties, d)
+
+        err := c.UpdateVNetworkAdapter(vnaQuery, vnaProperties)
+        if err != nil {
+            log.Printf("[WARNING][terraform-provider-windows] cannot restore original config for windows_vnetwork_adapter %q\n", id)
+        }
+
+        // set id
+        d.SetId("")
+
+        log.Printf("[INFO][terraform-provider-windows] deleted windows_vnetwork_adapter %q from terraform state\n", id)
+        return nil
+    }
+
+    log.Printf("[INFO][terraform-provider-windows] deleting windows_vnetwork_adapter %q\n", id)
+
+    // delete
+    err := c.DeleteVNetworkAdapter(vnaQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows] cannot delete windows_vnetwork_adapter %q\n", id)
+        return err
+    }
+
+    // set id
+    d.SetId("")
+
+    log.Printf("[INFO][terraform-provider-windows] deleted windows_vnetwork_adapter %q\n", id)
+    return nil
+}
+
+//------------------------------------------------------------------------------
+
+func setVNetworkAdapterProperties(d *schema.ResourceData, vnaProperties *api.VNetworkAdapter) {
+    d.Set("name", vnaProperties.Name)
+
+    d.Set("vswitch_name", vnaProperties.VSwitchName)
+    d.Set("static_mac_address", vnaProperties.StaticMACAddress)
+    d.Set("vlan_id", int(vnaProperties.VlanID))
+    d.Set("minimum_bandwidth_weight", int(vnaProperties.MinimumBandwidthWeight))
+
+    d.Set("mac_address", vnaProperties.MACAddress)
+    d.Set("network_adapter_name", vnaProperties.NetworkAdapterName)
+    d.Set("network_adapter_guid", vnaProperties.NetworkAdapterGUID)
+}
+
+func setOriginalVNetworkAdapterProperties(d *schema.ResourceData, vnaProperties *api.VNetworkAdapter) {
+    original := make(map[string]interface{})
+
+    original["vswitch_name"]             = vnaProperties.VSwitchName
+    original["static_mac_address"]       = vnaProperties.StaticMACAddress
+    original["vlan_id"]                  = int(vnaProperties.VlanID)
+    original["minimum_bandwidth_weight"] = int(vnaProperties.MinimumBandwidthWeight)
+
+    d.Set("original", []interface{}{ original })
+}
+
+//------------------------------------------------------------------------------
+
+func expandVNetworkAdapterProperties(vnaProperties *api.VNetworkAdapter, d *schema.ResourceData) {
+    vnaProperties.VSwitchName            = d.Get("vswitch_name").(string)
+    vnaProperties.StaticMACAddress       = tfutil.NormalizeMAC(d.Get("static_mac_address").(string))
+    vnaProperties.VlanID                 = uint16(d.Get("vlan_id").(int))
+    vnaProperties.MinimumBandwidthWeight = uint32(d.Get("minimum_bandwidth_weight").(int))
+}
+
+func expandOriginalVNetworkAdapterProperties(vnaProperties *api.VNetworkAdapter, d *schema.ResourceData) {
+    original := tfutil.GetResource(d, "original")
+
+    vnaProperties.VSwitchName            = original["vswitch_name"].(string)
+    vnaProperties.StaticMACAddress       = original["static_mac_address"].(string)
+    vnaProperties.VlanID                 = uint16(original["vlan_id"].(int))
+    vnaProperties.MinimumBandwidthWeight = uint32(original["minimum_bandwidth_weight"].(int))
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,425 @@
+//
+// Copyright (c) 2019 Stefaan Coussement
+// MIT License
+//
+// more info: https://github.com/stefaanc/terraform-provider-windows
+//
+package windows
+
+import (
+    "fmt"
+    "log"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+    "github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+
+    "github.com/stefaanc/terraform-provider-windows/api"
+    "github.com/stefaanc/terraform-provider-windows/windows/tfutil"
+)
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsVSwitch() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "name": &schema.Schema{
+                Type:     schema.TypeString,
+                Required: true,
+                ForceNew: true,
+            },
+            "switch_type": &schema.Schema{
+                Type:     schema.TypeString,
+                Required: true,
+                ForceNew: true,
+
+                ValidateFunc: validation.StringInSlice([]string{ "External", "Internal", "Private" }, false),
+            },
+
+            "network_adapter_names": &schema.Schema{
+                Type:     schema.TypeSet,
+                Optional: true,
+                Elem: &schema.Schema{
+                    Type: schema.TypeString,
+                },
+            },
+            "allow_management_os": &schema.Schema{
+                Type:     schema.TypeBool,
+                Optional: true,
+                Computed: true,
+            },
+            "enable_embedded_teaming": &schema.Schema{
+                Type:     schema.TypeBool,
+                Optional: true,
+                Default:  false,
+                ForceNew: true,
+            },
+
+            "bandwidth_reservation_mode": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Computed: true,
+                ForceNew: true,
+
+                ValidateFunc: validation.StringInSlice([]string{ "Absolute", "Weight", "None" }, false),
+            },
+            "default_flow_minimum_bandwidth_weight": &schema.Schema{
+                Type:     schema.TypeInt,   // uint32
+                Optional: true,
+                Computed: true,
+
+                ValidateFunc: validation.IntBetween(1, 100),
+            },
+            "default_flow_minimum_bandwidth_absolute": &schema.Schema{
+                Type:     schema.TypeInt,   // uint64, bits per second
+                Optional: true,
+                Computed: true,
+
+                ValidateFunc: validation.IntAtLeast(1),
+            },
+
+            "guid": &schema.Schema{
+                Type:     schema.TypeString,
+                Computed: true,
+            },
+
+            // lifecycle customizations that are not supported by the 'lifecycle' meta-argument
+            "x_lifecycle": &tfutil.ResourceXLifecycleSchema,
+
+            // used to reset values on terraform destroy
+            "original": &schema.Schema{
+                Type:     schema.TypeList,
+                MaxItems: 1,
+                Computed: true,
+                Elem: resourceWindowsVSwitchOriginal(),
+            },
+        },
+
+        Create: resourceWindowsVSwitchCreate,
+        Read:   resourceWindowsVSwitchRead,
+        Update: resourceWindowsVSwitchUpdate,
+        Delete: resourceWindowsVSwitchDelete,
+    }
+}
+
+func resourceWindowsVSwitchOriginal() *schema.Resource {
+    return &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "network_adapter_names": &schema.Schema{
+                Type:     schema.TypeList,
+                Computed: true,
+                Elem: &schema.Schema{
+                    Type: schema.TypeString,
+                },
+            },
+            "allow_management_os": &schema.Schema{
+                Type:     schema.TypeBool,
+                Computed: true,
+            },
+            "default_flow_minimum_bandwidth_weight": &schema.Schema{
+                Type:     schema.TypeInt,
+                Computed: true,
+            },
+            "default_flow_minimum_bandwidth_absolute": &schema.Schema{
+                Type:     schema.TypeInt,
+                Computed: true,
+            },
+        },
+    }
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsVSwitchCreate(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    name                                := d.Get("name").(string)
+    switchType                          := d.Get("switch_type").(string)
+    networkAdapterNames                 := tfutil.GetSetOfStrings(d, "network_adapter_names")
+    allowManagementOS                   := d.Get("allow_management_os").(bool)
+    enableEmbeddedTeaming               := d.Get("enable_embedded_teaming").(bool)
+    bandwidthReservationMode            := d.Get("bandwidth_reservation_mode").(string)
+    defaultFlowMinimumBandwidthWeight   := d.Get("default_flow_minimum_bandwidth_weight").(int)
+    defaultFlowMinimumBandwidthAbsolute := d.Get("default_flow_minimum_bandwidth_absolute").(int)
+
+    host := "localhost"
+    if c.Type != "local" {
+        host = c.Host
+    }
+
+    id := fmt.Sprintf("//%s/vswitches/%s", host, name)
+
+    log.Printf(`[INFO][terraform-provider-windows] creating windows_vswitch %q
+                    [INFO][terraform-provider-windows]     name:                                    %#v
+                    [INFO][terraform-provider-windows]     switch_type:                             %#v
+                    [INFO][terraform-provider-windows]     network_adapter_names:                   %#v
+                    [INFO][terraform-provider-windows]     allow_management_os:                     %#v
+                    [INFO][terraform-provider-windows]     enable_embedded_teaming:                 %#v
+                    [INFO][terraform-provider-windows]     bandwidth_reservation_mode:              %#v
+                    [INFO][terraform-provider-windows]     default_flow_minimum_bandwidth_weight:   %#v
+                    [INFO][terraform-provider-windows]     default_flow_minimum_bandwidth_absolute: %#v
+`       ,
+        id,
+        name,
+        switchType,
+        networkAdapterNames,
+        allowManagementOS,
+        enableEmbeddedTeaming,
+        bandwidthReservationMode,
+        defaultFlowMinimumBandwidthWeight,
+        defaultFlowMinimumBandwidthAbsolute,
+    )
+
+    x_lifecycle := tfutil.GetResource(d, "x_lifecycle")
+
+    vsQuery := new(api.VSwitch)
+    vsQuery.Name = name
+
+    vswitch, err := c.ReadVSwitch(vsQuery)
+    if err == nil {
+        // lifecycle customizations: import_if_exists
+        v, ok := x_lifecycle["import_if_exists"]
+        if !ok || !v.(bool) {
+            log.Printf("[ERROR][terraform-provider-windows] cannot create windows_vswitch %q\n", id)
+            return fmt.Errorf("[ERROR][terraform-provider-windows] cannot create windows_vswitch %q, vswitch already exists", id)
+        }
+        if vswitch.SwitchType != switchType {
+            log.Printf("[ERROR][terraform-provider-windows] cannot import windows_vswitch %q\n", id)
+            return fmt.Errorf("[ERROR][terraform-provider-windows] cannot import windows_vswitch %q, existing vswitch has switch_type %q", id, vswitch.SwitchType)
+        }
+
+        log.Printf("[INFO][terraform-provider-windows] importing windows_vswitch %q into terraform state\n", id)
+
+        // set computed lifecycle properties
+        x_lifecycle["imported"] = true
+        d.Set("x_lifecycle", []interface{}{ x_lifecycle })
+
+        // save original config
+        setOriginalVSwitchProperties(d, vswitch)
+
+        // update
+        vsProperties := new(api.VSwitch)
+        expandVSwitchProperties(vsProperties, d)
+
+        err := c.UpdateVSwitch(vsQuery, vsProperties)
+        if err != nil {
+            log.Printf("[ERROR][terraform-provider-windows] cannot update windows_vswitch %q\n", id)
+            return err
+        }
+
+        // set id
+        d.SetId(id)
+
+        log.Printf("[INFO][terraform-provider-windows] created windows_vswitch %q\n", id)
+        return resourceWindowsVSwitchRead(d, m)
+    }
+    if !strings.Contains(err.Error(), "cannot find vswitch") {
+        log.Printf("[ERROR][terraform-provider-windows] cannot create windows_vswitch %q\n", id)
+        return err
+    }
+
+    // set computed lifecycle properties
+    x_lifecycle["imported"] = false
+    d.Set("x_lifecycle", []interface{}{ x_lifecycle })
+
+    // create
+    vsProperties := new(api.VSwitch)
+    vsProperties.Name                     = name
+    vsProperties.SwitchType               = switchType
+    vsProperties.EnableEmbeddedTeaming    = enableEmbeddedTeaming
+    vsProperties.BandwidthReservationMode = bandwidthReservationMode
+    expandVSwitchProperties(vsProperties, d)
+
+    // an external vswitch shares its network adapter with the management OS unless explicitly disallowed
+    if _, ok := d.GetOkExists("allow_management_os"); !ok {
+        vsProperties.AllowManagementOS = true
+    }
+
+    err = c.CreateVSwitch(vsProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows] cannot create windows_vswitch %q\n", id)
+        return err
+    }
+
+    // set id
+    d.SetId(id)
+
+    log.Printf("[INFO][terraform-provider-windows] created windows_vswitch %q\n", id)
+    return resourceWindowsVSwitchRead(d, m)
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsVSwitchRead(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    id := d.Id()
+
+    log.Printf("[INFO][terraform-provider-windows] reading windows_vswitch %q\n", id)
+
+    // read
+    vsQuery := new(api.VSwitch)
+    vsQuery.Name = d.Get("name").(string)
+
+    vswitch, err := c.ReadVSwitch(vsQuery)
+    if err != nil {
+        if strings.Contains(err.Error(), "cannot find vswitch") {
+            log.Printf("[INFO][terraform-provider-windows] cannot find windows_vswitch %q\n", id)
+
+            // set id
+            d.SetId("")
+
+            log.Printf("[INFO][terraform-provider-windows] deleted windows_vswitch %q from terraform state\n", id)
+            return nil   // don't return an error to allow terraform refresh to update state
+        }
+
+        log.Printf("[ERROR][terraform-provider-windows] cannot read windows_vswitch %q\n", id)
+        return err
+    }
+
+    // set properties
+    setVSwitchProperties(d, vswitch)
+
+    log.Printf("[INFO][terraform-provider-windows] read windows_vswitch %q\n", id)
+    return nil
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsVSwitchUpdate(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    id                                  := d.Id()
+    networkAdapterNames                 := tfutil.GetSetOfStrings(d, "network_adapter_names")
+    allowManagementOS                   := d.Get("allow_management_os").(bool)
+    defaultFlowMinimumBandwidthWeight   := d.Get("default_flow_minimum_bandwidth_weight").(int)
+    defaultFlowMinimumBandwidthAbsolute := d.Get("default_flow_minimum_bandwidth_absolute").(int)
+
+    log.Printf(`[INFO][terraform-provider-windows] updating windows_vswitch %q
+                    [INFO][terraform-provider-windows]     network_adapter_names:                   %#v
+                    [INFO][terraform-provider-windows]     allow_management_os:                     %#v
+                    [INFO][terraform-provider-windows]     default_flow_minimum_bandwidth_weight:   %#v
+                    [INFO][terraform-provider-windows]     default_flow_minimum_bandwidth_absolute: %#v
+`       ,
+        id,
+        networkAdapterNames,
+        allowManagementOS,
+        defaultFlowMinimumBandwidthWeight,
+        defaultFlowMinimumBandwidthAbsolute,
+    )
+
+    // update
+    vsQuery := new(api.VSwitch)
+    vsQuery.Name = d.Get("name").(string)
+
+    vsProperties := new(api.VSwitch)
+    expandVSwitchProperties(vsProperties, d)
+
+    err := c.UpdateVSwitch(vsQuery, vsProperties)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows] cannot update windows_vswitch %q\n", id)
+        return err
+    }
+
+    log.Printf("[INFO][terraform-provider-windows] updated windows_vswitch %q\n", id)
+    return resourceWindowsVSwitchRead(d, m)
+}
+
+//------------------------------------------------------------------------------
+
+func resourceWindowsVSwitchDelete(d *schema.ResourceData, m interface{}) error {
+    c := m.(*api.WindowsClient)
+
+    id := d.Id()
+
+    x_lifecycle := tfutil.GetResource(d, "x_lifecycle")
+
+    vsQuery := new(api.VSwitch)
+    vsQuery.Name = d.Get("name").(string)
+
+    // lifecycle customizations: destroy_if_imported
+    imported, _          := x_lifecycle["imported"].(bool)
+    destroyIfImported, _ := x_lifecycle["destroy_if_imported"].(bool)
+    if imported && !destroyIfImported {
+        log.Printf("[INFO][terraform-provider-windows] deleting windows_vswitch %q from terraform state\n", id)
+        log.Printf("[INFO][terraform-provider-windows] restore original config for windows_vswitch %q\n", id)
+
+        // restore original config
+        vsProperties := new(api.VSwitch)
+        expandOriginalVSwitchProperties(vsProperties, d)
+
+        err := c.UpdateVSwitch(vsQuery, vsProperties)
+        if err != nil {
+            log.Printf("[WARNING][terraform-provider-windows] cannot restore original config for windows_vswitch %q\n", id)
+        }
+
+        // set id
+        d.SetId("")
+
+        log.Printf("[INFO][terraform-provider-windows] deleted windows_vswitch %q from terraform state\n", id)
+        return nil
+    }
+
+    log.Printf("[INFO][terraform-provider-windows] deleting windows_vswitch %q\n", id)
+
+    // delete
+    err := c.DeleteVSwitch(vsQuery)
+    if err != nil {
+        log.Printf("[ERROR][terraform-provider-windows] cannot delete windows_vswitch %q\n", id)
+        return err
+    }
+
+    // set id
+    d.SetId("")
+
+    log.Printf("[INFO][terraform-provider-windows] deleted windows_vswitch %q\n", id)
+    return nil
+}
+
+//------------------------------------------------------------------------------
+
+func setVSwitchProperties(d *schema.ResourceData, vsProperties *api.VSwitch) {
+    d.Set("name", vsProperties.Name)
+    d.Set("switch_type", vsProperties.SwitchType)
+
+    d.Set("network_adapter_names", vsProperties.NetworkAdapterNames)
+    d.Set("allow_management_os", vsProperties.AllowManagementOS)
+    d.Set("enable_embedded_teaming", vsProperties.EnableEmbeddedTeaming)
+
+    d.Set("bandwidth_reservation_mode", vsProperties.BandwidthReservationMode)
+    d.Set("default_flow_minimum_bandwidth_weight", int(vsProperties.DefaultFlowMinimumBandwidthWeight))
+    d.Set("default_flow_minimum_bandwidth_absolute", int(vsProperties.DefaultFlowMinimumBandwidthAbsolute))
+
+    d.Set("guid", vsProperties.GUID)
+}
+
+func setOriginalVSwitchProperties(d *schema.ResourceData, vsProperties *api.VSwitch) {
+    original := make(map[string]interface{})
+
+    original["network_adapter_names"]                   = vsProperties.NetworkAdapterNames
+    original["allow_management_os"]                     = vsProperties.AllowManagementOS
+    original["default_flow_minimum_bandwidth_weight"]   = int(vsProperties.DefaultFlowMinimumBandwidthWeight)
+    original["default_flow_minimum_bandwidth_absolute"] = int(vsProperties.DefaultFlowMinimumBandwidthAbsolute)
+
+    d.Set("original", []interface{}{ original })
+}
+
+//------------------------------------------------------------------------------
+
+func expandVSwitchProperties(vsProperties *api.VSwitch, d *schema.ResourceData) {
+    vsProperties.NetworkAdapterNames                 = tfutil.GetSetOfStrings(d, "network_adapter_names")
+    vsProperties.AllowManagementOS                   = d.Get("allow_management_os").(bool)
+    vsProperties.DefaultFlowMinimumBandwidthWeight   = uint32(d.Get("default_flow_minimum_bandwidth_weight").(int))
+    vsProperties.DefaultFlowMinimumBandwidthAbsolute = uint64(d.Get("default_flow_minimum_bandwidth_absolute").(int))
+}
+
+func expandOriginalVSwitchProperties(vsProperties *api.VSwitch, d *schema.ResourceData) {
+    original := tfutil.GetResource(d, "original")
+
+    vsProperties.NetworkAdapterNames                 = tfutil.ExpandListOfStrings(original, "network_adapter_names")
+    vsProperties.AllowManagementOS                   = original["allow_management_os"].(bool)
+    vsProperties.DefaultFlowMinimumBandwidthWeight   = uint32(original["default_flow_minimum_bandwidth_weight"].(int))
+    vsProperties.DefaultFlowMinimumBandwidthAbsolute = uint64(original["default_flow_minimum_bandwidth_absolute"].(int))
+}
+
+//------------------------------------------------------------------------------
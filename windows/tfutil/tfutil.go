@@ -8,8 +8,11 @@ package tfutil
 
 import (
     "fmt"
+    "log"
+    "net"
     "regexp"
     "strings"
+    "time"
 
     "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 )
@@ -30,6 +33,9 @@ var DataSourceXLifecycleSchema schema.Schema =  schema.Schema{
                 Optional: true,
                 Default:  false,
             },
+            // "wait_until_exists" keeps reading the data source until it exists or until the timeout expires
+            // this can be used for objects that appear some time after they are created, f.i. hot-plugged network adapters
+            "wait_until_exists": &WaitUntilExistsSchema,
             // "exists" true if the resource exists
             "exists": &schema.Schema{
                 Type:     schema.TypeBool,
@@ -39,6 +45,30 @@ var DataSourceXLifecycleSchema schema.Schema =  schema.Schema{
     },
 }
 
+var WaitUntilExistsSchema schema.Schema = schema.Schema{
+    Type:     schema.TypeList,
+    MaxItems: 1,
+    Optional: true,
+    Elem: &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "timeout": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Default:  "5m",
+
+                ValidateFunc: ValidateDuration(),
+            },
+            "poll_interval": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Default:  "5s",
+
+                ValidateFunc: ValidateDuration(),
+            },
+        },
+    },
+}
+
 var ResourceXLifecycleSchema schema.Schema = schema.Schema{
     Type:     schema.TypeList,
     MaxItems: 1,
@@ -70,6 +100,25 @@ var ResourceXLifecycleSchema schema.Schema = schema.Schema{
     },
 }
 
+// "rollback_protection" schedules a rollback on the windows-computer before an update, that restores the original values unless it is cancelled
+// the rollback is only cancelled when the provider can reconnect and read the resource after the update, before the window expires
+var RollbackProtectionSchema schema.Schema = schema.Schema{
+    Type:     schema.TypeList,
+    MaxItems: 1,
+    Optional: true,
+    Elem: &schema.Resource{
+        Schema: map[string]*schema.Schema{
+            "window": &schema.Schema{
+                Type:     schema.TypeString,
+                Optional: true,
+                Default:  "5m",
+
+                ValidateFunc: ValidateDuration(),
+            },
+        },
+    },
+}
+
 //------------------------------------------------------------------------------
 
 func ValidateUUID() schema.SchemaValidateFunc {
@@ -95,10 +144,39 @@ func ValidateSingleMAC() schema.SchemaValidateFunc {
             return nil, []error{fmt.Errorf("expected type of %s to be a string", k)}
         }
 
-        v = strings.ToLower(v)
-        r := regexp.MustCompile(`^[0-9a-f]{2}(-[0-9a-f]{2}){5}$`)
-        if !r.MatchString(v) {
-            return nil, []error{fmt.Errorf("expected value of %s to be a valid MAC, using format \"xx-xx-xx-xx-xx-xx\" where \"x\" is a hex digit, got: %s", k, v)}
+        _, err := ParseMAC(v)
+        if err != nil {
+            return nil, []error{fmt.Errorf("expected value of %s to be a valid MAC, using format \"xx-xx-xx-xx-xx-xx\", \"xx:xx:xx:xx:xx:xx\", \"xxxx.xxxx.xxxx\" or \"xxxxxxxxxxxx\" where \"x\" is a hex digit, got: %s", k, v)}
+        }
+        return nil, nil
+    }
+}
+
+func ValidateMACPrefix() schema.SchemaValidateFunc {
+    return func(i interface{}, k string) ([]string, []error) {
+        v, ok := i.(string)
+        if !ok {
+            return nil, []error{fmt.Errorf("expected type of %s to be a string", k)}
+        }
+
+        _, err := ParseMACPrefix(v)
+        if err != nil {
+            return nil, []error{fmt.Errorf("expected value of %s to be a valid MAC prefix of 1 to 5 octets, using format \"xx-xx-xx\", \"xx:xx:xx\", \"xxxx.xx\" or \"xxxxxx\" where \"x\" is a hex digit, got: %s", k, v)}
+        }
+        return nil, nil
+    }
+}
+
+func ValidateDuration() schema.SchemaValidateFunc {
+    return func(i interface{}, k string) ([]string, []error) {
+        v, ok := i.(string)
+        if !ok {
+            return nil, []error{fmt.Errorf("expected type of %s to be a string", k)}
+        }
+
+        d, err := time.ParseDuration(v)
+        if err != nil || d <= 0 {
+            return nil, []error{fmt.Errorf("expected value of %s to be a valid positive duration, using format \"1h2m3s\", got: %s", k, v)}
         }
         return nil, nil
     }
@@ -121,6 +199,30 @@ func StateToUpper() schema.SchemaStateFunc {
     }
 }
 
+// StateToMAC normalizes a MAC address to the Windows format "XX-XX-XX-XX-XX-XX", other values are left unchanged
+func StateToMAC() schema.SchemaStateFunc {
+    return func(val interface{}) string {
+        return NormalizeMAC(val.(string))
+    }
+}
+
+// StateToMACPrefix normalizes a MAC prefix to the Windows format "XX-XX-XX", other values are left unchanged
+func StateToMACPrefix() schema.SchemaStateFunc {
+    return func(val interface{}) string {
+        return NormalizeMACPrefix(val.(string))
+    }
+}
+
+// StateToIP normalizes an IP address to its canonical format, f.i. "2001:db8:0::1" becomes "2001:db8::1", other values are left unchanged
+func StateToIP() schema.SchemaStateFunc {
+    return func(val interface{}) string {
+        if ip := net.ParseIP(val.(string)); ip != nil {
+            return ip.String()
+        }
+        return val.(string)
+    }
+}
+
 func StateToLower() schema.SchemaStateFunc {
     return func(val interface{}) string {
         return strings.ToLower(val.(string))
@@ -148,6 +250,36 @@ func StateAcceptEmptyString() schema.SchemaStateFunc {
 
 //------------------------------------------------------------------------------
 
+// DiffSuppressMAC suppresses the diff between two notations of the same MAC address
+func DiffSuppressMAC() schema.SchemaDiffSuppressFunc {
+    return func(k, old, new string, d *schema.ResourceData) bool {
+        o, err := ParseMAC(old)
+        if err != nil {
+            return false
+        }
+        n, err := ParseMAC(new)
+        if err != nil {
+            return false
+        }
+        return o == n
+    }
+}
+
+// DiffSuppressMACPrefix suppresses the diff between two notations of the same MAC prefix
+func DiffSuppressMACPrefix() schema.SchemaDiffSuppressFunc {
+    return func(k, old, new string, d *schema.ResourceData) bool {
+        o, err := ParseMACPrefix(old)
+        if err != nil {
+            return false
+        }
+        n, err := ParseMACPrefix(new)
+        if err != nil {
+            return false
+        }
+        return o == n
+    }
+}
+
 // func DiffSuppressCase() schema.SchemaDiffSuppressFunc {
 //     return func(k, old, new string, d *schema.ResourceData) bool {
 //         if strings.ToLower(old) == strings.ToLower(new) {
@@ -159,6 +291,201 @@ func StateAcceptEmptyString() schema.SchemaStateFunc {
 
 //------------------------------------------------------------------------------
 
+var macFormats = []*regexp.Regexp{
+    regexp.MustCompile(`^[0-9a-f]{2}(-[0-9a-f]{2}){5}$`),           // Windows
+    regexp.MustCompile(`^[0-9a-f]{2}(:[0-9a-f]{2}){5}$`),           // Linux
+    regexp.MustCompile(`^[0-9a-f]{4}\.[0-9a-f]{4}\.[0-9a-f]{4}$`),   // Cisco
+    regexp.MustCompile(`^[0-9a-f]{12}$`),                           // Hyper-V
+}
+
+// ParseMAC accepts a MAC address in any of the common formats, and returns it in the Windows format "XX-XX-XX-XX-XX-XX"
+func ParseMAC(s string) (string, error) {
+    v := strings.ToLower(strings.TrimSpace(s))
+    for _, r := range macFormats {
+        if r.MatchString(v) {
+            h := strings.ToUpper(strings.NewReplacer("-", "", ":", "", ".", "").Replace(v))
+            return fmt.Sprintf("%s-%s-%s-%s-%s-%s", h[0:2], h[2:4], h[4:6], h[6:8], h[8:10], h[10:12]), nil
+        }
+    }
+    return "", fmt.Errorf("invalid MAC %q", s)
+}
+
+// NormalizeMAC returns a MAC address in the Windows format "XX-XX-XX-XX-XX-XX", other values are returned unchanged
+func NormalizeMAC(s string) string {
+    v, err := ParseMAC(s)
+    if err != nil {
+        return s
+    }
+    return v
+}
+
+var macPrefixFormats = []*regexp.Regexp{
+    regexp.MustCompile(`^[0-9a-f]{2}(-[0-9a-f]{2}){0,4}$`),                 // Windows
+    regexp.MustCompile(`^[0-9a-f]{2}(:[0-9a-f]{2}){0,4}$`),                 // Linux
+    regexp.MustCompile(`^([0-9a-f]{4}\.){0,2}[0-9a-f]{2}([0-9a-f]{2})?$`),   // Cisco
+    regexp.MustCompile(`^([0-9a-f]{2}){1,5}$`),                             // Hyper-V
+}
+
+// ParseMACPrefix accepts a MAC prefix of 1 to 5 octets in any of the common MAC formats, and returns it in the Windows format "XX-XX-XX"
+func ParseMACPrefix(s string) (string, error) {
+    v := strings.ToLower(strings.TrimSpace(s))
+    for _, r := range macPrefixFormats {
+        if r.MatchString(v) {
+            h := strings.ToUpper(strings.NewReplacer("-", "", ":", "", ".", "").Replace(v))
+            if len(h) > 10 {
+                break   // a full MAC address is not a prefix
+            }
+            octets := make([]string, 0, len(h) / 2)
+            for i := 0; i < len(h); i += 2 {
+                octets = append(octets, h[i:i+2])
+            }
+            return strings.Join(octets, "-"), nil
+        }
+    }
+    return "", fmt.Errorf("invalid MAC prefix %q", s)
+}
+
+// NormalizeMACPrefix returns a MAC prefix in the Windows format "XX-XX-XX", other values are returned unchanged
+func NormalizeMACPrefix(s string) string {
+    v, err := ParseMACPrefix(s)
+    if err != nil {
+        return s
+    }
+    return v
+}
+
+//------------------------------------------------------------------------------
+
+// WaitUntilExists calls 'read' until it doesn't fail with a 'notFound' error, or until the 'wait_until_exists' timeout of the 'x_lifecycle' expires
+// when there is no 'wait_until_exists' in the 'x_lifecycle', 'read' is called only once
+func WaitUntilExists(x_lifecycle map[string]interface{}, notFound string, read func() error) error {
+    waitUntilExists := ExpandListOfResources(x_lifecycle, "wait_until_exists")
+    if len(waitUntilExists) == 0 {
+        return read()
+    }
+
+    timeout, _      := time.ParseDuration(waitUntilExists[0]["timeout"].(string))
+    pollInterval, _ := time.ParseDuration(waitUntilExists[0]["poll_interval"].(string))
+    deadline        := time.Now().Add(timeout)
+
+    for {
+        err := read()
+        if ( err == nil ) || !strings.Contains(err.Error(), notFound) {
+            return err
+        }
+
+        if time.Now().Add(pollInterval).After(deadline) {
+            log.Printf("[INFO][terraform-provider-windows/tfutil/WaitUntilExists()] timeout %s expired\n", timeout)
+            return err
+        }
+
+        log.Printf("[INFO][terraform-provider-windows/tfutil/WaitUntilExists()] %s, retrying in %s\n", notFound, pollInterval)
+        time.Sleep(pollInterval)
+    }
+}
+
+// connectivity levels of a network connection, from low to high
+var ConnectivityLevels = []string{ "Disconnected", "NoTraffic", "Subnet", "LocalNetwork", "Internet" }
+
+func connectivityLevel(connectivity string) int {
+    for i, c := range ConnectivityLevels {
+        if strings.EqualFold(c, connectivity) {
+            return i
+        }
+    }
+    return -1
+}
+
+// MaxConnectivity returns the highest of two connectivity levels
+func MaxConnectivity(connectivity1 string, connectivity2 string) string {
+    if connectivityLevel(connectivity2) > connectivityLevel(connectivity1) {
+        return connectivity2
+    }
+    return connectivity1
+}
+
+// WaitForConnectivity calls 'read' until the IPv4 or IPv6 connectivity reaches the requested 'connectivity' level, or until the 'timeout' expires
+// when 'connectivity' is empty, 'read' is not called
+func WaitForConnectivity(connectivity string, timeout time.Duration, read func() (ipv4Connectivity string, ipv6Connectivity string, err error)) error {
+    if connectivity == "" {
+        return nil
+    }
+
+    pollInterval := 5 * time.Second
+    deadline     := time.Now().Add(timeout)
+
+    for {
+        // errors are expected while the network is reconfiguring, keep the last one for reporting
+        ipv4Connectivity, ipv6Connectivity, err := read()
+        if ( err == nil ) &&
+           ( ( connectivityLevel(ipv4Connectivity) >= connectivityLevel(connectivity) ) ||
+             ( connectivityLevel(ipv6Connectivity) >= connectivityLevel(connectivity) ) ) {
+            return nil
+        }
+
+        if time.Now().Add(pollInterval).After(deadline) {
+            log.Printf("[INFO][terraform-provider-windows/tfutil/WaitForConnectivity()] timeout %s expired\n", timeout)
+            if err != nil {
+                return fmt.Errorf("timeout %s expired waiting for connectivity %q: %s", timeout, connectivity, err)
+            }
+            return fmt.Errorf("timeout %s expired waiting for connectivity %q, last observed ipv4_connectivity %q and ipv6_connectivity %q", timeout, connectivity, ipv4Connectivity, ipv6Connectivity)
+        }
+
+        log.Printf("[INFO][terraform-provider-windows/tfutil/WaitForConnectivity()] waiting for connectivity %q, observed ipv4_connectivity %q and ipv6_connectivity %q, retrying in %s\n", connectivity, ipv4Connectivity, ipv6Connectivity, pollInterval)
+        time.Sleep(pollInterval)
+    }
+}
+
+// WithRollbackProtection calls 'schedule' to schedule a rollback, calls 'update', and calls 'cancel' to cancel the rollback as soon as 'verify' succeeds
+// when 'update' fails, or 'verify' doesn't succeed before the 'window' of the 'rollback_protection' expires, the rollback is left to restore the original values
+// when there is no 'rollback_protection', only 'update' is called
+func WithRollbackProtection(rollbackProtection map[string]interface{}, schedule func(window time.Duration) error, update func() error, verify func() error, cancel func() error) error {
+    if len(rollbackProtection) == 0 {
+        return update()
+    }
+
+    window, _ := time.ParseDuration(rollbackProtection["window"].(string))
+
+    // keep a margin to cancel the rollback before it starts
+    margin := 30 * time.Second
+    if margin > window / 4 {
+        margin = window / 4
+    }
+    pollInterval := 5 * time.Second
+    deadline     := time.Now().Add(window - margin)
+
+    err := schedule(window)
+    if err != nil {
+        return err
+    }
+
+    err = update()
+    if err != nil {
+        // a failed update may be partially applied, leave the rollback to restore the original values
+        log.Printf("[WARNING][terraform-provider-windows/tfutil/WithRollbackProtection()] update failed, keeping rollback: %s\n", err)
+        return fmt.Errorf("%s\nthe update failed, the rollback will restore the original values within %s", err, window)
+    }
+
+    for {
+        err = verify()
+        if err == nil {
+            break
+        }
+
+        if time.Now().Add(pollInterval).After(deadline) {
+            log.Printf("[INFO][terraform-provider-windows/tfutil/WithRollbackProtection()] rollback window %s expired\n", window)
+            return fmt.Errorf("cannot verify after update, the rollback will restore the original values: %s", err)
+        }
+
+        log.Printf("[INFO][terraform-provider-windows/tfutil/WithRollbackProtection()] cannot verify after update, retrying in %s\n", pollInterval)
+        time.Sleep(pollInterval)
+    }
+
+    return cancel()
+}
+
+//------------------------------------------------------------------------------
+
 func GetResource(d *schema.ResourceData, name string) (m map[string]interface{}) {
     if listOfInterfaces1, ok := d.GetOk(name); ok {
         listOfInterfaces2 := listOfInterfaces1.([]interface{})
@@ -276,3 +603,18 @@ func ExpandListOfStrings(d map[string]interface{}, name string) (l []string) {
 }
 
 //------------------------------------------------------------------------------
+
+func GetSetOfStrings(d *schema.ResourceData, name string) (l []string) {
+    if listOfInterfaces1, ok := d.GetOk(name); ok {
+        listOfInterfaces2 := listOfInterfaces1.(*schema.Set).List()
+
+        l = make([]string, len(listOfInterfaces2))
+        for i, s := range listOfInterfaces2 {
+            l[i] = s.(string)
+        }
+    }
+
+    return l
+}
+
+//------------------------------------------------------------------------------